@@ -0,0 +1,96 @@
+// Command fxrates loads currency exchange rates from a local file into the
+// exchange_rates table used to compare salaries across currencies:
+//
+//	go run ./cmd/fxrates -file rates.json
+//
+// Rates already in the table but missing from the file are left as they
+// are. See services.ParseExchangeRates for the accepted formats.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aswinbala005/rizeos/api/internal/config"
+	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/aswinbala005/rizeos/api/internal/services"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func main() {
+	file := flag.String("file", "", "rates file to load (.json or .csv)")
+	source := flag.String("source", "", "where the rates came from, stored with each rate (default: the file name)")
+	dryRun := flag.Bool("dry-run", false, "parse the file and print the rates without saving them")
+	flag.Parse()
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *source == "" {
+		*source = filepath.Base(*file)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("Failed to open rates file: %v", err)
+	}
+	defer f.Close()
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), ".")
+	rates, err := services.ParseExchangeRates(f, format)
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", *file, err)
+	}
+
+	currencies := make([]string, 0, len(rates))
+	for currency := range rates {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	if *dryRun {
+		for _, currency := range currencies {
+			log.Printf("%s %s per USD", currency, rates[currency])
+		}
+		return
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	pool, err := db.ConnectDB(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer pool.Close()
+
+	// All or nothing, so a bad row never leaves a half-updated table
+	ctx := context.Background()
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Fatalf("Failed to start transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+	queries := db.New(tx)
+	for _, currency := range currencies {
+		var rate pgtype.Numeric
+		if err := rate.Scan(rates[currency]); err != nil {
+			log.Fatalf("Invalid rate for %s: %v", currency, err)
+		}
+		if err := queries.UpsertExchangeRate(ctx, db.UpsertExchangeRateParams{
+			Currency:    currency,
+			UnitsPerUsd: rate,
+			Source:      pgtype.Text{String: *source, Valid: true},
+		}); err != nil {
+			log.Fatalf("Failed to save rate for %s: %v", currency, err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		log.Fatalf("Failed to save rates: %v", err)
+	}
+	log.Printf("Loaded %d exchange rates from %s", len(currencies), *file)
+}
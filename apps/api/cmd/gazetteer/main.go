@@ -0,0 +1,95 @@
+// Command gazetteer loads places and their aliases from a local file into
+// the locations table, then re-geocodes jobs and candidates against it:
+//
+//	go run ./cmd/gazetteer -file data/gazetteer/cities.tsv
+//
+// Places already loaded are updated in place and keep the aliases they
+// had. See services.ParseGazetteer for the accepted formats.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/aswinbala005/rizeos/api/internal/config"
+	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/aswinbala005/rizeos/api/internal/services"
+)
+
+func main() {
+	file := flag.String("file", "", "gazetteer file to load (our TSV format or a GeoNames dump)")
+	dryRun := flag.Bool("dry-run", false, "parse the file and print the places without saving them")
+	flag.Parse()
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("Failed to open gazetteer file: %v", err)
+	}
+	defer f.Close()
+	places, err := services.ParseGazetteer(f)
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", *file, err)
+	}
+	if *dryRun {
+		for _, p := range places {
+			log.Printf("%s, %s, %s (%.4f, %.4f) aliases: %d", p.Name, p.Admin1, p.CountryCode, p.Latitude, p.Longitude, len(p.Aliases))
+		}
+		return
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	pool, err := db.ConnectDB(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer pool.Close()
+
+	// All or nothing, so jobs are never geocoded against half a gazetteer
+	ctx := context.Background()
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Fatalf("Failed to start transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+	queries := db.New(tx)
+	for _, p := range places {
+		id, err := queries.UpsertLocation(ctx, db.UpsertLocationParams{
+			Name:        p.Name,
+			CountryCode: p.CountryCode,
+			Admin1:      p.Admin1,
+			Latitude:    p.Latitude,
+			Longitude:   p.Longitude,
+			Population:  p.Population,
+		})
+		if err != nil {
+			log.Fatalf("Failed to save %s, %s: %v", p.Name, p.CountryCode, err)
+		}
+		if err := queries.AddLocationAliases(ctx, db.AddLocationAliasesParams{
+			LocationID: id,
+			Aliases:    append([]string{p.Name}, p.Aliases...),
+		}); err != nil {
+			log.Fatalf("Failed to save aliases of %s, %s: %v", p.Name, p.CountryCode, err)
+		}
+	}
+	jobs, err := queries.GeocodeJobs(ctx)
+	if err != nil {
+		log.Fatalf("Failed to geocode jobs: %v", err)
+	}
+	users, err := queries.GeocodeUsers(ctx)
+	if err != nil {
+		log.Fatalf("Failed to geocode users: %v", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		log.Fatalf("Failed to save places: %v", err)
+	}
+	log.Printf("Loaded %d places from %s; re-geocoded %d jobs and %d users", len(places), *file, jobs, users)
+}
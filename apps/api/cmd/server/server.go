@@ -1,10 +1,13 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -15,6 +18,8 @@ import (
 	"github.com/aswinbala005/rizeos/api/internal/config"
 	"github.com/aswinbala005/rizeos/api/internal/db"
 	"github.com/aswinbala005/rizeos/api/internal/handlers"
+	"github.com/aswinbala005/rizeos/api/internal/services"
+	"github.com/aswinbala005/rizeos/api/internal/workers"
 )
 
 // Server holds all dependencies for our application
@@ -23,6 +28,18 @@ type Server struct {
 	db      *pgxpool.Pool
 	queries *db.Queries
 	router  *fiber.App
+
+	resumeCache *services.ResumeCache
+
+	// Background workers
+	resumeParser *workers.ResumeParser
+	answerGrader *workers.AnswerGrader
+	screener     *workers.ApplicantScreener
+	scheduler    *workers.JobScheduler
+	sweeper      *workers.JobSweeper
+	digest       *workers.JobDigest
+	retention    *workers.ApplicationRetention
+	emailSender  *workers.EmailSender
 }
 
 // NewServer creates a new Server instance
@@ -45,11 +62,23 @@ func NewServer() (*Server, error) {
 	// 4. Create Fiber app
 	app := fiber.New()
 
+	resumeCache := services.NewResumeCache(queries, cfg.ResumeCacheTTL)
+
 	server := &Server{
 		config:  cfg,
 		db:      pool,
 		queries: queries,
 		router:  app,
+
+		resumeCache:  resumeCache,
+		resumeParser: workers.NewResumeParser(queries, resumeCache, cfg.ResumeWorkerConcurrency),
+		answerGrader: workers.NewAnswerGrader(queries, services.LLMAnswerGrader{}),
+		screener:     workers.NewApplicantScreener(queries, services.LLMApplicantScreener{}),
+		scheduler:    workers.NewJobScheduler(queries),
+		sweeper:      workers.NewJobSweeper(queries, cfg.JobMaxAgeDays, cfg.JobInactivityDays, cfg.JobExpiryWarningDays),
+		digest:       workers.NewJobDigest(queries, cfg.JobDigestDays),
+		retention:    workers.NewApplicationRetention(queries, cfg.ApplicationRetentionDays),
+		emailSender:  workers.NewEmailSender(queries, services.NewMailer()),
 	}
 
 	server.setupMiddleware()
@@ -83,9 +112,22 @@ func (s *Server) setupRoutes() {
 
 	// --- Initialize Handlers ---
 	userHandler := handlers.NewUserHandler(s.queries)
-	jobHandler := handlers.NewJobHandler(s.queries)
-	appHandler := handlers.NewApplicationHandler(s.queries)
-	resumeHandler := handlers.NewResumeHandler()
+	jobHandler := handlers.NewJobHandler(s.queries, s.db, s.scheduler)
+	appHandler := handlers.NewApplicationHandler(s.queries, s.db, s.answerGrader, s.screener, s.emailSender, services.ReapplyPolicy{
+		AfterRejection:  time.Duration(s.config.ReapplyAfterRejectionDays) * 24 * time.Hour,
+		AfterWithdrawal: time.Duration(s.config.ReapplyAfterWithdrawalDays) * 24 * time.Hour,
+	})
+	historyHandler := handlers.NewWorkHistoryHandler(s.queries, s.db)
+	mergeHandler := handlers.NewProfileMergeHandler(s.queries, s.db)
+	screeningHandler := handlers.NewScreeningHandler(s.queries, s.db)
+	reviewHandler := handlers.NewApplicationReviewHandler(s.queries, s.db)
+	interviewHandler := handlers.NewInterviewHandler(s.queries, s.db, s.emailSender, s.config.PublicSiteURL)
+	notificationHandler := handlers.NewNotificationHandler(s.queries)
+	publicJobHandler := handlers.NewPublicJobHandler(s.queries, s.config.PublicSiteURL)
+	locationHandler := handlers.NewLocationHandler(s.queries)
+	preferencesHandler := handlers.NewJobPreferencesHandler(s.queries)
+	jobParserHandler := handlers.NewJobParserHandler(services.JobDescriptionParser{})
+	resumeHandler := handlers.NewResumeHandler(s.queries, s.resumeParser, s.resumeCache, s.config.ResumeJobMaxAttempts, s.config.ResumeParser)
 
 	// --- User Routes ---
 	api.Post("/users", userHandler.CreateUser)
@@ -93,25 +135,113 @@ func (s *Server) setupRoutes() {
 	api.Get("/users/:email", userHandler.GetUser) // Accepts Email OR Wallet
 	api.Put("/users/:id", userHandler.UpdateUser)
 	api.Get("/candidates/search", userHandler.SearchCandidates) // <-- NEW: Archer
+	api.Get("/candidates/query", userHandler.QueryCandidates)
+	api.Get("/users/:id/notifications", notificationHandler.ListNotifications)
+	api.Put("/users/:id/notifications/read", notificationHandler.MarkAllNotificationsRead)
+	api.Put("/users/:id/notifications/:notificationId/read", notificationHandler.MarkNotificationRead)
+	api.Get("/users/:id/job-preferences", preferencesHandler.GetJobPreferences)
+	api.Put("/users/:id/job-preferences", preferencesHandler.UpdateJobPreferences)
+	api.Delete("/users/:id/job-preferences", preferencesHandler.DeleteJobPreferences)
+	api.Get("/users/:id/saved-searches", preferencesHandler.ListSavedSearches)
+	api.Post("/users/:id/saved-searches", preferencesHandler.CreateSavedSearch)
+	api.Put("/users/:id/saved-searches/:searchId", preferencesHandler.UpdateSavedSearch)
+	api.Delete("/users/:id/saved-searches/:searchId", preferencesHandler.DeleteSavedSearch)
+
+	// --- Work History Routes ---
+	api.Get("/users/:id/work-positions", historyHandler.ListWorkPositions)
+	api.Post("/users/:id/work-positions", historyHandler.CreateWorkPosition)
+	api.Put("/users/:id/work-positions", historyHandler.ReplaceWorkPositions)
+	api.Put("/users/:id/work-positions/:positionId", historyHandler.UpdateWorkPosition)
+	api.Delete("/users/:id/work-positions/:positionId", historyHandler.DeleteWorkPosition)
+	api.Get("/users/:id/education-entries", historyHandler.ListEducationEntries)
+	api.Post("/users/:id/education-entries", historyHandler.CreateEducationEntry)
+	api.Put("/users/:id/education-entries", historyHandler.ReplaceEducationEntries)
+	api.Put("/users/:id/education-entries/:entryId", historyHandler.UpdateEducationEntry)
+	api.Delete("/users/:id/education-entries/:entryId", historyHandler.DeleteEducationEntry)
 
 	// --- Job Routes ---
 	api.Post("/jobs", jobHandler.CreateJob)
 	api.Get("/jobs", jobHandler.ListJobs)
+	api.Get("/jobs/search", jobHandler.SearchJobs)
+	api.Post("/jobs/import", jobHandler.ImportJobs)
+	api.Get("/exchange-rates", jobHandler.ListExchangeRates)
+	api.Get("/locations", locationHandler.SearchLocations)
+	api.Get("/jobs/:id", jobHandler.GetJob)
+	api.Put("/jobs/:id", jobHandler.UpdateJob)
+	api.Get("/jobs/:id/revisions", jobHandler.GetJobRevisions)
 	api.Get("/jobs/recruiter/:id", jobHandler.ListJobsByRecruiter) // <-- NEW ROUTE
 	api.Get("/jobs/:id/applications", appHandler.GetJobApplications)
 	api.Get("/jobs/recruiter/:id/volume", appHandler.GetApplicationVolume) // <-- NEW ROUTE: Real-time Chart Data
 	api.Get("/applications/recruiter/:id", appHandler.GetRecruiterApplications) // <-- NEW: Thena
+	api.Get("/applications/recruiter/:id/screenings", appHandler.GetRecruiterScreenings)
+	api.Post("/jobs/:id/screenings", appHandler.RescreenJob)
 	api.Put("/jobs/:id/close", jobHandler.CloseJob)
 	api.Put("/jobs/:id/reopen", jobHandler.ReopenJob)
+	api.Post("/jobs/:id/extend", jobHandler.ExtendJob)
+	api.Get("/jobs/recruiter/:id/events", jobHandler.ListJobEvents)
+	api.Get("/jobs/recruiter/:id/export", jobHandler.ExportJobs)
+	api.Get("/jobs/:id/questions", screeningHandler.GetJobQuestions)
+	api.Put("/jobs/:id/questions", screeningHandler.PutJobQuestions)
+	api.Get("/jobs/:id/scorecard-criteria", reviewHandler.GetScorecardCriteria)
+	api.Put("/jobs/:id/scorecard-criteria", reviewHandler.PutScorecardCriteria)
 	api.Get("/jobs/recruiter/:id/stats", jobHandler.GetDashboardStats) // <-- NEW ROUTE
 
+	// --- Public Routes (no login; for job pages and crawlers) ---
+	api.Get("/public/jobs/:id", publicJobHandler.GetPublicJob)
+	api.Get("/public/sitemap.xml", publicJobHandler.Sitemap)
+	api.Get("/public/feeds/jobs.rss", publicJobHandler.RSSFeed)
+	api.Get("/public/feeds/jobs.atom", publicJobHandler.AtomFeed)
+	api.Get("/public/feeds/indeed.xml", publicJobHandler.IndeedFeed)
+
 	// --- Application Routes ---
 	api.Post("/applications", appHandler.ApplyToJob)
 	api.Get("/applications/:id", appHandler.GetMyApplications)
-	api.Delete("/applications/:id", appHandler.WithdrawApplication)
+	api.Delete("/applications/:id", appHandler.WithdrawApplication) // withdraws; the application is kept
+	api.Post("/applications/:id/withdraw", appHandler.WithdrawApplication)
+	api.Get("/applications/:id/answers", screeningHandler.GetApplicationAnswers)
+
+	// --- Application Review Routes (recruiters only) ---
+	api.Get("/applications/:id/notes", reviewHandler.ListNotes)
+	api.Post("/applications/:id/notes", reviewHandler.CreateNote)
+	api.Delete("/applications/:id/notes/:noteId", reviewHandler.DeleteNote)
+	api.Get("/applications/:id/tags", reviewHandler.ListTags)
+	api.Put("/applications/:id/tags", reviewHandler.SetTags)
+	api.Get("/applications/:id/scorecards", reviewHandler.ListScorecards)
+	api.Put("/applications/:id/scorecards", reviewHandler.SubmitScorecard)
+
+	// --- Interview Routes ---
+	api.Get("/applications/:id/interviews", interviewHandler.ListInterviews)
+	api.Post("/applications/:id/interviews", interviewHandler.CreateInterview)
+	api.Get("/interviews/:id", interviewHandler.GetInterview)
+	api.Put("/interviews/:id", interviewHandler.ReviseInterview)
+	api.Get("/interviews/:id/invite.ics", interviewHandler.GetInvite)
+	api.Post("/interviews/:id/select", interviewHandler.SelectSlot)
+	api.Post("/interviews/:id/cancel", interviewHandler.CancelInterview)
 
 	// --- AI Routes ---
 	api.Post("/parse-resume", resumeHandler.ParseResume)
+	api.Get("/parse-resume/:jobId", resumeHandler.GetParseJob)
+	api.Get("/parse-resume/:jobId/events", resumeHandler.StreamParseJob)
+	api.Post("/parse-job-description", jobParserHandler.ParseJobDescription)
+	api.Post("/users/:id/resume-merge/preview", mergeHandler.PreviewResumeMerge)
+	api.Post("/users/:id/resume-merge/apply", mergeHandler.ApplyResumeMerge)
+
+	// --- Monitoring Routes ---
+	api.Get("/metrics/resume-cache", resumeHandler.GetCacheStats)
+}
+
+// startWorkers launches the background workers and returns a WaitGroup
+// that completes once all of them have stopped
+func (s *Server) startWorkers(ctx context.Context) *sync.WaitGroup {
+	var wg sync.WaitGroup
+	for _, w := range []workers.Worker{s.resumeParser, s.answerGrader, s.screener, s.scheduler, s.sweeper, s.digest, s.retention, s.emailSender} {
+		wg.Add(1)
+		go func(w workers.Worker) {
+			defer wg.Done()
+			w.Run(ctx)
+		}(w)
+	}
+	return &wg
 }
 
 // Start runs the HTTP server with graceful shutdown
@@ -120,6 +250,10 @@ func (s *Server) Start() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 
+	// Start background workers
+	ctx, stopWorkers := context.WithCancel(context.Background())
+	workersDone := s.startWorkers(ctx)
+
 	// Run the server in a separate goroutine so it doesn't block
 	go func() {
 		log.Printf("Server starting on port %s", s.config.Port)
@@ -132,6 +266,11 @@ func (s *Server) Start() {
 	<-quit
 	log.Println("Shutting down server...")
 
+	// Let in-flight background jobs stop before the pool goes away
+	stopWorkers()
+	workersDone.Wait()
+	log.Println("Background workers stopped.")
+
 	// Close the database connection pool
 	s.db.Close()
 	log.Println("Database connection closed.")
@@ -3,6 +3,8 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -11,6 +13,33 @@ import (
 type Config struct {
     Port        string
     DatabaseURL string
+
+    // Resume parsing queue
+    ResumeWorkerConcurrency int
+    ResumeJobMaxAttempts    int
+    ResumeCacheTTL          time.Duration
+    ResumeParser            string // auto, llm or heuristic
+
+    // Stale-job sweeper, in days; a window of 0 is disabled
+    JobMaxAgeDays        int
+    JobInactivityDays    int
+    JobExpiryWarningDays int
+
+    // Days between job alerts for one saved search or preference digest;
+    // 0 turns alerts off
+    JobDigestDays int
+
+    // Days a candidate waits to re-apply to a job after their application
+    // was rejected or withdrawn; 0 lets them re-apply at once
+    ReapplyAfterRejectionDays  int
+    ReapplyAfterWithdrawalDays int
+
+    // Days withdrawn applications are kept before being deleted; 0 keeps
+    // them forever
+    ApplicationRetentionDays int
+
+    // Base URL of the public site, for job links in JSON-LD and the sitemap
+    PublicSiteURL string
 }
 
 // LoadConfig loads application configuration from environment variables
@@ -23,6 +52,22 @@ func LoadConfig() (*Config, error) {
     cfg := &Config{
         Port:        os.Getenv("PORT"),
         DatabaseURL: os.Getenv("DATABASE_URL"),
+
+        ResumeWorkerConcurrency: getEnvInt("RESUME_WORKER_CONCURRENCY", 2),
+        ResumeJobMaxAttempts:    getEnvInt("RESUME_JOB_MAX_ATTEMPTS", 3),
+        ResumeCacheTTL:          time.Duration(getEnvInt("RESUME_CACHE_TTL_HOURS", 168)) * time.Hour,
+        ResumeParser:            os.Getenv("RESUME_PARSER"),
+
+        JobMaxAgeDays:        getEnvDays("JOB_MAX_AGE_DAYS", 60),
+        JobInactivityDays:    getEnvDays("JOB_INACTIVITY_DAYS", 30),
+        JobExpiryWarningDays: getEnvDays("JOB_EXPIRY_WARNING_DAYS", 7),
+        JobDigestDays:        getEnvDays("JOB_DIGEST_DAYS", 1),
+
+        ReapplyAfterRejectionDays:  getEnvDays("REAPPLY_AFTER_REJECTION_DAYS", 90),
+        ReapplyAfterWithdrawalDays: getEnvDays("REAPPLY_AFTER_WITHDRAWAL_DAYS", 7),
+        ApplicationRetentionDays:   getEnvDays("APPLICATION_RETENTION_DAYS", 0),
+
+        PublicSiteURL: os.Getenv("PUBLIC_SITE_URL"),
     }
 
     // Set default port if not specified
@@ -30,5 +75,37 @@ func LoadConfig() (*Config, error) {
         cfg.Port = "8080"
     }
 
+    if cfg.ResumeParser == "" {
+        cfg.ResumeParser = "auto"
+    }
+
     return cfg, nil
-}
\ No newline at end of file
+}
+
+// getEnvInt reads a positive integer from the environment, falling back to def
+func getEnvInt(key string, def int) int {
+    raw := os.Getenv(key)
+    if raw == "" {
+        return def
+    }
+    v, err := strconv.Atoi(raw)
+    if err != nil || v <= 0 {
+        log.Printf("Invalid value for %s (%q), using default %d", key, raw, def)
+        return def
+    }
+    return v
+}
+
+// getEnvDays is getEnvInt for a number of days, where 0 is allowed
+func getEnvDays(key string, def int) int {
+    raw := os.Getenv(key)
+    if raw == "" {
+        return def
+    }
+    v, err := strconv.Atoi(raw)
+    if err != nil || v < 0 {
+        log.Printf("Invalid value for %s (%q), using default %d", key, raw, def)
+        return def
+    }
+    return v
+}
@@ -13,11 +13,13 @@ import (
 
 const createApplication = `-- name: CreateApplication :one
 INSERT INTO applications (
-  job_id, candidate_id, status, match_score, gateway_answer
+  job_id, candidate_id, status, match_score, gateway_answer, job_revision
 ) VALUES (
-  $1, $2, $3, $4, $5
+  $1, $2, $3, $4, $5,
+  (SELECT MAX(revision) FROM job_revisions WHERE job_id = $1)
 )
-RETURNING id, job_id, candidate_id, status, match_score, gateway_answer, created_at, updated_at
+ON CONFLICT (job_id, candidate_id) DO NOTHING
+RETURNING id, job_id, candidate_id, status, match_score, gateway_answer, created_at, updated_at, gateway_grade, rejection_reason, job_revision, closed_at, reapplied_at, reapply_count, withdrawn_at, withdrawal_reason
 `
 
 type CreateApplicationParams struct {
@@ -28,6 +30,8 @@ type CreateApplicationParams struct {
 	GatewayAnswer pgtype.Text `json:"gateway_answer"`
 }
 
+// job_revision pins the version of the job the candidate applied to. No
+// row comes back if the candidate has already applied.
 func (q *Queries) CreateApplication(ctx context.Context, arg CreateApplicationParams) (Application, error) {
 	row := q.db.QueryRow(ctx, createApplication,
 		arg.JobID,
@@ -46,19 +50,18 @@ func (q *Queries) CreateApplication(ctx context.Context, arg CreateApplicationPa
 		&i.GatewayAnswer,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.GatewayGrade,
+		&i.RejectionReason,
+		&i.JobRevision,
+		&i.ClosedAt,
+		&i.ReappliedAt,
+		&i.ReapplyCount,
+		&i.WithdrawnAt,
+		&i.WithdrawalReason,
 	)
 	return i, err
 }
 
-const deleteApplication = `-- name: DeleteApplication :exec
-DELETE FROM applications WHERE id = $1
-`
-
-func (q *Queries) DeleteApplication(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteApplication, id)
-	return err
-}
-
 const getAllApplicationsByRecruiter = `-- name: GetAllApplicationsByRecruiter :many
 SELECT 
     a.id, 
@@ -66,6 +69,10 @@ SELECT
     a.created_at, 
     a.match_score,
     a.gateway_answer,
+    a.gateway_grade,
+    a.rejection_reason,
+    a.withdrawn_at,
+    a.withdrawal_reason,
     a.job_id, -- <--- ADDED THIS
     j.title as job_title,
     u.id as candidate_id,
@@ -88,6 +95,10 @@ type GetAllApplicationsByRecruiterRow struct {
 	CreatedAt           pgtype.Timestamptz `json:"created_at"`
 	MatchScore          pgtype.Int4        `json:"match_score"`
 	GatewayAnswer       pgtype.Text        `json:"gateway_answer"`
+	GatewayGrade        pgtype.Int4        `json:"gateway_grade"`
+	RejectionReason     pgtype.Text        `json:"rejection_reason"`
+	WithdrawnAt         pgtype.Timestamptz `json:"withdrawn_at"`
+	WithdrawalReason    pgtype.Text        `json:"withdrawal_reason"`
 	JobID               pgtype.UUID        `json:"job_id"`
 	JobTitle            string             `json:"job_title"`
 	CandidateID         pgtype.UUID        `json:"candidate_id"`
@@ -114,6 +125,10 @@ func (q *Queries) GetAllApplicationsByRecruiter(ctx context.Context, recruiterID
 			&i.CreatedAt,
 			&i.MatchScore,
 			&i.GatewayAnswer,
+			&i.GatewayGrade,
+			&i.RejectionReason,
+			&i.WithdrawnAt,
+			&i.WithdrawalReason,
 			&i.JobID,
 			&i.JobTitle,
 			&i.CandidateID,
@@ -134,15 +149,43 @@ func (q *Queries) GetAllApplicationsByRecruiter(ctx context.Context, recruiterID
 	return items, nil
 }
 
+const getApplicationByID = `-- name: GetApplicationByID :one
+SELECT id, job_id, candidate_id, status, match_score, gateway_answer, created_at, updated_at, gateway_grade, rejection_reason, job_revision, closed_at, reapplied_at, reapply_count, withdrawn_at, withdrawal_reason FROM applications WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetApplicationByID(ctx context.Context, id pgtype.UUID) (Application, error) {
+	row := q.db.QueryRow(ctx, getApplicationByID, id)
+	var i Application
+	err := row.Scan(
+		&i.ID,
+		&i.JobID,
+		&i.CandidateID,
+		&i.Status,
+		&i.MatchScore,
+		&i.GatewayAnswer,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.GatewayGrade,
+		&i.RejectionReason,
+		&i.JobRevision,
+		&i.ClosedAt,
+		&i.ReappliedAt,
+		&i.ReapplyCount,
+		&i.WithdrawnAt,
+		&i.WithdrawalReason,
+	)
+	return i, err
+}
+
 const getApplicationVolumeByRecruiter = `-- name: GetApplicationVolumeByRecruiter :many
 SELECT 
-    DATE(a.created_at)::text as application_date,
+    DATE(COALESCE(a.reapplied_at, a.created_at))::text as application_date,
     COUNT(*) as total
 FROM applications a
 JOIN jobs j ON a.job_id = j.id
 WHERE j.recruiter_id = $1
-  AND EXTRACT(MONTH FROM a.created_at) = EXTRACT(MONTH FROM CURRENT_DATE)
-  AND EXTRACT(YEAR FROM a.created_at) = EXTRACT(YEAR FROM CURRENT_DATE)
+  AND EXTRACT(MONTH FROM COALESCE(a.reapplied_at, a.created_at)) = EXTRACT(MONTH FROM CURRENT_DATE)
+  AND EXTRACT(YEAR FROM COALESCE(a.reapplied_at, a.created_at)) = EXTRACT(YEAR FROM CURRENT_DATE)
 GROUP BY application_date
 ORDER BY application_date ASC
 `
@@ -152,6 +195,8 @@ type GetApplicationVolumeByRecruiterRow struct {
 	Total           int64  `json:"total"`
 }
 
+// A re-application counts on the day it was made, not the day of the
+// original application
 func (q *Queries) GetApplicationVolumeByRecruiter(ctx context.Context, recruiterID pgtype.UUID) ([]GetApplicationVolumeByRecruiterRow, error) {
 	rows, err := q.db.Query(ctx, getApplicationVolumeByRecruiter, recruiterID)
 	if err != nil {
@@ -178,6 +223,8 @@ SELECT
     a.status, 
     a.created_at, 
     a.match_score,
+    a.job_id,
+    a.job_revision,
     j.title as job_title, 
     j.description as job_description,
     j.location_city,
@@ -199,6 +246,8 @@ type GetApplicationsByCandidateRow struct {
 	Status         string             `json:"status"`
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
 	MatchScore     pgtype.Int4        `json:"match_score"`
+	JobID          pgtype.UUID        `json:"job_id"`
+	JobRevision    pgtype.Int4        `json:"job_revision"`
 	JobTitle       string             `json:"job_title"`
 	JobDescription string             `json:"job_description"`
 	LocationCity   pgtype.Text        `json:"location_city"`
@@ -224,6 +273,8 @@ func (q *Queries) GetApplicationsByCandidate(ctx context.Context, candidateID pg
 			&i.Status,
 			&i.CreatedAt,
 			&i.MatchScore,
+			&i.JobID,
+			&i.JobRevision,
 			&i.JobTitle,
 			&i.JobDescription,
 			&i.LocationCity,
@@ -251,6 +302,11 @@ SELECT
     a.created_at, 
     a.match_score,
     a.gateway_answer,
+    a.gateway_grade,
+    a.rejection_reason,
+    a.job_revision,
+    a.withdrawn_at,
+    a.withdrawal_reason,
     u.full_name as candidate_name,
     u.email as candidate_email,
     u.job_role as candidate_role,
@@ -268,6 +324,11 @@ type GetApplicationsByJobRow struct {
 	CreatedAt           pgtype.Timestamptz `json:"created_at"`
 	MatchScore          pgtype.Int4        `json:"match_score"`
 	GatewayAnswer       pgtype.Text        `json:"gateway_answer"`
+	GatewayGrade        pgtype.Int4        `json:"gateway_grade"`
+	RejectionReason     pgtype.Text        `json:"rejection_reason"`
+	JobRevision         pgtype.Int4        `json:"job_revision"`
+	WithdrawnAt         pgtype.Timestamptz `json:"withdrawn_at"`
+	WithdrawalReason    pgtype.Text        `json:"withdrawal_reason"`
 	CandidateName       pgtype.Text        `json:"candidate_name"`
 	CandidateEmail      pgtype.Text        `json:"candidate_email"`
 	CandidateRole       pgtype.Text        `json:"candidate_role"`
@@ -290,6 +351,11 @@ func (q *Queries) GetApplicationsByJob(ctx context.Context, jobID pgtype.UUID) (
 			&i.CreatedAt,
 			&i.MatchScore,
 			&i.GatewayAnswer,
+			&i.GatewayGrade,
+			&i.RejectionReason,
+			&i.JobRevision,
+			&i.WithdrawnAt,
+			&i.WithdrawalReason,
 			&i.CandidateName,
 			&i.CandidateEmail,
 			&i.CandidateRole,
@@ -305,3 +371,139 @@ func (q *Queries) GetApplicationsByJob(ctx context.Context, jobID pgtype.UUID) (
 	}
 	return items, nil
 }
+
+const getCandidateApplication = `-- name: GetCandidateApplication :one
+SELECT id, job_id, candidate_id, status, match_score, gateway_answer, created_at, updated_at, gateway_grade, rejection_reason, job_revision, closed_at, reapplied_at, reapply_count, withdrawn_at, withdrawal_reason FROM applications WHERE job_id = $1 AND candidate_id = $2 LIMIT 1
+`
+
+type GetCandidateApplicationParams struct {
+	JobID       pgtype.UUID `json:"job_id"`
+	CandidateID pgtype.UUID `json:"candidate_id"`
+}
+
+func (q *Queries) GetCandidateApplication(ctx context.Context, arg GetCandidateApplicationParams) (Application, error) {
+	row := q.db.QueryRow(ctx, getCandidateApplication, arg.JobID, arg.CandidateID)
+	var i Application
+	err := row.Scan(
+		&i.ID,
+		&i.JobID,
+		&i.CandidateID,
+		&i.Status,
+		&i.MatchScore,
+		&i.GatewayAnswer,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.GatewayGrade,
+		&i.RejectionReason,
+		&i.JobRevision,
+		&i.ClosedAt,
+		&i.ReappliedAt,
+		&i.ReapplyCount,
+		&i.WithdrawnAt,
+		&i.WithdrawalReason,
+	)
+	return i, err
+}
+
+const purgeWithdrawnApplications = `-- name: PurgeWithdrawnApplications :execrows
+DELETE FROM applications
+WHERE status = 'WITHDRAWN' AND withdrawn_at < $1
+`
+
+// Data retention: withdrawn applications are deleted for good, with their
+// answers and screenings, once they are old enough. Nothing else deletes
+// applications.
+func (q *Queries) PurgeWithdrawnApplications(ctx context.Context, withdrawnAt pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeWithdrawnApplications, withdrawnAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const reopenApplication = `-- name: ReopenApplication :one
+UPDATE applications
+SET status = $2, match_score = $3, gateway_answer = $4, gateway_grade = NULL,
+    rejection_reason = NULL, closed_at = NULL, withdrawn_at = NULL, withdrawal_reason = NULL,
+    job_revision = (SELECT MAX(revision) FROM job_revisions WHERE job_id = applications.job_id),
+    reapplied_at = NOW(), reapply_count = reapply_count + 1, updated_at = NOW()
+WHERE id = $1 AND closed_at IS NOT NULL
+RETURNING id, job_id, candidate_id, status, match_score, gateway_answer, created_at, updated_at, gateway_grade, rejection_reason, job_revision, closed_at, reapplied_at, reapply_count, withdrawn_at, withdrawal_reason
+`
+
+type ReopenApplicationParams struct {
+	ID            pgtype.UUID `json:"id"`
+	Status        string      `json:"status"`
+	MatchScore    pgtype.Int4 `json:"match_score"`
+	GatewayAnswer pgtype.Text `json:"gateway_answer"`
+}
+
+// Re-applying to a job: a rejected or withdrawn application starts over
+// against the job's current revision. No row comes back if it is not
+// closed any more.
+func (q *Queries) ReopenApplication(ctx context.Context, arg ReopenApplicationParams) (Application, error) {
+	row := q.db.QueryRow(ctx, reopenApplication,
+		arg.ID,
+		arg.Status,
+		arg.MatchScore,
+		arg.GatewayAnswer,
+	)
+	var i Application
+	err := row.Scan(
+		&i.ID,
+		&i.JobID,
+		&i.CandidateID,
+		&i.Status,
+		&i.MatchScore,
+		&i.GatewayAnswer,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.GatewayGrade,
+		&i.RejectionReason,
+		&i.JobRevision,
+		&i.ClosedAt,
+		&i.ReappliedAt,
+		&i.ReapplyCount,
+		&i.WithdrawnAt,
+		&i.WithdrawalReason,
+	)
+	return i, err
+}
+
+const withdrawApplication = `-- name: WithdrawApplication :one
+UPDATE applications
+SET status = 'WITHDRAWN', withdrawal_reason = $2, withdrawn_at = NOW(),
+    closed_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND status NOT IN ('REJECTED', 'WITHDRAWN')
+RETURNING id, job_id, candidate_id, status, match_score, gateway_answer, created_at, updated_at, gateway_grade, rejection_reason, job_revision, closed_at, reapplied_at, reapply_count, withdrawn_at, withdrawal_reason
+`
+
+type WithdrawApplicationParams struct {
+	ID               pgtype.UUID `json:"id"`
+	WithdrawalReason pgtype.Text `json:"withdrawal_reason"`
+}
+
+// No row comes back if the application is already rejected or withdrawn
+func (q *Queries) WithdrawApplication(ctx context.Context, arg WithdrawApplicationParams) (Application, error) {
+	row := q.db.QueryRow(ctx, withdrawApplication, arg.ID, arg.WithdrawalReason)
+	var i Application
+	err := row.Scan(
+		&i.ID,
+		&i.JobID,
+		&i.CandidateID,
+		&i.Status,
+		&i.MatchScore,
+		&i.GatewayAnswer,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.GatewayGrade,
+		&i.RejectionReason,
+		&i.JobRevision,
+		&i.ClosedAt,
+		&i.ReappliedAt,
+		&i.ReapplyCount,
+		&i.WithdrawnAt,
+		&i.WithdrawalReason,
+	)
+	return i, err
+}
@@ -11,13 +11,28 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const closeJob = `-- name: CloseJob :exec
-UPDATE jobs SET status = 'CLOSED' WHERE id = $1
+const closeJob = `-- name: CloseJob :one
+WITH closed AS (
+  UPDATE jobs SET status = 'CLOSED', close_reason = 'MANUAL', updated_at = NOW(), updated_by = $1::uuid
+  WHERE id = $2 AND status = 'OPEN'
+  RETURNING id
+), events AS (
+  INSERT INTO job_events (job_id, kind, reason, actor_id)
+  SELECT id, 'CLOSED', 'MANUAL', $1::uuid FROM closed
+)
+SELECT id FROM closed
 `
 
-func (q *Queries) CloseJob(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, closeJob, id)
-	return err
+type CloseJobParams struct {
+	ActorID pgtype.UUID `json:"actor_id"`
+	ID      pgtype.UUID `json:"id"`
+}
+
+func (q *Queries) CloseJob(ctx context.Context, arg CloseJobParams) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, closeJob, arg.ActorID, arg.ID)
+	var id pgtype.UUID
+	err := row.Scan(&id)
+	return id, err
 }
 
 const createJob = `-- name: CreateJob :one
@@ -26,31 +41,35 @@ INSERT INTO jobs (
   job_type, location_type, location_city, salary_min, salary_max, currency,
   experience_min, experience_max,
   job_summary, education_requirements, skills_requirements, is_unpaid,
-  recruiter_email
+  recruiter_email, status, publish_at, expires_at, pay_period
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21
 )
-RETURNING id, recruiter_id, title, description, is_paid, created_at, updated_at
+RETURNING id, recruiter_id, title, description, is_paid, created_at, updated_at, status, publish_at, expires_at
 `
 
 type CreateJobParams struct {
-	RecruiterID           pgtype.UUID `json:"recruiter_id"`
-	Title                 string      `json:"title"`
-	Description           string      `json:"description"`
-	IsPaid                pgtype.Bool `json:"is_paid"`
-	JobType               pgtype.Text `json:"job_type"`
-	LocationType          pgtype.Text `json:"location_type"`
-	LocationCity          pgtype.Text `json:"location_city"`
-	SalaryMin             pgtype.Int4 `json:"salary_min"`
-	SalaryMax             pgtype.Int4 `json:"salary_max"`
-	Currency              pgtype.Text `json:"currency"`
-	ExperienceMin         pgtype.Int4 `json:"experience_min"`
-	ExperienceMax         pgtype.Int4 `json:"experience_max"`
-	JobSummary            pgtype.Text `json:"job_summary"`
-	EducationRequirements pgtype.Text `json:"education_requirements"`
-	SkillsRequirements    pgtype.Text `json:"skills_requirements"`
-	IsUnpaid              pgtype.Bool `json:"is_unpaid"`
-	RecruiterEmail        pgtype.Text `json:"recruiter_email"`
+	RecruiterID           pgtype.UUID        `json:"recruiter_id"`
+	Title                 string             `json:"title"`
+	Description           string             `json:"description"`
+	IsPaid                pgtype.Bool        `json:"is_paid"`
+	JobType               pgtype.Text        `json:"job_type"`
+	LocationType          pgtype.Text        `json:"location_type"`
+	LocationCity          pgtype.Text        `json:"location_city"`
+	SalaryMin             pgtype.Int4        `json:"salary_min"`
+	SalaryMax             pgtype.Int4        `json:"salary_max"`
+	Currency              pgtype.Text        `json:"currency"`
+	ExperienceMin         pgtype.Int4        `json:"experience_min"`
+	ExperienceMax         pgtype.Int4        `json:"experience_max"`
+	JobSummary            pgtype.Text        `json:"job_summary"`
+	EducationRequirements pgtype.Text        `json:"education_requirements"`
+	SkillsRequirements    pgtype.Text        `json:"skills_requirements"`
+	IsUnpaid              pgtype.Bool        `json:"is_unpaid"`
+	RecruiterEmail        pgtype.Text        `json:"recruiter_email"`
+	Status                pgtype.Text        `json:"status"`
+	PublishAt             pgtype.Timestamptz `json:"publish_at"`
+	ExpiresAt             pgtype.Timestamptz `json:"expires_at"`
+	PayPeriod             string             `json:"pay_period"`
 }
 
 type CreateJobRow struct {
@@ -61,6 +80,9 @@ type CreateJobRow struct {
 	IsPaid      pgtype.Bool        `json:"is_paid"`
 	CreatedAt   pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	Status      pgtype.Text        `json:"status"`
+	PublishAt   pgtype.Timestamptz `json:"publish_at"`
+	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
 }
 
 func (q *Queries) CreateJob(ctx context.Context, arg CreateJobParams) (CreateJobRow, error) {
@@ -82,6 +104,10 @@ func (q *Queries) CreateJob(ctx context.Context, arg CreateJobParams) (CreateJob
 		arg.SkillsRequirements,
 		arg.IsUnpaid,
 		arg.RecruiterEmail,
+		arg.Status,
+		arg.PublishAt,
+		arg.ExpiresAt,
+		arg.PayPeriod,
 	)
 	var i CreateJobRow
 	err := row.Scan(
@@ -92,10 +118,61 @@ func (q *Queries) CreateJob(ctx context.Context, arg CreateJobParams) (CreateJob
 		&i.IsPaid,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.PublishAt,
+		&i.ExpiresAt,
 	)
 	return i, err
 }
 
+const expireJobs = `-- name: ExpireJobs :execrows
+WITH expired AS (
+  UPDATE jobs SET status = 'CLOSED', close_reason = 'EXPIRED', updated_at = NOW(), updated_by = NULL
+  WHERE status = 'OPEN' AND expires_at <= NOW()
+  RETURNING id
+)
+INSERT INTO job_events (job_id, kind, reason)
+SELECT id, 'AUTO_CLOSED', 'EXPIRED' FROM expired
+`
+
+func (q *Queries) ExpireJobs(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, expireJobs)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getFeedVersion = `-- name: GetFeedVersion :one
+SELECT
+  COUNT(*) FILTER (WHERE j.status = 'OPEN') AS open_jobs,
+  MAX(j.updated_at)::timestamptz AS last_modified
+FROM jobs j
+JOIN users u ON j.recruiter_id = u.id
+WHERE ($1::uuid IS NULL OR j.recruiter_id = $1)
+  AND ($2::text IS NULL OR lower(u.organization_name) = lower($2))
+`
+
+type GetFeedVersionParams struct {
+	RecruiterID  pgtype.UUID `json:"recruiter_id"`
+	Organization pgtype.Text `json:"organization"`
+}
+
+type GetFeedVersionRow struct {
+	OpenJobs     int64              `json:"open_jobs"`
+	LastModified pgtype.Timestamptz `json:"last_modified"`
+}
+
+// Moves whenever a job in a feed with the same filters is posted, edited,
+// opened or closed (closing bumps updated_at too), so cached feeds are
+// regenerated only when they would change.
+func (q *Queries) GetFeedVersion(ctx context.Context, arg GetFeedVersionParams) (GetFeedVersionRow, error) {
+	row := q.db.QueryRow(ctx, getFeedVersion, arg.RecruiterID, arg.Organization)
+	var i GetFeedVersionRow
+	err := row.Scan(&i.OpenJobs, &i.LastModified)
+	return i, err
+}
+
 const getJobApplicationCounts = `-- name: GetJobApplicationCounts :many
 SELECT 
     j.id,
@@ -103,7 +180,7 @@ SELECT
     j.status,
     COUNT(a.id)::int as applicant_count
 FROM jobs j
-LEFT JOIN applications a ON j.id = a.job_id
+LEFT JOIN applications a ON j.id = a.job_id AND a.status <> 'WITHDRAWN'
 WHERE j.recruiter_id = $1
 GROUP BY j.id
 ORDER BY applicant_count DESC
@@ -116,6 +193,7 @@ type GetJobApplicationCountsRow struct {
 	ApplicantCount int32       `json:"applicant_count"`
 }
 
+// Withdrawn applications are not applicants any more
 func (q *Queries) GetJobApplicationCounts(ctx context.Context, recruiterID pgtype.UUID) ([]GetJobApplicationCountsRow, error) {
 	rows, err := q.db.Query(ctx, getJobApplicationCounts, recruiterID)
 	if err != nil {
@@ -142,12 +220,45 @@ func (q *Queries) GetJobApplicationCounts(ctx context.Context, recruiterID pgtyp
 }
 
 const getJobByID = `-- name: GetJobByID :one
-SELECT id, recruiter_id, title, description, is_paid, created_at, updated_at, job_type, location_type, location_city, salary_min, salary_max, currency, experience_min, experience_max, job_summary, education_requirements, skills_requirements, is_unpaid, recruiter_email, status FROM jobs WHERE id = $1 LIMIT 1
+SELECT id, recruiter_id, title, description, is_paid, created_at, updated_at, job_type,
+       location_type, location_city, salary_min, salary_max, currency, experience_min,
+       experience_max, job_summary, education_requirements, skills_requirements, is_unpaid,
+       recruiter_email, status, publish_at, expires_at, close_reason, extended_at, pay_period
+FROM jobs WHERE id = $1 LIMIT 1
 `
 
-func (q *Queries) GetJobByID(ctx context.Context, id pgtype.UUID) (Job, error) {
+type GetJobByIDRow struct {
+	ID                    pgtype.UUID        `json:"id"`
+	RecruiterID           pgtype.UUID        `json:"recruiter_id"`
+	Title                 string             `json:"title"`
+	Description           string             `json:"description"`
+	IsPaid                pgtype.Bool        `json:"is_paid"`
+	CreatedAt             pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt             pgtype.Timestamptz `json:"updated_at"`
+	JobType               pgtype.Text        `json:"job_type"`
+	LocationType          pgtype.Text        `json:"location_type"`
+	LocationCity          pgtype.Text        `json:"location_city"`
+	SalaryMin             pgtype.Int4        `json:"salary_min"`
+	SalaryMax             pgtype.Int4        `json:"salary_max"`
+	Currency              pgtype.Text        `json:"currency"`
+	ExperienceMin         pgtype.Int4        `json:"experience_min"`
+	ExperienceMax         pgtype.Int4        `json:"experience_max"`
+	JobSummary            pgtype.Text        `json:"job_summary"`
+	EducationRequirements pgtype.Text        `json:"education_requirements"`
+	SkillsRequirements    pgtype.Text        `json:"skills_requirements"`
+	IsUnpaid              pgtype.Bool        `json:"is_unpaid"`
+	RecruiterEmail        pgtype.Text        `json:"recruiter_email"`
+	Status                pgtype.Text        `json:"status"`
+	PublishAt             pgtype.Timestamptz `json:"publish_at"`
+	ExpiresAt             pgtype.Timestamptz `json:"expires_at"`
+	CloseReason           pgtype.Text        `json:"close_reason"`
+	ExtendedAt            pgtype.Timestamptz `json:"extended_at"`
+	PayPeriod             string             `json:"pay_period"`
+}
+
+func (q *Queries) GetJobByID(ctx context.Context, id pgtype.UUID) (GetJobByIDRow, error) {
 	row := q.db.QueryRow(ctx, getJobByID, id)
-	var i Job
+	var i GetJobByIDRow
 	err := row.Scan(
 		&i.ID,
 		&i.RecruiterID,
@@ -170,16 +281,191 @@ func (q *Queries) GetJobByID(ctx context.Context, id pgtype.UUID) (Job, error) {
 		&i.IsUnpaid,
 		&i.RecruiterEmail,
 		&i.Status,
+		&i.PublishAt,
+		&i.ExpiresAt,
+		&i.CloseReason,
+		&i.ExtendedAt,
+		&i.PayPeriod,
 	)
 	return i, err
 }
 
+const getPublicJob = `-- name: GetPublicJob :one
+SELECT
+  j.id, j.title, j.description, j.job_summary, j.education_requirements, j.skills_requirements,
+  j.experience_min, j.experience_max, j.job_type, j.location_type,
+  COALESCE(j.location_name, j.location_city) AS location_city,
+  j.salary_min, j.salary_max, j.currency, j.pay_period, j.is_unpaid,
+  j.created_at, j.updated_at, j.publish_at, j.expires_at,
+  u.organization_name, u.full_name AS recruiter_name,
+  l.admin1 AS location_region, l.country_code AS location_country, l.latitude, l.longitude
+FROM jobs j
+JOIN users u ON j.recruiter_id = u.id
+LEFT JOIN locations l ON l.id = j.location_id
+WHERE j.id = $1 AND j.status = 'OPEN'
+`
+
+type GetPublicJobRow struct {
+	ID                    pgtype.UUID        `json:"id"`
+	Title                 string             `json:"title"`
+	Description           string             `json:"description"`
+	JobSummary            pgtype.Text        `json:"job_summary"`
+	EducationRequirements pgtype.Text        `json:"education_requirements"`
+	SkillsRequirements    pgtype.Text        `json:"skills_requirements"`
+	ExperienceMin         pgtype.Int4        `json:"experience_min"`
+	ExperienceMax         pgtype.Int4        `json:"experience_max"`
+	JobType               pgtype.Text        `json:"job_type"`
+	LocationType          pgtype.Text        `json:"location_type"`
+	LocationCity          pgtype.Text        `json:"location_city"`
+	SalaryMin             pgtype.Int4        `json:"salary_min"`
+	SalaryMax             pgtype.Int4        `json:"salary_max"`
+	Currency              pgtype.Text        `json:"currency"`
+	PayPeriod             string             `json:"pay_period"`
+	IsUnpaid              pgtype.Bool        `json:"is_unpaid"`
+	CreatedAt             pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt             pgtype.Timestamptz `json:"updated_at"`
+	PublishAt             pgtype.Timestamptz `json:"publish_at"`
+	ExpiresAt             pgtype.Timestamptz `json:"expires_at"`
+	OrganizationName      pgtype.Text        `json:"organization_name"`
+	RecruiterName         pgtype.Text        `json:"recruiter_name"`
+	LocationRegion        pgtype.Text        `json:"location_region"`
+	LocationCountry       pgtype.Text        `json:"location_country"`
+	Latitude              pgtype.Float8      `json:"latitude"`
+	Longitude             pgtype.Float8      `json:"longitude"`
+}
+
+// An open job with what its public page and JSON-LD need; drafts and
+// closed jobs are not public.
+func (q *Queries) GetPublicJob(ctx context.Context, id pgtype.UUID) (GetPublicJobRow, error) {
+	row := q.db.QueryRow(ctx, getPublicJob, id)
+	var i GetPublicJobRow
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.JobSummary,
+		&i.EducationRequirements,
+		&i.SkillsRequirements,
+		&i.ExperienceMin,
+		&i.ExperienceMax,
+		&i.JobType,
+		&i.LocationType,
+		&i.LocationCity,
+		&i.SalaryMin,
+		&i.SalaryMax,
+		&i.Currency,
+		&i.PayPeriod,
+		&i.IsUnpaid,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PublishAt,
+		&i.ExpiresAt,
+		&i.OrganizationName,
+		&i.RecruiterName,
+		&i.LocationRegion,
+		&i.LocationCountry,
+		&i.Latitude,
+		&i.Longitude,
+	)
+	return i, err
+}
+
+const listFeedJobs = `-- name: ListFeedJobs :many
+SELECT
+  j.id, j.title, j.description, j.job_summary, j.skills_requirements,
+  j.experience_min, j.experience_max, j.job_type, j.location_type, j.location_city,
+  j.salary_min, j.salary_max, j.currency, j.pay_period, j.is_unpaid,
+  j.published_at AS posted_at, j.updated_at, j.expires_at,
+  u.organization_name, u.full_name AS recruiter_name
+FROM jobs j
+JOIN users u ON j.recruiter_id = u.id
+WHERE j.status = 'OPEN'
+  AND ($1::uuid IS NULL OR j.recruiter_id = $1)
+  AND ($2::text IS NULL OR lower(u.organization_name) = lower($2))
+ORDER BY posted_at DESC, j.id
+LIMIT $3
+`
+
+type ListFeedJobsParams struct {
+	RecruiterID  pgtype.UUID `json:"recruiter_id"`
+	Organization pgtype.Text `json:"organization"`
+	MaxItems     int32       `json:"max_items"`
+}
+
+type ListFeedJobsRow struct {
+	ID                 pgtype.UUID        `json:"id"`
+	Title              string             `json:"title"`
+	Description        string             `json:"description"`
+	JobSummary         pgtype.Text        `json:"job_summary"`
+	SkillsRequirements pgtype.Text        `json:"skills_requirements"`
+	ExperienceMin      pgtype.Int4        `json:"experience_min"`
+	ExperienceMax      pgtype.Int4        `json:"experience_max"`
+	JobType            pgtype.Text        `json:"job_type"`
+	LocationType       pgtype.Text        `json:"location_type"`
+	LocationCity       pgtype.Text        `json:"location_city"`
+	SalaryMin          pgtype.Int4        `json:"salary_min"`
+	SalaryMax          pgtype.Int4        `json:"salary_max"`
+	Currency           pgtype.Text        `json:"currency"`
+	PayPeriod          string             `json:"pay_period"`
+	IsUnpaid           pgtype.Bool        `json:"is_unpaid"`
+	PostedAt           pgtype.Timestamptz `json:"posted_at"`
+	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
+	ExpiresAt          pgtype.Timestamptz `json:"expires_at"`
+	OrganizationName   pgtype.Text        `json:"organization_name"`
+	RecruiterName      pgtype.Text        `json:"recruiter_name"`
+}
+
+// Open jobs for the syndication feeds, newest posting first, optionally
+// for one recruiter or one organization (matched case-insensitively). A
+// job is posted when it first opened, which is not publish_at for a draft
+// opened by hand.
+func (q *Queries) ListFeedJobs(ctx context.Context, arg ListFeedJobsParams) ([]ListFeedJobsRow, error) {
+	rows, err := q.db.Query(ctx, listFeedJobs, arg.RecruiterID, arg.Organization, arg.MaxItems)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListFeedJobsRow
+	for rows.Next() {
+		var i ListFeedJobsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.JobSummary,
+			&i.SkillsRequirements,
+			&i.ExperienceMin,
+			&i.ExperienceMax,
+			&i.JobType,
+			&i.LocationType,
+			&i.LocationCity,
+			&i.SalaryMin,
+			&i.SalaryMax,
+			&i.Currency,
+			&i.PayPeriod,
+			&i.IsUnpaid,
+			&i.PostedAt,
+			&i.UpdatedAt,
+			&i.ExpiresAt,
+			&i.OrganizationName,
+			&i.RecruiterName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listJobs = `-- name: ListJobs :many
 SELECT 
   j.id, j.recruiter_id, j.title, j.description, j.is_paid, j.created_at, j.updated_at,
   j.job_type, j.location_type, j.location_city, j.salary_min, j.salary_max, j.currency,
   j.job_summary, j.education_requirements, j.skills_requirements, j.is_unpaid,
-  u.organization_name
+  u.organization_name, j.pay_period
 FROM jobs j
 JOIN users u ON j.recruiter_id = u.id
 WHERE status = 'OPEN'
@@ -205,6 +491,7 @@ type ListJobsRow struct {
 	SkillsRequirements    pgtype.Text        `json:"skills_requirements"`
 	IsUnpaid              pgtype.Bool        `json:"is_unpaid"`
 	OrganizationName      pgtype.Text        `json:"organization_name"`
+	PayPeriod             string             `json:"pay_period"`
 }
 
 func (q *Queries) ListJobs(ctx context.Context) ([]ListJobsRow, error) {
@@ -235,6 +522,7 @@ func (q *Queries) ListJobs(ctx context.Context) ([]ListJobsRow, error) {
 			&i.SkillsRequirements,
 			&i.IsUnpaid,
 			&i.OrganizationName,
+			&i.PayPeriod,
 		); err != nil {
 			return nil, err
 		}
@@ -249,8 +537,9 @@ func (q *Queries) ListJobs(ctx context.Context) ([]ListJobsRow, error) {
 const listJobsByRecruiter = `-- name: ListJobsByRecruiter :many
 SELECT 
   id, title, created_at, location_city, location_type,
-  salary_min, salary_max, currency, is_unpaid, skills_requirements,
-  status -- <-- NEW FIELD
+  salary_min, salary_max, currency, pay_period, is_unpaid, skills_requirements,
+  status, -- <-- NEW FIELD
+  publish_at, expires_at, close_reason
 FROM jobs
 WHERE recruiter_id = $1
 ORDER BY created_at DESC
@@ -265,9 +554,13 @@ type ListJobsByRecruiterRow struct {
 	SalaryMin          pgtype.Int4        `json:"salary_min"`
 	SalaryMax          pgtype.Int4        `json:"salary_max"`
 	Currency           pgtype.Text        `json:"currency"`
+	PayPeriod          string             `json:"pay_period"`
 	IsUnpaid           pgtype.Bool        `json:"is_unpaid"`
 	SkillsRequirements pgtype.Text        `json:"skills_requirements"`
 	Status             pgtype.Text        `json:"status"`
+	PublishAt          pgtype.Timestamptz `json:"publish_at"`
+	ExpiresAt          pgtype.Timestamptz `json:"expires_at"`
+	CloseReason        pgtype.Text        `json:"close_reason"`
 }
 
 func (q *Queries) ListJobsByRecruiter(ctx context.Context, recruiterID pgtype.UUID) ([]ListJobsByRecruiterRow, error) {
@@ -288,9 +581,483 @@ func (q *Queries) ListJobsByRecruiter(ctx context.Context, recruiterID pgtype.UU
 			&i.SalaryMin,
 			&i.SalaryMax,
 			&i.Currency,
+			&i.PayPeriod,
 			&i.IsUnpaid,
 			&i.SkillsRequirements,
 			&i.Status,
+			&i.PublishAt,
+			&i.ExpiresAt,
+			&i.CloseReason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listJobsForExport = `-- name: ListJobsForExport :many
+SELECT id, recruiter_id, recruiter_email, title, job_summary, description,
+       education_requirements, skills_requirements, experience_min, experience_max,
+       is_unpaid, salary_min, salary_max, currency, job_type, location_type, location_city,
+       status, publish_at, expires_at, pay_period
+FROM jobs
+WHERE recruiter_id = $1
+  AND ($2::bool OR status <> 'CLOSED')
+ORDER BY created_at, id
+`
+
+type ListJobsForExportParams struct {
+	RecruiterID   pgtype.UUID `json:"recruiter_id"`
+	IncludeClosed bool        `json:"include_closed"`
+}
+
+type ListJobsForExportRow struct {
+	ID                    pgtype.UUID        `json:"id"`
+	RecruiterID           pgtype.UUID        `json:"recruiter_id"`
+	RecruiterEmail        pgtype.Text        `json:"recruiter_email"`
+	Title                 string             `json:"title"`
+	JobSummary            pgtype.Text        `json:"job_summary"`
+	Description           string             `json:"description"`
+	EducationRequirements pgtype.Text        `json:"education_requirements"`
+	SkillsRequirements    pgtype.Text        `json:"skills_requirements"`
+	ExperienceMin         pgtype.Int4        `json:"experience_min"`
+	ExperienceMax         pgtype.Int4        `json:"experience_max"`
+	IsUnpaid              pgtype.Bool        `json:"is_unpaid"`
+	SalaryMin             pgtype.Int4        `json:"salary_min"`
+	SalaryMax             pgtype.Int4        `json:"salary_max"`
+	Currency              pgtype.Text        `json:"currency"`
+	JobType               pgtype.Text        `json:"job_type"`
+	LocationType          pgtype.Text        `json:"location_type"`
+	LocationCity          pgtype.Text        `json:"location_city"`
+	Status                pgtype.Text        `json:"status"`
+	PublishAt             pgtype.Timestamptz `json:"publish_at"`
+	ExpiresAt             pgtype.Timestamptz `json:"expires_at"`
+	PayPeriod             string             `json:"pay_period"`
+}
+
+// A recruiter's jobs with the fields CreateJobRequest takes, oldest first
+func (q *Queries) ListJobsForExport(ctx context.Context, arg ListJobsForExportParams) ([]ListJobsForExportRow, error) {
+	rows, err := q.db.Query(ctx, listJobsForExport, arg.RecruiterID, arg.IncludeClosed)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListJobsForExportRow
+	for rows.Next() {
+		var i ListJobsForExportRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.RecruiterID,
+			&i.RecruiterEmail,
+			&i.Title,
+			&i.JobSummary,
+			&i.Description,
+			&i.EducationRequirements,
+			&i.SkillsRequirements,
+			&i.ExperienceMin,
+			&i.ExperienceMax,
+			&i.IsUnpaid,
+			&i.SalaryMin,
+			&i.SalaryMax,
+			&i.Currency,
+			&i.JobType,
+			&i.LocationType,
+			&i.LocationCity,
+			&i.Status,
+			&i.PublishAt,
+			&i.ExpiresAt,
+			&i.PayPeriod,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSitemapJobs = `-- name: ListSitemapJobs :many
+SELECT id, updated_at
+FROM jobs
+WHERE status = 'OPEN'
+ORDER BY updated_at DESC, id
+LIMIT 50000
+`
+
+type ListSitemapJobsRow struct {
+	ID        pgtype.UUID        `json:"id"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+// Open jobs for the sitemap, most recently changed first. A sitemap file
+// holds at most 50,000 URLs.
+func (q *Queries) ListSitemapJobs(ctx context.Context) ([]ListSitemapJobsRow, error) {
+	rows, err := q.db.Query(ctx, listSitemapJobs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSitemapJobsRow
+	for rows.Next() {
+		var i ListSitemapJobsRow
+		if err := rows.Scan(&i.ID, &i.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const nextJobScheduleAt = `-- name: NextJobScheduleAt :one
+SELECT LEAST(
+  (SELECT MIN(publish_at) FROM jobs WHERE status = 'DRAFT'),
+  (SELECT MIN(expires_at) FROM jobs WHERE status = 'OPEN')
+)::timestamptz AS next_run_at
+`
+
+// When the scheduler next has something to do; NULL when nothing is scheduled
+func (q *Queries) NextJobScheduleAt(ctx context.Context) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, nextJobScheduleAt)
+	var next_run_at pgtype.Timestamptz
+	err := row.Scan(&next_run_at)
+	return next_run_at, err
+}
+
+const publishScheduledJobs = `-- name: PublishScheduledJobs :execrows
+WITH published AS (
+  UPDATE jobs SET status = 'OPEN', updated_at = NOW(), updated_by = NULL
+  WHERE status = 'DRAFT' AND publish_at <= NOW()
+  RETURNING id
+)
+INSERT INTO job_events (job_id, kind)
+SELECT id, 'PUBLISHED' FROM published
+`
+
+func (q *Queries) PublishScheduledJobs(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, publishScheduledJobs)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const reopenJob = `-- name: ReopenJob :one
+WITH reopened AS (
+  UPDATE jobs
+  SET status = 'OPEN',
+      close_reason = NULL,
+      expires_at = CASE WHEN expires_at <= NOW() THEN NULL ELSE expires_at END,
+      extended_at = NOW(),
+      updated_at = NOW(),
+      updated_by = $1::uuid
+  WHERE id = $2 AND status = 'CLOSED'
+  RETURNING id
+), events AS (
+  INSERT INTO job_events (job_id, kind, actor_id)
+  SELECT id, 'REOPENED', $1::uuid FROM reopened
+)
+SELECT id FROM reopened
+`
+
+type ReopenJobParams struct {
+	ActorID pgtype.UUID `json:"actor_id"`
+	ID      pgtype.UUID `json:"id"`
+}
+
+// A past expiry is dropped, or the scheduler would close the job again, and
+// the sweeper's clocks restart as if the job had been extended
+func (q *Queries) ReopenJob(ctx context.Context, arg ReopenJobParams) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, reopenJob, arg.ActorID, arg.ID)
+	var id pgtype.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const searchJobFacets = `-- name: SearchJobFacets :many
+WITH base AS (
+  SELECT j.location_type, lower(COALESCE(j.location_name, j.location_city)) AS location_city, j.job_type, j.currency,
+         COALESCE(j.is_unpaid, FALSE) AS is_unpaid, j.published_at,
+         (cardinality($1::text[]) = 0 OR j.location_type = ANY($1::text[])) AS ok_location_type,
+         (cardinality($2::text[]) = 0 OR lower(j.location_city) = ANY($2::text[])
+          OR j.location_id IN (SELECT resolve_location(c) FROM unnest($2::text[]) AS c)) AS ok_location_city,
+         (cardinality($3::text[]) = 0 OR j.job_type = ANY($3::text[])) AS ok_job_type,
+         (cardinality($4::text[]) = 0 OR j.currency = ANY($4::text[])) AS ok_currency,
+         ($5::bool IS NULL OR COALESCE(j.is_unpaid, FALSE) = $5) AS ok_is_unpaid,
+         ($6::timestamptz IS NULL OR j.published_at >= $6) AS ok_posted_since
+  FROM jobs j
+  LEFT JOIN exchange_rates r ON r.currency = upper(j.currency)
+  LEFT JOIN locations l ON l.id = j.location_id
+  WHERE j.status = 'OPEN'
+    AND ($7::text IS NULL OR j.search_vector @@ websearch_to_tsquery('english', $7) OR j.title % $7)
+    AND ($8::bigint IS NULL OR
+         CASE WHEN $9::float8 IS NULL THEN j.salary_max_yearly
+         ELSE j.salary_max_yearly / r.units_per_usd * $9::float8 END >= $8)
+    AND ($10::bigint IS NULL OR
+         CASE WHEN $9::float8 IS NULL THEN j.salary_min_yearly
+         ELSE j.salary_min_yearly / r.units_per_usd * $9::float8 END <= $10)
+    AND ($11::int IS NULL OR COALESCE(NULLIF(j.experience_max, 0), 100) >= $11)
+    AND ($12::int IS NULL OR COALESCE(j.experience_min, 0) <= $12)
+    AND ($13::float8 IS NULL
+         OR ($14::bool AND j.location_type = 'Remote')
+         OR distance_km(l.latitude, l.longitude, $13::float8, $15::float8) <= $16::float8)
+)
+SELECT 'total'::text AS facet, ''::text AS value, COUNT(*) AS count FROM base
+WHERE ok_location_type AND ok_location_city AND ok_job_type AND ok_currency AND ok_is_unpaid AND ok_posted_since
+UNION ALL
+SELECT 'location_type', COALESCE(location_type, ''), COUNT(*) FROM base
+WHERE ok_location_city AND ok_job_type AND ok_currency AND ok_is_unpaid AND ok_posted_since
+GROUP BY location_type
+UNION ALL
+SELECT 'location_city', COALESCE(location_city, ''), COUNT(*) FROM base
+WHERE ok_location_type AND ok_job_type AND ok_currency AND ok_is_unpaid AND ok_posted_since
+GROUP BY location_city
+UNION ALL
+SELECT 'job_type', COALESCE(job_type, ''), COUNT(*) FROM base
+WHERE ok_location_type AND ok_location_city AND ok_currency AND ok_is_unpaid AND ok_posted_since
+GROUP BY job_type
+UNION ALL
+SELECT 'currency', COALESCE(currency, ''), COUNT(*) FROM base
+WHERE ok_location_type AND ok_location_city AND ok_job_type AND ok_is_unpaid AND ok_posted_since
+GROUP BY currency
+UNION ALL
+SELECT 'is_unpaid', is_unpaid::text, COUNT(*) FROM base
+WHERE ok_location_type AND ok_location_city AND ok_job_type AND ok_currency AND ok_posted_since
+GROUP BY is_unpaid
+UNION ALL
+SELECT 'posted_since', v.label, COUNT(b.published_at) FROM (VALUES ('1d', INTERVAL '1 day'), ('7d', INTERVAL '7 days'), ('30d', INTERVAL '30 days')) AS v(label, span)
+LEFT JOIN base b ON b.published_at >= NOW() - v.span
+  AND b.ok_location_type AND b.ok_location_city AND b.ok_job_type AND b.ok_currency AND b.ok_is_unpaid
+GROUP BY v.label
+ORDER BY facet, count DESC, value
+`
+
+type SearchJobFacetsParams struct {
+	LocationTypes  []string           `json:"location_types"`
+	LocationCities []string           `json:"location_cities"`
+	JobTypes       []string           `json:"job_types"`
+	Currencies     []string           `json:"currencies"`
+	IsUnpaid       pgtype.Bool        `json:"is_unpaid"`
+	PostedSince    pgtype.Timestamptz `json:"posted_since"`
+	Q              pgtype.Text        `json:"q"`
+	SalaryMin      pgtype.Int8        `json:"salary_min"`
+	TargetRate     pgtype.Float8      `json:"target_rate"`
+	SalaryMax      pgtype.Int8        `json:"salary_max"`
+	ExperienceMin  pgtype.Int4        `json:"experience_min"`
+	ExperienceMax  pgtype.Int4        `json:"experience_max"`
+	CenterLat      pgtype.Float8      `json:"center_lat"`
+	IncludeRemote  bool               `json:"include_remote"`
+	CenterLng      pgtype.Float8      `json:"center_lng"`
+	RadiusKm       float64            `json:"radius_km"`
+}
+
+type SearchJobFacetsRow struct {
+	Facet string `json:"facet"`
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// Counts behind the filter chips. Each facet is counted with every filter
+// except its own applied, so a chip shows what selecting it would add.
+// The 'total' row counts the jobs matching all filters.
+func (q *Queries) SearchJobFacets(ctx context.Context, arg SearchJobFacetsParams) ([]SearchJobFacetsRow, error) {
+	rows, err := q.db.Query(ctx, searchJobFacets,
+		arg.LocationTypes,
+		arg.LocationCities,
+		arg.JobTypes,
+		arg.Currencies,
+		arg.IsUnpaid,
+		arg.PostedSince,
+		arg.Q,
+		arg.SalaryMin,
+		arg.TargetRate,
+		arg.SalaryMax,
+		arg.ExperienceMin,
+		arg.ExperienceMax,
+		arg.CenterLat,
+		arg.IncludeRemote,
+		arg.CenterLng,
+		arg.RadiusKm,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchJobFacetsRow
+	for rows.Next() {
+		var i SearchJobFacetsRow
+		if err := rows.Scan(&i.Facet, &i.Value, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchJobs = `-- name: SearchJobs :many
+SELECT
+  j.id, j.recruiter_id, j.title, j.description, j.is_paid, j.created_at, j.updated_at,
+  j.job_type, j.location_type, j.location_city, j.salary_min, j.salary_max, j.currency,
+  j.experience_min, j.experience_max,
+  j.job_summary, j.education_requirements, j.skills_requirements, j.is_unpaid,
+  u.organization_name, j.pay_period,
+  round(j.salary_min_yearly / r.units_per_usd * $1::float8)::bigint AS salary_min_converted,
+  round(j.salary_max_yearly / r.units_per_usd * $1::float8)::bigint AS salary_max_converted,
+  distance_km(l.latitude, l.longitude, $2::float8, $3::float8) AS distance_km
+FROM jobs j
+JOIN users u ON j.recruiter_id = u.id
+LEFT JOIN exchange_rates r ON r.currency = upper(j.currency)
+LEFT JOIN locations l ON l.id = j.location_id
+WHERE j.status = 'OPEN'
+  AND ($4::text IS NULL OR j.search_vector @@ websearch_to_tsquery('english', $4) OR j.title % $4)
+  AND (cardinality($5::text[]) = 0 OR j.location_type = ANY($5::text[]))
+  AND (cardinality($6::text[]) = 0 OR lower(j.location_city) = ANY($6::text[])
+       OR j.location_id IN (SELECT resolve_location(c) FROM unnest($6::text[]) AS c))
+  AND (cardinality($7::text[]) = 0 OR j.job_type = ANY($7::text[]))
+  AND (cardinality($8::text[]) = 0 OR j.currency = ANY($8::text[]))
+  AND ($9::bool IS NULL OR COALESCE(j.is_unpaid, FALSE) = $9)
+  AND ($10::bigint IS NULL OR
+       CASE WHEN $1::float8 IS NULL THEN j.salary_max_yearly
+       ELSE j.salary_max_yearly / r.units_per_usd * $1::float8 END >= $10)
+  AND ($11::bigint IS NULL OR
+       CASE WHEN $1::float8 IS NULL THEN j.salary_min_yearly
+       ELSE j.salary_min_yearly / r.units_per_usd * $1::float8 END <= $11)
+  AND ($12::int IS NULL OR COALESCE(NULLIF(j.experience_max, 0), 100) >= $12)
+  AND ($13::int IS NULL OR COALESCE(j.experience_min, 0) <= $13)
+  AND ($14::timestamptz IS NULL OR j.published_at >= $14)
+  AND ($2::float8 IS NULL
+       OR ($15::bool AND j.location_type = 'Remote')
+       OR distance_km(l.latitude, l.longitude, $2::float8, $3::float8) <= $16::float8)
+  AND ($17::timestamptz IS NULL OR (j.created_at, j.id) < ($17, $18::uuid))
+ORDER BY j.created_at DESC, j.id DESC
+LIMIT $19
+`
+
+type SearchJobsParams struct {
+	TargetRate      pgtype.Float8      `json:"target_rate"`
+	CenterLat       pgtype.Float8      `json:"center_lat"`
+	CenterLng       pgtype.Float8      `json:"center_lng"`
+	Q               pgtype.Text        `json:"q"`
+	LocationTypes   []string           `json:"location_types"`
+	LocationCities  []string           `json:"location_cities"`
+	JobTypes        []string           `json:"job_types"`
+	Currencies      []string           `json:"currencies"`
+	IsUnpaid        pgtype.Bool        `json:"is_unpaid"`
+	SalaryMin       pgtype.Int8        `json:"salary_min"`
+	SalaryMax       pgtype.Int8        `json:"salary_max"`
+	ExperienceMin   pgtype.Int4        `json:"experience_min"`
+	ExperienceMax   pgtype.Int4        `json:"experience_max"`
+	PostedSince     pgtype.Timestamptz `json:"posted_since"`
+	IncludeRemote   bool               `json:"include_remote"`
+	RadiusKm        float64            `json:"radius_km"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        pgtype.UUID        `json:"cursor_id"`
+	PageLimit       int32              `json:"page_limit"`
+}
+
+type SearchJobsRow struct {
+	ID                    pgtype.UUID        `json:"id"`
+	RecruiterID           pgtype.UUID        `json:"recruiter_id"`
+	Title                 string             `json:"title"`
+	Description           string             `json:"description"`
+	IsPaid                pgtype.Bool        `json:"is_paid"`
+	CreatedAt             pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt             pgtype.Timestamptz `json:"updated_at"`
+	JobType               pgtype.Text        `json:"job_type"`
+	LocationType          pgtype.Text        `json:"location_type"`
+	LocationCity          pgtype.Text        `json:"location_city"`
+	SalaryMin             pgtype.Int4        `json:"salary_min"`
+	SalaryMax             pgtype.Int4        `json:"salary_max"`
+	Currency              pgtype.Text        `json:"currency"`
+	ExperienceMin         pgtype.Int4        `json:"experience_min"`
+	ExperienceMax         pgtype.Int4        `json:"experience_max"`
+	JobSummary            pgtype.Text        `json:"job_summary"`
+	EducationRequirements pgtype.Text        `json:"education_requirements"`
+	SkillsRequirements    pgtype.Text        `json:"skills_requirements"`
+	IsUnpaid              pgtype.Bool        `json:"is_unpaid"`
+	OrganizationName      pgtype.Text        `json:"organization_name"`
+	PayPeriod             string             `json:"pay_period"`
+	SalaryMinConverted    pgtype.Int8        `json:"salary_min_converted"`
+	SalaryMaxConverted    pgtype.Int8        `json:"salary_max_converted"`
+	DistanceKm            pgtype.Float8      `json:"distance_km"`
+}
+
+// Open jobs matching every filter that is set, newest first. Multi-value
+// filters are arrays where empty means "any"; pagination is keyset on
+// (created_at, id) so pages stay stable while jobs are being posted.
+// salary_min/salary_max are yearly amounts. With target_rate (units of the
+// searcher's currency per US dollar) they are in that currency and each
+// job's yearly salary is converted before comparing; jobs in a currency
+// without an exchange rate then never match a salary filter.
+// With a center, only jobs placed within radius_km of it match, plus
+// remote jobs when include_remote is set; distance_km is then each job's
+// distance from the center. location_cities match any spelling the
+// gazetteer knows for a city.
+func (q *Queries) SearchJobs(ctx context.Context, arg SearchJobsParams) ([]SearchJobsRow, error) {
+	rows, err := q.db.Query(ctx, searchJobs,
+		arg.TargetRate,
+		arg.CenterLat,
+		arg.CenterLng,
+		arg.Q,
+		arg.LocationTypes,
+		arg.LocationCities,
+		arg.JobTypes,
+		arg.Currencies,
+		arg.IsUnpaid,
+		arg.SalaryMin,
+		arg.SalaryMax,
+		arg.ExperienceMin,
+		arg.ExperienceMax,
+		arg.PostedSince,
+		arg.IncludeRemote,
+		arg.RadiusKm,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchJobsRow
+	for rows.Next() {
+		var i SearchJobsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.RecruiterID,
+			&i.Title,
+			&i.Description,
+			&i.IsPaid,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.JobType,
+			&i.LocationType,
+			&i.LocationCity,
+			&i.SalaryMin,
+			&i.SalaryMax,
+			&i.Currency,
+			&i.ExperienceMin,
+			&i.ExperienceMax,
+			&i.JobSummary,
+			&i.EducationRequirements,
+			&i.SkillsRequirements,
+			&i.IsUnpaid,
+			&i.OrganizationName,
+			&i.PayPeriod,
+			&i.SalaryMinConverted,
+			&i.SalaryMaxConverted,
+			&i.DistanceKm,
 		); err != nil {
 			return nil, err
 		}
@@ -302,11 +1069,158 @@ func (q *Queries) ListJobsByRecruiter(ctx context.Context, recruiterID pgtype.UU
 	return items, nil
 }
 
-const reopenJob = `-- name: ReopenJob :exec
-UPDATE jobs SET status = 'OPEN' WHERE id = $1
+const updateJob = `-- name: UpdateJob :one
+UPDATE jobs
+SET
+  title = COALESCE($1::text, title),
+  description = COALESCE($2::text, description),
+  job_summary = COALESCE($3::text, job_summary),
+  education_requirements = COALESCE($4::text, education_requirements),
+  skills_requirements = COALESCE($5::text, skills_requirements),
+  experience_min = COALESCE($6::int, experience_min),
+  experience_max = COALESCE($7::int, experience_max),
+  is_unpaid = COALESCE($8::bool, is_unpaid),
+  salary_min = COALESCE($9::int, salary_min),
+  salary_max = COALESCE($10::int, salary_max),
+  currency = COALESCE($11::text, currency),
+  pay_period = COALESCE($12::text, pay_period),
+  job_type = COALESCE($13::text, job_type),
+  location_type = COALESCE($14::text, location_type),
+  location_city = COALESCE($15::text, location_city),
+  recruiter_email = COALESCE($16::text, recruiter_email),
+  status = COALESCE($17::text, status),
+  -- status on the right is the value before this update
+  close_reason = CASE
+    WHEN $17::text = 'CLOSED' AND status <> 'CLOSED' THEN 'MANUAL'
+    WHEN $17::text IN ('DRAFT', 'OPEN') THEN NULL
+    ELSE close_reason END,
+  extended_at = CASE WHEN $17::text = 'OPEN' AND status = 'CLOSED' THEN NOW() ELSE extended_at END,
+  publish_at = CASE WHEN $18::bool THEN $19::timestamptz ELSE publish_at END,
+  expires_at = CASE WHEN $20::bool THEN $21::timestamptz ELSE expires_at END,
+  updated_at = NOW(),
+  -- recorded on the job_revisions row this update creates
+  updated_by = $22::uuid
+WHERE id = $23 AND updated_at = $24
+RETURNING id, recruiter_id, title, description, is_paid, created_at, updated_at, job_type,
+       location_type, location_city, salary_min, salary_max, currency, experience_min,
+       experience_max, job_summary, education_requirements, skills_requirements, is_unpaid,
+       recruiter_email, status, publish_at, expires_at, close_reason, extended_at, pay_period
 `
 
-func (q *Queries) ReopenJob(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, reopenJob, id)
-	return err
+type UpdateJobParams struct {
+	Title                 pgtype.Text        `json:"title"`
+	Description           pgtype.Text        `json:"description"`
+	JobSummary            pgtype.Text        `json:"job_summary"`
+	EducationRequirements pgtype.Text        `json:"education_requirements"`
+	SkillsRequirements    pgtype.Text        `json:"skills_requirements"`
+	ExperienceMin         pgtype.Int4        `json:"experience_min"`
+	ExperienceMax         pgtype.Int4        `json:"experience_max"`
+	IsUnpaid              pgtype.Bool        `json:"is_unpaid"`
+	SalaryMin             pgtype.Int4        `json:"salary_min"`
+	SalaryMax             pgtype.Int4        `json:"salary_max"`
+	Currency              pgtype.Text        `json:"currency"`
+	PayPeriod             pgtype.Text        `json:"pay_period"`
+	JobType               pgtype.Text        `json:"job_type"`
+	LocationType          pgtype.Text        `json:"location_type"`
+	LocationCity          pgtype.Text        `json:"location_city"`
+	RecruiterEmail        pgtype.Text        `json:"recruiter_email"`
+	Status                pgtype.Text        `json:"status"`
+	SetPublishAt          bool               `json:"set_publish_at"`
+	PublishAt             pgtype.Timestamptz `json:"publish_at"`
+	SetExpiresAt          bool               `json:"set_expires_at"`
+	ExpiresAt             pgtype.Timestamptz `json:"expires_at"`
+	UpdatedBy             pgtype.UUID        `json:"updated_by"`
+	ID                    pgtype.UUID        `json:"id"`
+	ExpectedUpdatedAt     pgtype.Timestamptz `json:"expected_updated_at"`
+}
+
+type UpdateJobRow struct {
+	ID                    pgtype.UUID        `json:"id"`
+	RecruiterID           pgtype.UUID        `json:"recruiter_id"`
+	Title                 string             `json:"title"`
+	Description           string             `json:"description"`
+	IsPaid                pgtype.Bool        `json:"is_paid"`
+	CreatedAt             pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt             pgtype.Timestamptz `json:"updated_at"`
+	JobType               pgtype.Text        `json:"job_type"`
+	LocationType          pgtype.Text        `json:"location_type"`
+	LocationCity          pgtype.Text        `json:"location_city"`
+	SalaryMin             pgtype.Int4        `json:"salary_min"`
+	SalaryMax             pgtype.Int4        `json:"salary_max"`
+	Currency              pgtype.Text        `json:"currency"`
+	ExperienceMin         pgtype.Int4        `json:"experience_min"`
+	ExperienceMax         pgtype.Int4        `json:"experience_max"`
+	JobSummary            pgtype.Text        `json:"job_summary"`
+	EducationRequirements pgtype.Text        `json:"education_requirements"`
+	SkillsRequirements    pgtype.Text        `json:"skills_requirements"`
+	IsUnpaid              pgtype.Bool        `json:"is_unpaid"`
+	RecruiterEmail        pgtype.Text        `json:"recruiter_email"`
+	Status                pgtype.Text        `json:"status"`
+	PublishAt             pgtype.Timestamptz `json:"publish_at"`
+	ExpiresAt             pgtype.Timestamptz `json:"expires_at"`
+	CloseReason           pgtype.Text        `json:"close_reason"`
+	ExtendedAt            pgtype.Timestamptz `json:"extended_at"`
+	PayPeriod             string             `json:"pay_period"`
+}
+
+// Partial update: NULL leaves a field as it is, except for the schedule,
+// which uses set_* flags so it can be cleared. Nothing is written unless
+// updated_at still equals expected_updated_at (optimistic concurrency).
+func (q *Queries) UpdateJob(ctx context.Context, arg UpdateJobParams) (UpdateJobRow, error) {
+	row := q.db.QueryRow(ctx, updateJob,
+		arg.Title,
+		arg.Description,
+		arg.JobSummary,
+		arg.EducationRequirements,
+		arg.SkillsRequirements,
+		arg.ExperienceMin,
+		arg.ExperienceMax,
+		arg.IsUnpaid,
+		arg.SalaryMin,
+		arg.SalaryMax,
+		arg.Currency,
+		arg.PayPeriod,
+		arg.JobType,
+		arg.LocationType,
+		arg.LocationCity,
+		arg.RecruiterEmail,
+		arg.Status,
+		arg.SetPublishAt,
+		arg.PublishAt,
+		arg.SetExpiresAt,
+		arg.ExpiresAt,
+		arg.UpdatedBy,
+		arg.ID,
+		arg.ExpectedUpdatedAt,
+	)
+	var i UpdateJobRow
+	err := row.Scan(
+		&i.ID,
+		&i.RecruiterID,
+		&i.Title,
+		&i.Description,
+		&i.IsPaid,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.JobType,
+		&i.LocationType,
+		&i.LocationCity,
+		&i.SalaryMin,
+		&i.SalaryMax,
+		&i.Currency,
+		&i.ExperienceMin,
+		&i.ExperienceMax,
+		&i.JobSummary,
+		&i.EducationRequirements,
+		&i.SkillsRequirements,
+		&i.IsUnpaid,
+		&i.RecruiterEmail,
+		&i.Status,
+		&i.PublishAt,
+		&i.ExpiresAt,
+		&i.CloseReason,
+		&i.ExtendedAt,
+		&i.PayPeriod,
+	)
+	return i, err
 }
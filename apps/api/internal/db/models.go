@@ -54,16 +54,152 @@ func (ns NullUserRole) Value() (driver.Value, error) {
 }
 
 type Application struct {
+	ID               pgtype.UUID        `json:"id"`
+	JobID            pgtype.UUID        `json:"job_id"`
+	CandidateID      pgtype.UUID        `json:"candidate_id"`
+	Status           string             `json:"status"`
+	MatchScore       pgtype.Int4        `json:"match_score"`
+	GatewayAnswer    pgtype.Text        `json:"gateway_answer"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+	GatewayGrade     pgtype.Int4        `json:"gateway_grade"`
+	RejectionReason  pgtype.Text        `json:"rejection_reason"`
+	JobRevision      pgtype.Int4        `json:"job_revision"`
+	ClosedAt         pgtype.Timestamptz `json:"closed_at"`
+	ReappliedAt      pgtype.Timestamptz `json:"reapplied_at"`
+	ReapplyCount     int32              `json:"reapply_count"`
+	WithdrawnAt      pgtype.Timestamptz `json:"withdrawn_at"`
+	WithdrawalReason pgtype.Text        `json:"withdrawal_reason"`
+}
+
+type ApplicationAnswer struct {
+	ID             pgtype.UUID        `json:"id"`
+	ApplicationID  pgtype.UUID        `json:"application_id"`
+	QuestionID     pgtype.UUID        `json:"question_id"`
+	QuestionPrompt string             `json:"question_prompt"`
+	Answer         string             `json:"answer"`
+	GradeStatus    string             `json:"grade_status"`
+	Grade          pgtype.Int4        `json:"grade"`
+	Rationale      pgtype.Text        `json:"rationale"`
+	Attempts       int32              `json:"attempts"`
+	RunAt          pgtype.Timestamptz `json:"run_at"`
+	LockedAt       pgtype.Timestamptz `json:"locked_at"`
+	GradedAt       pgtype.Timestamptz `json:"graded_at"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
+type ApplicationNote struct {
 	ID            pgtype.UUID        `json:"id"`
+	ApplicationID pgtype.UUID        `json:"application_id"`
+	AuthorID      pgtype.UUID        `json:"author_id"`
+	Body          string             `json:"body"`
+	Mentions      []pgtype.UUID      `json:"mentions"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+type ApplicationScorecard struct {
+	ID             pgtype.UUID        `json:"id"`
+	ApplicationID  pgtype.UUID        `json:"application_id"`
+	ReviewerID     pgtype.UUID        `json:"reviewer_id"`
+	Recommendation string             `json:"recommendation"`
+	Summary        pgtype.Text        `json:"summary"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
+
+type ApplicationScreening struct {
+	ApplicationID pgtype.UUID        `json:"application_id"`
 	JobID         pgtype.UUID        `json:"job_id"`
-	CandidateID   pgtype.UUID        `json:"candidate_id"`
 	Status        string             `json:"status"`
-	MatchScore    pgtype.Int4        `json:"match_score"`
-	GatewayAnswer pgtype.Text        `json:"gateway_answer"`
+	Bucket        pgtype.Text        `json:"bucket"`
+	Score         pgtype.Int4        `json:"score"`
+	Summary       pgtype.Text        `json:"summary"`
+	Strengths     []string           `json:"strengths"`
+	Gaps          []string           `json:"gaps"`
+	AnswerQuality pgtype.Text        `json:"answer_quality"`
+	Source        pgtype.Text        `json:"source"`
+	LastError     pgtype.Text        `json:"last_error"`
+	Attempts      int32              `json:"attempts"`
+	RequestedAt   pgtype.Timestamptz `json:"requested_at"`
+	RunAt         pgtype.Timestamptz `json:"run_at"`
+	LockedAt      pgtype.Timestamptz `json:"locked_at"`
+	ScreenedAt    pgtype.Timestamptz `json:"screened_at"`
 	CreatedAt     pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
 }
 
+type ApplicationTag struct {
+	ApplicationID pgtype.UUID        `json:"application_id"`
+	Tag           string             `json:"tag"`
+	CreatedBy     pgtype.UUID        `json:"created_by"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+type EducationEntry struct {
+	ID             pgtype.UUID        `json:"id"`
+	UserID         pgtype.UUID        `json:"user_id"`
+	Institution    string             `json:"institution"`
+	Degree         pgtype.Text        `json:"degree"`
+	FieldOfStudy   pgtype.Text        `json:"field_of_study"`
+	GraduationYear pgtype.Int4        `json:"graduation_year"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
+
+type EmailOutbox struct {
+	ID             pgtype.UUID        `json:"id"`
+	ToAddress      string             `json:"to_address"`
+	Subject        string             `json:"subject"`
+	Body           string             `json:"body"`
+	Calendar       pgtype.Text        `json:"calendar"`
+	CalendarMethod pgtype.Text        `json:"calendar_method"`
+	Status         string             `json:"status"`
+	Attempts       int32              `json:"attempts"`
+	RunAt          pgtype.Timestamptz `json:"run_at"`
+	LockedAt       pgtype.Timestamptz `json:"locked_at"`
+	LastError      pgtype.Text        `json:"last_error"`
+	SentAt         pgtype.Timestamptz `json:"sent_at"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
+type ExchangeRate struct {
+	Currency    string             `json:"currency"`
+	UnitsPerUsd pgtype.Numeric     `json:"units_per_usd"`
+	Source      pgtype.Text        `json:"source"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
+type Interview struct {
+	ID              pgtype.UUID        `json:"id"`
+	ApplicationID   pgtype.UUID        `json:"application_id"`
+	OrganizerID     pgtype.UUID        `json:"organizer_id"`
+	Title           string             `json:"title"`
+	DurationMinutes int32              `json:"duration_minutes"`
+	Timezone        string             `json:"timezone"`
+	Location        pgtype.Text        `json:"location"`
+	VideoUrl        pgtype.Text        `json:"video_url"`
+	Status          string             `json:"status"`
+	StartsAt        pgtype.Timestamptz `json:"starts_at"`
+	Uid             string             `json:"uid"`
+	Sequence        int32              `json:"sequence"`
+	InvitedAt       pgtype.Timestamptz `json:"invited_at"`
+	CancelReason    pgtype.Text        `json:"cancel_reason"`
+	CancelledAt     pgtype.Timestamptz `json:"cancelled_at"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+}
+
+type InterviewInterviewer struct {
+	InterviewID pgtype.UUID `json:"interview_id"`
+	UserID      pgtype.UUID `json:"user_id"`
+}
+
+type InterviewSlot struct {
+	ID          pgtype.UUID        `json:"id"`
+	InterviewID pgtype.UUID        `json:"interview_id"`
+	StartsAt    pgtype.Timestamptz `json:"starts_at"`
+}
+
 type Job struct {
 	ID                    pgtype.UUID        `json:"id"`
 	RecruiterID           pgtype.UUID        `json:"recruiter_id"`
@@ -86,6 +222,155 @@ type Job struct {
 	IsUnpaid              pgtype.Bool        `json:"is_unpaid"`
 	RecruiterEmail        pgtype.Text        `json:"recruiter_email"`
 	Status                pgtype.Text        `json:"status"`
+	SearchVector          interface{}        `json:"search_vector"`
+	PublishAt             pgtype.Timestamptz `json:"publish_at"`
+	ExpiresAt             pgtype.Timestamptz `json:"expires_at"`
+	UpdatedBy             pgtype.UUID        `json:"updated_by"`
+	CloseReason           pgtype.Text        `json:"close_reason"`
+	ExtendedAt            pgtype.Timestamptz `json:"extended_at"`
+	PayPeriod             string             `json:"pay_period"`
+	SalaryMinYearly       pgtype.Int8        `json:"salary_min_yearly"`
+	SalaryMaxYearly       pgtype.Int8        `json:"salary_max_yearly"`
+	LocationID            pgtype.Int4        `json:"location_id"`
+	LocationName          pgtype.Text        `json:"location_name"`
+	PublishedAt           pgtype.Timestamptz `json:"published_at"`
+}
+
+type JobActivity struct {
+	JobID          pgtype.UUID        `json:"job_id"`
+	RecruiterID    pgtype.UUID        `json:"recruiter_id"`
+	Title          string             `json:"title"`
+	AgeAnchor      pgtype.Timestamptz `json:"age_anchor"`
+	ActivityAnchor pgtype.Timestamptz `json:"activity_anchor"`
+}
+
+type JobEvent struct {
+	ID        pgtype.UUID        `json:"id"`
+	JobID     pgtype.UUID        `json:"job_id"`
+	Kind      string             `json:"kind"`
+	Reason    pgtype.Text        `json:"reason"`
+	Deadline  pgtype.Timestamptz `json:"deadline"`
+	ActorID   pgtype.UUID        `json:"actor_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type JobPreference struct {
+	UserID          pgtype.UUID        `json:"user_id"`
+	Roles           []string           `json:"roles"`
+	Locations       []string           `json:"locations"`
+	RadiusKm        int32              `json:"radius_km"`
+	RemoteOnly      bool               `json:"remote_only"`
+	JobTypes        []string           `json:"job_types"`
+	MinSalary       pgtype.Int4        `json:"min_salary"`
+	SalaryCurrency  pgtype.Text        `json:"salary_currency"`
+	PayPeriod       string             `json:"pay_period"`
+	MinSalaryYearly pgtype.Int8        `json:"min_salary_yearly"`
+	Alerts          bool               `json:"alerts"`
+	LastNotifiedAt  pgtype.Timestamptz `json:"last_notified_at"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+}
+
+type JobRevision struct {
+	ID        pgtype.UUID        `json:"id"`
+	JobID     pgtype.UUID        `json:"job_id"`
+	Revision  int32              `json:"revision"`
+	Snapshot  []byte             `json:"snapshot"`
+	ChangedBy pgtype.UUID        `json:"changed_by"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type JobScorecardCriterion struct {
+	ID          pgtype.UUID        `json:"id"`
+	JobID       pgtype.UUID        `json:"job_id"`
+	Position    int32              `json:"position"`
+	Name        string             `json:"name"`
+	Description pgtype.Text        `json:"description"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type JobScreeningQuestion struct {
+	ID             pgtype.UUID        `json:"id"`
+	JobID          pgtype.UUID        `json:"job_id"`
+	Position       int32              `json:"position"`
+	Kind           string             `json:"kind"`
+	Prompt         string             `json:"prompt"`
+	Options        []string           `json:"options"`
+	Required       bool               `json:"required"`
+	KnockoutAnswer pgtype.Text        `json:"knockout_answer"`
+	Rubric         pgtype.Text        `json:"rubric"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
+type Location struct {
+	ID          int32   `json:"id"`
+	Name        string  `json:"name"`
+	CountryCode string  `json:"country_code"`
+	Admin1      string  `json:"admin1"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	Population  int64   `json:"population"`
+}
+
+type LocationAlias struct {
+	AliasKey   string `json:"alias_key"`
+	LocationID int32  `json:"location_id"`
+}
+
+type Notification struct {
+	ID        pgtype.UUID        `json:"id"`
+	UserID    pgtype.UUID        `json:"user_id"`
+	Kind      string             `json:"kind"`
+	Title     string             `json:"title"`
+	Body      string             `json:"body"`
+	Data      []byte             `json:"data"`
+	ReadAt    pgtype.Timestamptz `json:"read_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type ResumeParseCache struct {
+	ContentHash   string             `json:"content_hash"`
+	PromptVersion string             `json:"prompt_version"`
+	ExtractedText string             `json:"extracted_text"`
+	Result        []byte             `json:"result"`
+	ExpiresAt     pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+type ResumeParseJob struct {
+	ID          pgtype.UUID        `json:"id"`
+	SourceUrl   string             `json:"source_url"`
+	Status      string             `json:"status"`
+	Attempts    int32              `json:"attempts"`
+	MaxAttempts int32              `json:"max_attempts"`
+	Result      []byte             `json:"result"`
+	LastError   pgtype.Text        `json:"last_error"`
+	RunAt       pgtype.Timestamptz `json:"run_at"`
+	LockedAt    pgtype.Timestamptz `json:"locked_at"`
+	CompletedAt pgtype.Timestamptz `json:"completed_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	Parser      string             `json:"parser"`
+}
+
+type SavedSearch struct {
+	ID             pgtype.UUID        `json:"id"`
+	UserID         pgtype.UUID        `json:"user_id"`
+	Name           string             `json:"name"`
+	Query          string             `json:"query"`
+	Filters        []byte             `json:"filters"`
+	SalaryCurrency pgtype.Text        `json:"salary_currency"`
+	Alerts         bool               `json:"alerts"`
+	LastNotifiedAt pgtype.Timestamptz `json:"last_notified_at"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
+
+type ScorecardRating struct {
+	ScorecardID pgtype.UUID `json:"scorecard_id"`
+	CriterionID pgtype.UUID `json:"criterion_id"`
+	Criterion   string      `json:"criterion"`
+	Rating      int32       `json:"rating"`
 }
 
 type User struct {
@@ -110,4 +395,28 @@ type User struct {
 	OrganizationLocation pgtype.Text        `json:"organization_location"`
 	OrganizationBio      pgtype.Text        `json:"organization_bio"`
 	ProfessionalEmail    pgtype.Text        `json:"professional_email"`
+	Location             pgtype.Text        `json:"location"`
+	ExperienceYears      pgtype.Int4        `json:"experience_years"`
+	SearchVector         interface{}        `json:"search_vector"`
+	LocationID           pgtype.Int4        `json:"location_id"`
+}
+
+type UserFieldSource struct {
+	UserID     pgtype.UUID        `json:"user_id"`
+	Field      string             `json:"field"`
+	Source     string             `json:"source"`
+	ParseJobID pgtype.UUID        `json:"parse_job_id"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+}
+
+type WorkPosition struct {
+	ID          pgtype.UUID        `json:"id"`
+	UserID      pgtype.UUID        `json:"user_id"`
+	Company     string             `json:"company"`
+	Title       string             `json:"title"`
+	StartDate   pgtype.Date        `json:"start_date"`
+	EndDate     pgtype.Date        `json:"end_date"`
+	Description pgtype.Text        `json:"description"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
 }
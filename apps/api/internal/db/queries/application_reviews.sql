@@ -0,0 +1,133 @@
+-- name: GetApplicationReviewTarget :one
+-- The application and job a review is about, for permission checks and
+-- notifications
+SELECT a.id, a.job_id, j.title AS job_title, j.recruiter_id, u.full_name AS candidate_name
+FROM applications a
+JOIN jobs j ON j.id = a.job_id
+JOIN users u ON u.id = a.candidate_id
+WHERE a.id = $1;
+
+-- name: FilterApplicationReviewers :many
+-- Which of user_ids may review the application: its job's recruiter and
+-- recruiters from the same organization
+SELECT u.id FROM applications a
+JOIN jobs j ON j.id = a.job_id
+JOIN users o ON o.id = j.recruiter_id
+JOIN users u ON u.id = ANY(sqlc.arg(user_ids)::uuid[])
+WHERE a.id = sqlc.arg(application_id)
+  AND u.role = 'RECRUITER'
+  AND (u.id = o.id OR (COALESCE(o.organization_name, '') <> ''
+       AND lower(u.organization_name) = lower(o.organization_name)));
+
+-- name: FilterJobReviewers :many
+-- Which of user_ids are on the job's recruiting team; see
+-- FilterApplicationReviewers
+SELECT u.id FROM jobs j
+JOIN users o ON o.id = j.recruiter_id
+JOIN users u ON u.id = ANY(sqlc.arg(user_ids)::uuid[])
+WHERE j.id = sqlc.arg(job_id)
+  AND u.role = 'RECRUITER'
+  AND (u.id = o.id OR (COALESCE(o.organization_name, '') <> ''
+       AND lower(u.organization_name) = lower(o.organization_name)));
+
+-- name: ListApplicationReviewSummariesByJob :many
+-- Each application's review summary: tags, how many notes, and the
+-- scorecards' average rating and recommendations
+SELECT
+    a.id as application_id,
+    COALESCE(t.tags, '{}')::text[] as tags,
+    n.note_count,
+    sc.scorecard_count,
+    sc.average_rating,
+    sc.strong_yes_count,
+    sc.yes_count,
+    sc.no_count,
+    sc.strong_no_count
+FROM applications a
+LEFT JOIN LATERAL (
+    SELECT array_agg(tag ORDER BY tag) AS tags FROM application_tags WHERE application_id = a.id
+) t ON TRUE
+LEFT JOIN LATERAL (
+    SELECT COUNT(*) AS note_count FROM application_notes WHERE application_id = a.id
+) n ON TRUE
+LEFT JOIN LATERAL (
+    SELECT COUNT(*) AS scorecard_count,
+           (SELECT AVG(r.rating)::float8 FROM scorecard_ratings r
+            JOIN application_scorecards rs ON rs.id = r.scorecard_id
+            WHERE rs.application_id = a.id) AS average_rating,
+           COUNT(*) FILTER (WHERE s.recommendation = 'STRONG_YES') AS strong_yes_count,
+           COUNT(*) FILTER (WHERE s.recommendation = 'YES') AS yes_count,
+           COUNT(*) FILTER (WHERE s.recommendation = 'NO') AS no_count,
+           COUNT(*) FILTER (WHERE s.recommendation = 'STRONG_NO') AS strong_no_count
+    FROM application_scorecards s WHERE s.application_id = a.id
+) sc ON TRUE
+WHERE a.job_id = $1;
+
+-- name: CreateApplicationNote :one
+INSERT INTO application_notes (application_id, author_id, body, mentions)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: ListApplicationNotes :many
+SELECT n.id, n.application_id, n.author_id, u.full_name AS author_name, n.body, n.mentions, n.created_at
+FROM application_notes n
+JOIN users u ON u.id = n.author_id
+WHERE n.application_id = $1
+ORDER BY n.created_at, n.id;
+
+-- name: DeleteApplicationNote :execrows
+-- Only a note's author can delete it
+DELETE FROM application_notes WHERE id = $1 AND application_id = $2 AND author_id = $3;
+
+-- name: ListApplicationTags :many
+SELECT tag FROM application_tags WHERE application_id = $1 ORDER BY tag;
+
+-- name: DeleteApplicationTags :exec
+DELETE FROM application_tags WHERE application_id = $1;
+
+-- name: AddApplicationTags :exec
+INSERT INTO application_tags (application_id, tag, created_by)
+SELECT sqlc.arg(application_id), unnest(sqlc.arg(tags)::text[]), sqlc.arg(created_by)
+ON CONFLICT (application_id, tag) DO NOTHING;
+
+-- name: ListScorecardCriteriaByJob :many
+SELECT * FROM job_scorecard_criteria WHERE job_id = $1 ORDER BY position;
+
+-- name: DeleteScorecardCriteriaByJob :exec
+DELETE FROM job_scorecard_criteria WHERE job_id = $1;
+
+-- name: CreateScorecardCriterion :one
+INSERT INTO job_scorecard_criteria (job_id, position, name, description)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: UpsertApplicationScorecard :one
+-- A reviewer's scorecard replaces their previous one for the application
+INSERT INTO application_scorecards (application_id, reviewer_id, recommendation, summary)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (application_id, reviewer_id) DO UPDATE
+SET recommendation = EXCLUDED.recommendation, summary = EXCLUDED.summary, updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteScorecardRatings :exec
+DELETE FROM scorecard_ratings WHERE scorecard_id = $1;
+
+-- name: CreateScorecardRating :exec
+INSERT INTO scorecard_ratings (scorecard_id, criterion_id, criterion, rating)
+VALUES ($1, $2, $3, $4);
+
+-- name: ListApplicationScorecards :many
+SELECT s.id, s.application_id, s.reviewer_id, u.full_name AS reviewer_name, s.recommendation,
+       s.summary, s.created_at, s.updated_at
+FROM application_scorecards s
+JOIN users u ON u.id = s.reviewer_id
+WHERE s.application_id = $1
+ORDER BY s.created_at, s.id;
+
+-- name: ListApplicationScorecardRatings :many
+SELECT r.scorecard_id, r.criterion_id, r.criterion, r.rating
+FROM scorecard_ratings r
+JOIN application_scorecards s ON s.id = r.scorecard_id
+LEFT JOIN job_scorecard_criteria c ON c.id = r.criterion_id
+WHERE s.application_id = $1
+ORDER BY r.scorecard_id, c.position NULLS LAST, r.criterion;
@@ -0,0 +1,123 @@
+-- name: EnqueueApplicationScreening :exec
+-- Queues a (re-)screening. A screening that is already running is left
+-- running; bumping requested_at makes it re-queue itself on completion.
+INSERT INTO application_screenings (application_id, job_id)
+SELECT id, job_id FROM applications WHERE id = $1
+ON CONFLICT (application_id) DO UPDATE
+SET requested_at = NOW(),
+    status = CASE WHEN application_screenings.status = 'RUNNING' THEN 'RUNNING' ELSE 'PENDING' END,
+    attempts = CASE WHEN application_screenings.status = 'RUNNING' THEN application_screenings.attempts ELSE 0 END,
+    run_at = NOW(),
+    updated_at = NOW();
+
+-- name: EnqueueJobScreenings :execrows
+INSERT INTO application_screenings (application_id, job_id)
+SELECT id, job_id FROM applications WHERE job_id = $1
+ON CONFLICT (application_id) DO UPDATE
+SET requested_at = NOW(),
+    status = CASE WHEN application_screenings.status = 'RUNNING' THEN 'RUNNING' ELSE 'PENDING' END,
+    attempts = CASE WHEN application_screenings.status = 'RUNNING' THEN application_screenings.attempts ELSE 0 END,
+    run_at = NOW(),
+    updated_at = NOW();
+
+-- name: EnqueueMissingScreenings :execrows
+INSERT INTO application_screenings (application_id, job_id)
+SELECT a.id, a.job_id FROM applications a
+WHERE NOT EXISTS (SELECT 1 FROM application_screenings s WHERE s.application_id = a.id)
+ON CONFLICT (application_id) DO NOTHING;
+
+-- name: ClaimApplicationScreening :one
+UPDATE application_screenings
+SET status = 'RUNNING', attempts = attempts + 1, locked_at = NOW(), updated_at = NOW()
+WHERE application_id = (
+    SELECT application_id FROM application_screenings
+    WHERE status = 'PENDING' AND run_at <= NOW()
+    ORDER BY run_at
+    FOR UPDATE SKIP LOCKED
+    LIMIT 1
+)
+RETURNING *;
+
+-- name: CompleteApplicationScreening :exec
+UPDATE application_screenings
+SET status = CASE WHEN requested_at > locked_at THEN 'PENDING' ELSE 'DONE' END,
+    attempts = CASE WHEN requested_at > locked_at THEN 0 ELSE attempts END,
+    bucket = $2,
+    score = $3,
+    summary = $4,
+    strengths = $5,
+    gaps = $6,
+    answer_quality = $7,
+    source = $8,
+    last_error = $9,
+    screened_at = NOW(),
+    locked_at = NULL,
+    updated_at = NOW()
+WHERE application_id = $1;
+
+-- name: RetryApplicationScreening :exec
+UPDATE application_screenings
+SET status = 'PENDING', run_at = $2, last_error = $3, locked_at = NULL, updated_at = NOW()
+WHERE application_id = $1;
+
+-- name: RequeueStaleScreenings :execrows
+UPDATE application_screenings
+SET status = 'PENDING', locked_at = NULL, run_at = NOW(), updated_at = NOW()
+WHERE status = 'RUNNING' AND locked_at < $1;
+
+-- name: GetApplicationScreeningInput :one
+SELECT
+    a.id,
+    a.status,
+    a.match_score,
+    a.gateway_answer,
+    a.gateway_grade,
+    a.rejection_reason,
+    j.title as job_title,
+    j.job_summary,
+    j.skills_requirements,
+    j.education_requirements,
+    j.experience_min,
+    j.experience_max,
+    u.full_name as candidate_name,
+    u.job_role as candidate_role,
+    u.bio as candidate_bio,
+    u.skills as candidate_skills,
+    u.experience as candidate_experience,
+    u.education as candidate_education
+FROM applications a
+JOIN jobs j ON a.job_id = j.id
+JOIN users u ON a.candidate_id = u.id
+WHERE a.id = $1;
+
+-- name: ListRecruiterScreenings :many
+SELECT
+    a.id as application_id,
+    a.job_id,
+    j.title as job_title,
+    a.status,
+    a.created_at,
+    a.match_score,
+    a.gateway_grade,
+    a.rejection_reason,
+    a.withdrawn_at,
+    u.id as candidate_id,
+    u.full_name as candidate_name,
+    u.email as candidate_email,
+    u.job_role as candidate_role,
+    s.status as screening_status,
+    s.bucket,
+    s.score,
+    s.summary,
+    s.strengths,
+    s.gaps,
+    s.answer_quality,
+    s.source,
+    s.screened_at
+FROM applications a
+JOIN jobs j ON a.job_id = j.id
+JOIN users u ON a.candidate_id = u.id
+LEFT JOIN application_screenings s ON s.application_id = a.id
+WHERE j.recruiter_id = sqlc.arg(recruiter_id)
+  AND (sqlc.narg(job_id)::uuid IS NULL OR a.job_id = sqlc.narg(job_id))
+ORDER BY a.job_id, s.score DESC NULLS LAST, a.match_score DESC;
@@ -1,10 +1,32 @@
 -- name: CreateApplication :one
+-- job_revision pins the version of the job the candidate applied to. No
+-- row comes back if the candidate has already applied.
 INSERT INTO applications (
-  job_id, candidate_id, status, match_score, gateway_answer
+  job_id, candidate_id, status, match_score, gateway_answer, job_revision
 ) VALUES (
-  $1, $2, $3, $4, $5
+  $1, $2, $3, $4, $5,
+  (SELECT MAX(revision) FROM job_revisions WHERE job_id = $1)
 )
-RETURNING id, job_id, candidate_id, status, match_score, gateway_answer, created_at, updated_at;
+ON CONFLICT (job_id, candidate_id) DO NOTHING
+RETURNING *;
+
+-- name: GetCandidateApplication :one
+SELECT * FROM applications WHERE job_id = $1 AND candidate_id = $2 LIMIT 1;
+
+-- name: ReopenApplication :one
+-- Re-applying to a job: a rejected or withdrawn application starts over
+-- against the job's current revision. No row comes back if it is not
+-- closed any more.
+UPDATE applications
+SET status = $2, match_score = $3, gateway_answer = $4, gateway_grade = NULL,
+    rejection_reason = NULL, closed_at = NULL, withdrawn_at = NULL, withdrawal_reason = NULL,
+    job_revision = (SELECT MAX(revision) FROM job_revisions WHERE job_id = applications.job_id),
+    reapplied_at = NOW(), reapply_count = reapply_count + 1, updated_at = NOW()
+WHERE id = $1 AND closed_at IS NOT NULL
+RETURNING *;
+
+-- name: GetApplicationByID :one
+SELECT * FROM applications WHERE id = $1 LIMIT 1;
 
 -- name: GetApplicationsByCandidate :many
 SELECT 
@@ -12,6 +34,8 @@ SELECT
     a.status, 
     a.created_at, 
     a.match_score,
+    a.job_id,
+    a.job_revision,
     j.title as job_title, 
     j.description as job_description,
     j.location_city,
@@ -34,6 +58,11 @@ SELECT
     a.created_at, 
     a.match_score,
     a.gateway_answer,
+    a.gateway_grade,
+    a.rejection_reason,
+    a.job_revision,
+    a.withdrawn_at,
+    a.withdrawal_reason,
     u.full_name as candidate_name,
     u.email as candidate_email,
     u.job_role as candidate_role,
@@ -45,19 +74,33 @@ WHERE a.job_id = $1
 ORDER BY a.match_score DESC;
 
 -- name: GetApplicationVolumeByRecruiter :many
+-- A re-application counts on the day it was made, not the day of the
+-- original application
 SELECT 
-    DATE(a.created_at)::text as application_date,
+    DATE(COALESCE(a.reapplied_at, a.created_at))::text as application_date,
     COUNT(*) as total
 FROM applications a
 JOIN jobs j ON a.job_id = j.id
 WHERE j.recruiter_id = $1
-  AND EXTRACT(MONTH FROM a.created_at) = EXTRACT(MONTH FROM CURRENT_DATE)
-  AND EXTRACT(YEAR FROM a.created_at) = EXTRACT(YEAR FROM CURRENT_DATE)
+  AND EXTRACT(MONTH FROM COALESCE(a.reapplied_at, a.created_at)) = EXTRACT(MONTH FROM CURRENT_DATE)
+  AND EXTRACT(YEAR FROM COALESCE(a.reapplied_at, a.created_at)) = EXTRACT(YEAR FROM CURRENT_DATE)
 GROUP BY application_date
 ORDER BY application_date ASC;
 
--- name: DeleteApplication :exec
-DELETE FROM applications WHERE id = $1;
+-- name: WithdrawApplication :one
+-- No row comes back if the application is already rejected or withdrawn
+UPDATE applications
+SET status = 'WITHDRAWN', withdrawal_reason = $2, withdrawn_at = NOW(),
+    closed_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND status NOT IN ('REJECTED', 'WITHDRAWN')
+RETURNING *;
+
+-- name: PurgeWithdrawnApplications :execrows
+-- Data retention: withdrawn applications are deleted for good, with their
+-- answers and screenings, once they are old enough. Nothing else deletes
+-- applications.
+DELETE FROM applications
+WHERE status = 'WITHDRAWN' AND withdrawn_at < $1;
 
 -- name: GetAllApplicationsByRecruiter :many
 SELECT 
@@ -66,6 +109,10 @@ SELECT
     a.created_at, 
     a.match_score,
     a.gateway_answer,
+    a.gateway_grade,
+    a.rejection_reason,
+    a.withdrawn_at,
+    a.withdrawal_reason,
     a.job_id, -- <--- ADDED THIS
     j.title as job_title,
     u.id as candidate_id,
@@ -0,0 +1,38 @@
+-- name: EnqueueEmail :exec
+INSERT INTO email_outbox (to_address, subject, body, calendar, calendar_method)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ClaimEmail :one
+-- SKIP LOCKED lets several instances send without blocking each other
+UPDATE email_outbox
+SET status = 'SENDING', attempts = attempts + 1, locked_at = NOW()
+WHERE id = (
+    SELECT id FROM email_outbox
+    WHERE status = 'PENDING' AND run_at <= NOW()
+    ORDER BY run_at
+    FOR UPDATE SKIP LOCKED
+    LIMIT 1
+)
+RETURNING *;
+
+-- name: CompleteEmail :exec
+UPDATE email_outbox
+SET status = 'SENT', locked_at = NULL, last_error = NULL, sent_at = NOW()
+WHERE id = $1;
+
+-- name: RetryEmail :exec
+UPDATE email_outbox
+SET status = 'PENDING', run_at = $2, last_error = $3, locked_at = NULL
+WHERE id = $1;
+
+-- name: FailEmail :exec
+UPDATE email_outbox
+SET status = 'FAILED', last_error = $2, locked_at = NULL
+WHERE id = $1;
+
+-- name: RequeueStaleEmails :execrows
+-- Emails left SENDING by a crashed worker go back on the queue. One may be
+-- sent twice if the crash came after the server accepted it.
+UPDATE email_outbox
+SET status = 'PENDING', locked_at = NULL
+WHERE status = 'SENDING' AND locked_at < $1;
@@ -0,0 +1,13 @@
+-- name: UpsertExchangeRate :exec
+INSERT INTO exchange_rates (currency, units_per_usd, source, updated_at)
+VALUES (sqlc.arg(currency), sqlc.arg(units_per_usd)::numeric, sqlc.arg(source), NOW())
+ON CONFLICT (currency) DO UPDATE
+SET units_per_usd = EXCLUDED.units_per_usd, source = EXCLUDED.source, updated_at = NOW();
+
+-- name: GetExchangeRate :one
+SELECT units_per_usd::float8 AS units_per_usd FROM exchange_rates WHERE currency = $1;
+
+-- name: ListExchangeRates :many
+SELECT currency, units_per_usd::float8 AS units_per_usd, source, updated_at
+FROM exchange_rates
+ORDER BY currency;
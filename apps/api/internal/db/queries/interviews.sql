@@ -0,0 +1,101 @@
+-- name: CreateInterview :one
+-- uid is the iCalendar UID, unique across calendars
+INSERT INTO interviews (
+  application_id, organizer_id, title, duration_minutes, timezone, location, video_url, uid
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, gen_random_uuid()::text || '@' || $8::text
+)
+RETURNING *;
+
+-- name: AddInterviewSlots :exec
+INSERT INTO interview_slots (interview_id, starts_at)
+SELECT sqlc.arg(interview_id), unnest(sqlc.arg(starts_at)::timestamptz[])
+ON CONFLICT (interview_id, starts_at) DO NOTHING;
+
+-- name: AddInterviewers :exec
+INSERT INTO interview_interviewers (interview_id, user_id)
+SELECT sqlc.arg(interview_id), unnest(sqlc.arg(user_ids)::uuid[])
+ON CONFLICT (interview_id, user_id) DO NOTHING;
+
+-- name: GetInterview :one
+SELECT * FROM interviews WHERE id = $1;
+
+-- name: ListInterviewsByApplication :many
+SELECT * FROM interviews WHERE application_id = $1 ORDER BY created_at, id;
+
+-- name: ListInterviewSlots :many
+SELECT * FROM interview_slots WHERE interview_id = $1 ORDER BY starts_at;
+
+-- name: ListInterviewParticipants :many
+-- Everyone on an interview's invite: the candidate, the organizer and the
+-- interviewers, each once
+SELECT u.id, u.full_name, u.email, 'CANDIDATE'::text AS part
+FROM interviews i
+JOIN applications a ON a.id = i.application_id
+JOIN users u ON u.id = a.candidate_id
+WHERE i.id = sqlc.arg(interview_id)
+UNION ALL
+SELECT u.id, u.full_name, u.email, 'ORGANIZER'::text
+FROM interviews i
+JOIN users u ON u.id = i.organizer_id
+WHERE i.id = sqlc.arg(interview_id)
+UNION ALL
+SELECT u.id, u.full_name, u.email, 'INTERVIEWER'::text
+FROM interview_interviewers ii
+JOIN interviews i ON i.id = ii.interview_id
+JOIN users u ON u.id = ii.user_id
+WHERE ii.interview_id = sqlc.arg(interview_id) AND ii.user_id <> i.organizer_id;
+
+-- name: SelectInterviewSlot :one
+-- The candidate picks one of the proposed slots. No row comes back if the
+-- slot is not one of the interview's or the interview is not awaiting a
+-- pick.
+UPDATE interviews
+SET status = 'SCHEDULED', starts_at = s.starts_at,
+    sequence = CASE WHEN interviews.invited_at IS NULL THEN interviews.sequence ELSE interviews.sequence + 1 END,
+    invited_at = COALESCE(interviews.invited_at, NOW()), updated_at = NOW()
+FROM interview_slots s
+WHERE interviews.id = sqlc.arg(id) AND s.id = sqlc.arg(slot_id) AND s.interview_id = interviews.id
+  AND interviews.status = 'PROPOSED'
+RETURNING interviews.*;
+
+-- name: ReviseInterview :one
+-- Partial update: NULL leaves a field as it is. A new starts_at schedules
+-- the interview. Once an invite has gone out, every revision bumps the
+-- sequence so calendars take the update. No row comes back for a
+-- cancelled interview.
+UPDATE interviews
+SET title = COALESCE(sqlc.narg(title), title),
+    duration_minutes = COALESCE(sqlc.narg(duration_minutes), duration_minutes),
+    timezone = COALESCE(sqlc.narg(timezone), timezone),
+    location = COALESCE(sqlc.narg(location), location),
+    video_url = COALESCE(sqlc.narg(video_url), video_url),
+    starts_at = COALESCE(sqlc.narg(starts_at), starts_at),
+    status = CASE WHEN sqlc.narg(starts_at)::timestamptz IS NOT NULL THEN 'SCHEDULED' ELSE status END,
+    sequence = CASE WHEN invited_at IS NULL THEN sequence ELSE sequence + 1 END,
+    invited_at = CASE
+        WHEN status = 'SCHEDULED' OR sqlc.narg(starts_at)::timestamptz IS NOT NULL THEN COALESCE(invited_at, NOW())
+        ELSE invited_at END,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id) AND status <> 'CANCELLED'
+RETURNING *;
+
+-- name: CancelInterview :one
+-- No row comes back if it was already cancelled
+UPDATE interviews
+SET status = 'CANCELLED', cancel_reason = $2, cancelled_at = NOW(),
+    sequence = CASE WHEN invited_at IS NULL THEN sequence ELSE sequence + 1 END,
+    updated_at = NOW()
+WHERE id = $1 AND status <> 'CANCELLED'
+RETURNING *;
+
+-- name: CancelApplicationInterviews :many
+-- When an application closes, its interviews still to come are cancelled:
+-- proposals, and scheduled ones that have not started
+UPDATE interviews
+SET status = 'CANCELLED', cancel_reason = $2, cancelled_at = NOW(),
+    sequence = CASE WHEN invited_at IS NULL THEN sequence ELSE sequence + 1 END,
+    updated_at = NOW()
+WHERE application_id = $1
+  AND (status = 'PROPOSED' OR (status = 'SCHEDULED' AND starts_at > NOW()))
+RETURNING *;
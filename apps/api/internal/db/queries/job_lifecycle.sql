@@ -0,0 +1,91 @@
+-- name: WarnStaleJobs :many
+-- Records a STALE_WARNING for each open job whose age or inactivity
+-- deadline falls within warning_days, once per deadline, and returns the
+-- jobs just warned. A window of 0 days is disabled. Jobs already past
+-- their deadline (on first deploy, or after the sweeper was down) are
+-- warned too; closes_at is when CloseStaleJobs will close them, at least
+-- warning_days from now.
+WITH deadlines AS (
+  SELECT job_id,
+         CASE WHEN sqlc.arg(max_age_days)::int > 0 THEN age_anchor + make_interval(days => sqlc.arg(max_age_days)::int) END AS age_deadline,
+         CASE WHEN sqlc.arg(inactive_days)::int > 0 THEN activity_anchor + make_interval(days => sqlc.arg(inactive_days)::int) END AS inactive_deadline
+  FROM job_activity
+), due AS (
+  SELECT job_id,
+         LEAST(age_deadline, inactive_deadline) AS deadline,
+         CASE WHEN inactive_deadline IS NULL OR age_deadline <= inactive_deadline THEN 'MAX_AGE' ELSE 'INACTIVE' END AS reason
+  FROM deadlines
+), warned AS (
+  INSERT INTO job_events (job_id, kind, reason, deadline)
+  SELECT job_id, 'STALE_WARNING', reason, deadline
+  FROM due
+  WHERE deadline <= NOW() + make_interval(days => sqlc.arg(warning_days)::int)
+  ON CONFLICT (job_id, deadline) WHERE kind = 'STALE_WARNING' DO NOTHING
+  RETURNING job_id, reason, deadline, created_at
+)
+SELECT w.job_id, w.reason, w.deadline,
+       GREATEST(w.deadline, w.created_at + make_interval(days => sqlc.arg(warning_days)::int))::timestamptz AS closes_at,
+       j.recruiter_id, j.title
+FROM warned w
+JOIN jobs j ON j.id = w.job_id;
+
+-- name: CloseStaleJobs :many
+-- Closes open jobs past their age or inactivity deadline with that reason
+-- code, recording an AUTO_CLOSED event for each. Unless warnings are off
+-- (warning_days 0), a job is only closed once the recruiter was warned
+-- about that deadline at least warning_days ago.
+WITH deadlines AS (
+  SELECT job_id,
+         CASE WHEN sqlc.arg(max_age_days)::int > 0 THEN age_anchor + make_interval(days => sqlc.arg(max_age_days)::int) END AS age_deadline,
+         CASE WHEN sqlc.arg(inactive_days)::int > 0 THEN activity_anchor + make_interval(days => sqlc.arg(inactive_days)::int) END AS inactive_deadline
+  FROM job_activity
+), due AS (
+  SELECT job_id,
+         LEAST(age_deadline, inactive_deadline) AS deadline,
+         CASE WHEN inactive_deadline IS NULL OR age_deadline <= inactive_deadline THEN 'MAX_AGE' ELSE 'INACTIVE' END AS reason
+  FROM deadlines
+), closed AS (
+  UPDATE jobs j
+  SET status = 'CLOSED', close_reason = d.reason, updated_at = NOW(), updated_by = NULL
+  FROM due d
+  WHERE j.id = d.job_id AND j.status = 'OPEN' AND d.deadline <= NOW()
+    AND (sqlc.arg(warning_days)::int = 0 OR EXISTS (
+      SELECT 1 FROM job_events e
+      WHERE e.job_id = d.job_id AND e.kind = 'STALE_WARNING' AND e.deadline = d.deadline
+        AND e.created_at + make_interval(days => sqlc.arg(warning_days)::int) <= NOW()
+    ))
+  RETURNING j.id, j.recruiter_id, j.title, j.close_reason, d.deadline
+), events AS (
+  INSERT INTO job_events (job_id, kind, reason, deadline)
+  SELECT id, 'AUTO_CLOSED', close_reason, deadline FROM closed
+)
+SELECT id, recruiter_id, title, close_reason, deadline::timestamptz AS deadline FROM closed;
+
+-- name: ExtendJob :one
+-- Restarts an open job's age and inactivity clocks
+WITH extended AS (
+  UPDATE jobs
+  SET extended_at = NOW(), updated_at = NOW(), updated_by = sqlc.narg(actor_id)::uuid
+  WHERE id = sqlc.arg(id) AND status = 'OPEN'
+  RETURNING id, extended_at
+), events AS (
+  INSERT INTO job_events (job_id, kind, actor_id)
+  SELECT id, 'EXTENDED', sqlc.narg(actor_id)::uuid FROM extended
+)
+SELECT extended_at FROM extended;
+
+-- name: CreateJobEvent :exec
+INSERT INTO job_events (job_id, kind, reason, actor_id)
+VALUES ($1, $2, $3, $4);
+
+-- name: ListRecruiterJobEvents :many
+-- The lifecycle feed for a recruiter's dashboard, newest first
+SELECT e.id, e.job_id, j.title AS job_title, e.kind, e.reason, e.deadline,
+       e.actor_id, u.full_name AS actor_name, e.created_at
+FROM job_events e
+JOIN jobs j ON j.id = e.job_id
+LEFT JOIN users u ON u.id = e.actor_id
+WHERE j.recruiter_id = sqlc.arg(recruiter_id)
+  AND (sqlc.narg(job_id)::uuid IS NULL OR e.job_id = sqlc.narg(job_id))
+ORDER BY e.created_at DESC
+LIMIT sqlc.arg(page_limit);
@@ -0,0 +1,74 @@
+-- name: GetJobPreferences :one
+SELECT * FROM job_preferences WHERE user_id = $1;
+
+-- name: UpsertJobPreferences :one
+-- Turning alerts on starts the digest clock, so the first digest only has
+-- jobs posted after that
+INSERT INTO job_preferences (user_id, roles, locations, radius_km, remote_only, job_types,
+                             min_salary, salary_currency, pay_period, alerts, last_notified_at)
+VALUES (sqlc.arg(user_id), sqlc.arg(roles)::text[], sqlc.arg(locations)::text[], sqlc.arg(radius_km),
+        sqlc.arg(remote_only), sqlc.arg(job_types)::text[], sqlc.narg(min_salary), sqlc.narg(salary_currency),
+        sqlc.arg(pay_period), sqlc.arg(alerts), CASE WHEN sqlc.arg(alerts)::bool THEN NOW() END)
+ON CONFLICT (user_id) DO UPDATE
+SET roles = EXCLUDED.roles,
+    locations = EXCLUDED.locations,
+    radius_km = EXCLUDED.radius_km,
+    remote_only = EXCLUDED.remote_only,
+    job_types = EXCLUDED.job_types,
+    min_salary = EXCLUDED.min_salary,
+    salary_currency = EXCLUDED.salary_currency,
+    pay_period = EXCLUDED.pay_period,
+    alerts = EXCLUDED.alerts,
+    last_notified_at = CASE WHEN job_preferences.alerts THEN job_preferences.last_notified_at ELSE EXCLUDED.last_notified_at END,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteJobPreferences :execrows
+DELETE FROM job_preferences WHERE user_id = $1;
+
+-- name: ListPreferredJobs :many
+-- Open jobs that pass a candidate's hard preferences, newest first, and
+-- whether each meets their soft ones. Jobs without a salary, or in a
+-- currency without an exchange rate, are not ruled out by min_salary.
+SELECT j.id, j.title, u.organization_name, j.location_city, j.created_at,
+  EXISTS (
+    SELECT 1 FROM unnest(p.roles) AS wanted
+    WHERE strpos(lower(j.title), lower(wanted)) > 0
+  ) AS role_match,
+  EXISTS (
+    SELECT 1 FROM unnest(p.locations) AS place
+    LEFT JOIN locations pl ON pl.id = resolve_location(place)
+    WHERE lower(j.location_city) = lower(place)
+       OR distance_km(l.latitude, l.longitude, pl.latitude, pl.longitude) <= p.radius_km
+  ) AS location_match
+FROM job_preferences p
+JOIN jobs j ON j.status = 'OPEN'
+JOIN users u ON u.id = j.recruiter_id
+LEFT JOIN locations l ON l.id = j.location_id
+LEFT JOIN exchange_rates r ON r.currency = upper(j.currency)
+LEFT JOIN exchange_rates pr ON pr.currency = p.salary_currency
+WHERE p.user_id = sqlc.arg(candidate_id)
+  AND (NOT p.remote_only OR j.location_type = 'Remote')
+  AND (cardinality(p.job_types) = 0 OR j.job_type = ANY(p.job_types))
+  AND (p.min_salary_yearly IS NULL OR (NOT COALESCE(j.is_unpaid, FALSE) AND (
+       COALESCE(j.salary_max_yearly, 0) = 0
+       OR (CASE WHEN upper(j.currency) = p.salary_currency THEN j.salary_max_yearly
+                ELSE j.salary_max_yearly / r.units_per_usd * pr.units_per_usd END >= p.min_salary_yearly) IS NOT FALSE)))
+  AND (sqlc.narg(posted_since)::timestamptz IS NULL OR j.published_at >= sqlc.narg(posted_since))
+ORDER BY j.created_at DESC, j.id DESC;
+
+-- name: ClaimJobPreferenceDigests :many
+-- Candidates whose preference digest is due, moving their clock to now.
+-- since is when the previous digest went out. SKIP LOCKED lets several
+-- servers run the digest without sending one twice.
+WITH due AS (
+  SELECT user_id, last_notified_at FROM job_preferences
+  WHERE alerts AND last_notified_at <= NOW() - make_interval(days => sqlc.arg(interval_days)::int)
+  ORDER BY last_notified_at
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE SKIP LOCKED
+)
+UPDATE job_preferences p SET last_notified_at = NOW()
+FROM due
+WHERE p.user_id = due.user_id
+RETURNING p.user_id, due.last_notified_at AS since;
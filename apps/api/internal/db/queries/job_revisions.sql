@@ -0,0 +1,8 @@
+-- name: ListJobRevisions :many
+-- Oldest first; revisions are written by the jobs_record_revision trigger
+SELECT r.id, r.job_id, r.revision, r.snapshot, r.changed_by, r.created_at,
+       u.full_name AS changed_by_name
+FROM job_revisions r
+LEFT JOIN users u ON r.changed_by = u.id
+WHERE r.job_id = $1
+ORDER BY r.revision;
@@ -4,18 +4,18 @@ INSERT INTO jobs (
   job_type, location_type, location_city, salary_min, salary_max, currency,
   experience_min, experience_max,
   job_summary, education_requirements, skills_requirements, is_unpaid,
-  recruiter_email
+  recruiter_email, status, publish_at, expires_at, pay_period
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21
 )
-RETURNING id, recruiter_id, title, description, is_paid, created_at, updated_at;
+RETURNING id, recruiter_id, title, description, is_paid, created_at, updated_at, status, publish_at, expires_at;
 
 -- name: ListJobs :many
 SELECT 
   j.id, j.recruiter_id, j.title, j.description, j.is_paid, j.created_at, j.updated_at,
   j.job_type, j.location_type, j.location_city, j.salary_min, j.salary_max, j.currency,
   j.job_summary, j.education_requirements, j.skills_requirements, j.is_unpaid,
-  u.organization_name
+  u.organization_name, j.pay_period
 FROM jobs j
 JOIN users u ON j.recruiter_id = u.id
 WHERE status = 'OPEN'
@@ -24,29 +24,300 @@ ORDER BY j.created_at DESC;
 -- name: ListJobsByRecruiter :many
 SELECT 
   id, title, created_at, location_city, location_type,
-  salary_min, salary_max, currency, is_unpaid, skills_requirements,
-  status -- <-- NEW FIELD
+  salary_min, salary_max, currency, pay_period, is_unpaid, skills_requirements,
+  status, -- <-- NEW FIELD
+  publish_at, expires_at, close_reason
 FROM jobs
 WHERE recruiter_id = $1
 ORDER BY created_at DESC;
 
--- name: CloseJob :exec
-UPDATE jobs SET status = 'CLOSED' WHERE id = $1;
+-- name: CloseJob :one
+WITH closed AS (
+  UPDATE jobs SET status = 'CLOSED', close_reason = 'MANUAL', updated_at = NOW(), updated_by = sqlc.narg(actor_id)::uuid
+  WHERE id = sqlc.arg(id) AND status = 'OPEN'
+  RETURNING id
+), events AS (
+  INSERT INTO job_events (job_id, kind, reason, actor_id)
+  SELECT id, 'CLOSED', 'MANUAL', sqlc.narg(actor_id)::uuid FROM closed
+)
+SELECT id FROM closed;
 
--- name: ReopenJob :exec
-UPDATE jobs SET status = 'OPEN' WHERE id = $1;
+-- name: ReopenJob :one
+-- A past expiry is dropped, or the scheduler would close the job again, and
+-- the sweeper's clocks restart as if the job had been extended
+WITH reopened AS (
+  UPDATE jobs
+  SET status = 'OPEN',
+      close_reason = NULL,
+      expires_at = CASE WHEN expires_at <= NOW() THEN NULL ELSE expires_at END,
+      extended_at = NOW(),
+      updated_at = NOW(),
+      updated_by = sqlc.narg(actor_id)::uuid
+  WHERE id = sqlc.arg(id) AND status = 'CLOSED'
+  RETURNING id
+), events AS (
+  INSERT INTO job_events (job_id, kind, actor_id)
+  SELECT id, 'REOPENED', sqlc.narg(actor_id)::uuid FROM reopened
+)
+SELECT id FROM reopened;
 
 -- name: GetJobApplicationCounts :many
+-- Withdrawn applications are not applicants any more
 SELECT 
     j.id,
     j.title,
     j.status,
     COUNT(a.id)::int as applicant_count
 FROM jobs j
-LEFT JOIN applications a ON j.id = a.job_id
+LEFT JOIN applications a ON j.id = a.job_id AND a.status <> 'WITHDRAWN'
 WHERE j.recruiter_id = $1
 GROUP BY j.id
 ORDER BY applicant_count DESC;
 
 -- name: GetJobByID :one
-SELECT * FROM jobs WHERE id = $1 LIMIT 1;
\ No newline at end of file
+SELECT id, recruiter_id, title, description, is_paid, created_at, updated_at, job_type,
+       location_type, location_city, salary_min, salary_max, currency, experience_min,
+       experience_max, job_summary, education_requirements, skills_requirements, is_unpaid,
+       recruiter_email, status, publish_at, expires_at, close_reason, extended_at, pay_period
+FROM jobs WHERE id = $1 LIMIT 1;
+
+-- name: UpdateJob :one
+-- Partial update: NULL leaves a field as it is, except for the schedule,
+-- which uses set_* flags so it can be cleared. Nothing is written unless
+-- updated_at still equals expected_updated_at (optimistic concurrency).
+UPDATE jobs
+SET
+  title = COALESCE(sqlc.narg(title)::text, title),
+  description = COALESCE(sqlc.narg(description)::text, description),
+  job_summary = COALESCE(sqlc.narg(job_summary)::text, job_summary),
+  education_requirements = COALESCE(sqlc.narg(education_requirements)::text, education_requirements),
+  skills_requirements = COALESCE(sqlc.narg(skills_requirements)::text, skills_requirements),
+  experience_min = COALESCE(sqlc.narg(experience_min)::int, experience_min),
+  experience_max = COALESCE(sqlc.narg(experience_max)::int, experience_max),
+  is_unpaid = COALESCE(sqlc.narg(is_unpaid)::bool, is_unpaid),
+  salary_min = COALESCE(sqlc.narg(salary_min)::int, salary_min),
+  salary_max = COALESCE(sqlc.narg(salary_max)::int, salary_max),
+  currency = COALESCE(sqlc.narg(currency)::text, currency),
+  pay_period = COALESCE(sqlc.narg(pay_period)::text, pay_period),
+  job_type = COALESCE(sqlc.narg(job_type)::text, job_type),
+  location_type = COALESCE(sqlc.narg(location_type)::text, location_type),
+  location_city = COALESCE(sqlc.narg(location_city)::text, location_city),
+  recruiter_email = COALESCE(sqlc.narg(recruiter_email)::text, recruiter_email),
+  status = COALESCE(sqlc.narg(status)::text, status),
+  -- status on the right is the value before this update
+  close_reason = CASE
+    WHEN sqlc.narg(status)::text = 'CLOSED' AND status <> 'CLOSED' THEN 'MANUAL'
+    WHEN sqlc.narg(status)::text IN ('DRAFT', 'OPEN') THEN NULL
+    ELSE close_reason END,
+  extended_at = CASE WHEN sqlc.narg(status)::text = 'OPEN' AND status = 'CLOSED' THEN NOW() ELSE extended_at END,
+  publish_at = CASE WHEN sqlc.arg(set_publish_at)::bool THEN sqlc.narg(publish_at)::timestamptz ELSE publish_at END,
+  expires_at = CASE WHEN sqlc.arg(set_expires_at)::bool THEN sqlc.narg(expires_at)::timestamptz ELSE expires_at END,
+  updated_at = NOW(),
+  -- recorded on the job_revisions row this update creates
+  updated_by = sqlc.narg(updated_by)::uuid
+WHERE id = sqlc.arg(id) AND updated_at = sqlc.arg(expected_updated_at)
+RETURNING id, recruiter_id, title, description, is_paid, created_at, updated_at, job_type,
+       location_type, location_city, salary_min, salary_max, currency, experience_min,
+       experience_max, job_summary, education_requirements, skills_requirements, is_unpaid,
+       recruiter_email, status, publish_at, expires_at, close_reason, extended_at, pay_period;
+
+-- name: PublishScheduledJobs :execrows
+WITH published AS (
+  UPDATE jobs SET status = 'OPEN', updated_at = NOW(), updated_by = NULL
+  WHERE status = 'DRAFT' AND publish_at <= NOW()
+  RETURNING id
+)
+INSERT INTO job_events (job_id, kind)
+SELECT id, 'PUBLISHED' FROM published;
+
+-- name: ExpireJobs :execrows
+WITH expired AS (
+  UPDATE jobs SET status = 'CLOSED', close_reason = 'EXPIRED', updated_at = NOW(), updated_by = NULL
+  WHERE status = 'OPEN' AND expires_at <= NOW()
+  RETURNING id
+)
+INSERT INTO job_events (job_id, kind, reason)
+SELECT id, 'AUTO_CLOSED', 'EXPIRED' FROM expired;
+
+-- name: NextJobScheduleAt :one
+-- When the scheduler next has something to do; NULL when nothing is scheduled
+SELECT LEAST(
+  (SELECT MIN(publish_at) FROM jobs WHERE status = 'DRAFT'),
+  (SELECT MIN(expires_at) FROM jobs WHERE status = 'OPEN')
+)::timestamptz AS next_run_at;
+
+-- name: SearchJobs :many
+-- Open jobs matching every filter that is set, newest first. Multi-value
+-- filters are arrays where empty means "any"; pagination is keyset on
+-- (created_at, id) so pages stay stable while jobs are being posted.
+-- salary_min/salary_max are yearly amounts. With target_rate (units of the
+-- searcher's currency per US dollar) they are in that currency and each
+-- job's yearly salary is converted before comparing; jobs in a currency
+-- without an exchange rate then never match a salary filter.
+-- With a center, only jobs placed within radius_km of it match, plus
+-- remote jobs when include_remote is set; distance_km is then each job's
+-- distance from the center. location_cities match any spelling the
+-- gazetteer knows for a city.
+SELECT
+  j.id, j.recruiter_id, j.title, j.description, j.is_paid, j.created_at, j.updated_at,
+  j.job_type, j.location_type, j.location_city, j.salary_min, j.salary_max, j.currency,
+  j.experience_min, j.experience_max,
+  j.job_summary, j.education_requirements, j.skills_requirements, j.is_unpaid,
+  u.organization_name, j.pay_period,
+  round(j.salary_min_yearly / r.units_per_usd * sqlc.narg(target_rate)::float8)::bigint AS salary_min_converted,
+  round(j.salary_max_yearly / r.units_per_usd * sqlc.narg(target_rate)::float8)::bigint AS salary_max_converted,
+  distance_km(l.latitude, l.longitude, sqlc.narg(center_lat)::float8, sqlc.narg(center_lng)::float8) AS distance_km
+FROM jobs j
+JOIN users u ON j.recruiter_id = u.id
+LEFT JOIN exchange_rates r ON r.currency = upper(j.currency)
+LEFT JOIN locations l ON l.id = j.location_id
+WHERE j.status = 'OPEN'
+  AND (sqlc.narg(q)::text IS NULL OR j.search_vector @@ websearch_to_tsquery('english', sqlc.narg(q)) OR j.title % sqlc.narg(q))
+  AND (cardinality(sqlc.arg(location_types)::text[]) = 0 OR j.location_type = ANY(sqlc.arg(location_types)::text[]))
+  AND (cardinality(sqlc.arg(location_cities)::text[]) = 0 OR lower(j.location_city) = ANY(sqlc.arg(location_cities)::text[])
+       OR j.location_id IN (SELECT resolve_location(c) FROM unnest(sqlc.arg(location_cities)::text[]) AS c))
+  AND (cardinality(sqlc.arg(job_types)::text[]) = 0 OR j.job_type = ANY(sqlc.arg(job_types)::text[]))
+  AND (cardinality(sqlc.arg(currencies)::text[]) = 0 OR j.currency = ANY(sqlc.arg(currencies)::text[]))
+  AND (sqlc.narg(is_unpaid)::bool IS NULL OR COALESCE(j.is_unpaid, FALSE) = sqlc.narg(is_unpaid))
+  AND (sqlc.narg(salary_min)::bigint IS NULL OR
+       CASE WHEN sqlc.narg(target_rate)::float8 IS NULL THEN j.salary_max_yearly
+       ELSE j.salary_max_yearly / r.units_per_usd * sqlc.narg(target_rate)::float8 END >= sqlc.narg(salary_min))
+  AND (sqlc.narg(salary_max)::bigint IS NULL OR
+       CASE WHEN sqlc.narg(target_rate)::float8 IS NULL THEN j.salary_min_yearly
+       ELSE j.salary_min_yearly / r.units_per_usd * sqlc.narg(target_rate)::float8 END <= sqlc.narg(salary_max))
+  AND (sqlc.narg(experience_min)::int IS NULL OR COALESCE(NULLIF(j.experience_max, 0), 100) >= sqlc.narg(experience_min))
+  AND (sqlc.narg(experience_max)::int IS NULL OR COALESCE(j.experience_min, 0) <= sqlc.narg(experience_max))
+  AND (sqlc.narg(posted_since)::timestamptz IS NULL OR j.published_at >= sqlc.narg(posted_since))
+  AND (sqlc.narg(center_lat)::float8 IS NULL
+       OR (sqlc.arg(include_remote)::bool AND j.location_type = 'Remote')
+       OR distance_km(l.latitude, l.longitude, sqlc.narg(center_lat)::float8, sqlc.narg(center_lng)::float8) <= sqlc.arg(radius_km)::float8)
+  AND (sqlc.narg(cursor_created_at)::timestamptz IS NULL OR (j.created_at, j.id) < (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::uuid))
+ORDER BY j.created_at DESC, j.id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: SearchJobFacets :many
+-- Counts behind the filter chips. Each facet is counted with every filter
+-- except its own applied, so a chip shows what selecting it would add.
+-- The 'total' row counts the jobs matching all filters.
+WITH base AS (
+  SELECT j.location_type, lower(COALESCE(j.location_name, j.location_city)) AS location_city, j.job_type, j.currency,
+         COALESCE(j.is_unpaid, FALSE) AS is_unpaid, j.published_at,
+         (cardinality(sqlc.arg(location_types)::text[]) = 0 OR j.location_type = ANY(sqlc.arg(location_types)::text[])) AS ok_location_type,
+         (cardinality(sqlc.arg(location_cities)::text[]) = 0 OR lower(j.location_city) = ANY(sqlc.arg(location_cities)::text[])
+          OR j.location_id IN (SELECT resolve_location(c) FROM unnest(sqlc.arg(location_cities)::text[]) AS c)) AS ok_location_city,
+         (cardinality(sqlc.arg(job_types)::text[]) = 0 OR j.job_type = ANY(sqlc.arg(job_types)::text[])) AS ok_job_type,
+         (cardinality(sqlc.arg(currencies)::text[]) = 0 OR j.currency = ANY(sqlc.arg(currencies)::text[])) AS ok_currency,
+         (sqlc.narg(is_unpaid)::bool IS NULL OR COALESCE(j.is_unpaid, FALSE) = sqlc.narg(is_unpaid)) AS ok_is_unpaid,
+         (sqlc.narg(posted_since)::timestamptz IS NULL OR j.published_at >= sqlc.narg(posted_since)) AS ok_posted_since
+  FROM jobs j
+  LEFT JOIN exchange_rates r ON r.currency = upper(j.currency)
+  LEFT JOIN locations l ON l.id = j.location_id
+  WHERE j.status = 'OPEN'
+    AND (sqlc.narg(q)::text IS NULL OR j.search_vector @@ websearch_to_tsquery('english', sqlc.narg(q)) OR j.title % sqlc.narg(q))
+    AND (sqlc.narg(salary_min)::bigint IS NULL OR
+         CASE WHEN sqlc.narg(target_rate)::float8 IS NULL THEN j.salary_max_yearly
+         ELSE j.salary_max_yearly / r.units_per_usd * sqlc.narg(target_rate)::float8 END >= sqlc.narg(salary_min))
+    AND (sqlc.narg(salary_max)::bigint IS NULL OR
+         CASE WHEN sqlc.narg(target_rate)::float8 IS NULL THEN j.salary_min_yearly
+         ELSE j.salary_min_yearly / r.units_per_usd * sqlc.narg(target_rate)::float8 END <= sqlc.narg(salary_max))
+    AND (sqlc.narg(experience_min)::int IS NULL OR COALESCE(NULLIF(j.experience_max, 0), 100) >= sqlc.narg(experience_min))
+    AND (sqlc.narg(experience_max)::int IS NULL OR COALESCE(j.experience_min, 0) <= sqlc.narg(experience_max))
+    AND (sqlc.narg(center_lat)::float8 IS NULL
+         OR (sqlc.arg(include_remote)::bool AND j.location_type = 'Remote')
+         OR distance_km(l.latitude, l.longitude, sqlc.narg(center_lat)::float8, sqlc.narg(center_lng)::float8) <= sqlc.arg(radius_km)::float8)
+)
+SELECT 'total'::text AS facet, ''::text AS value, COUNT(*) AS count FROM base
+WHERE ok_location_type AND ok_location_city AND ok_job_type AND ok_currency AND ok_is_unpaid AND ok_posted_since
+UNION ALL
+SELECT 'location_type', COALESCE(location_type, ''), COUNT(*) FROM base
+WHERE ok_location_city AND ok_job_type AND ok_currency AND ok_is_unpaid AND ok_posted_since
+GROUP BY location_type
+UNION ALL
+SELECT 'location_city', COALESCE(location_city, ''), COUNT(*) FROM base
+WHERE ok_location_type AND ok_job_type AND ok_currency AND ok_is_unpaid AND ok_posted_since
+GROUP BY location_city
+UNION ALL
+SELECT 'job_type', COALESCE(job_type, ''), COUNT(*) FROM base
+WHERE ok_location_type AND ok_location_city AND ok_currency AND ok_is_unpaid AND ok_posted_since
+GROUP BY job_type
+UNION ALL
+SELECT 'currency', COALESCE(currency, ''), COUNT(*) FROM base
+WHERE ok_location_type AND ok_location_city AND ok_job_type AND ok_is_unpaid AND ok_posted_since
+GROUP BY currency
+UNION ALL
+SELECT 'is_unpaid', is_unpaid::text, COUNT(*) FROM base
+WHERE ok_location_type AND ok_location_city AND ok_job_type AND ok_currency AND ok_posted_since
+GROUP BY is_unpaid
+UNION ALL
+SELECT 'posted_since', v.label, COUNT(b.published_at) FROM (VALUES ('1d', INTERVAL '1 day'), ('7d', INTERVAL '7 days'), ('30d', INTERVAL '30 days')) AS v(label, span)
+LEFT JOIN base b ON b.published_at >= NOW() - v.span
+  AND b.ok_location_type AND b.ok_location_city AND b.ok_job_type AND b.ok_currency AND b.ok_is_unpaid
+GROUP BY v.label
+ORDER BY facet, count DESC, value;
+
+-- name: ListJobsForExport :many
+-- A recruiter's jobs with the fields CreateJobRequest takes, oldest first
+SELECT id, recruiter_id, recruiter_email, title, job_summary, description,
+       education_requirements, skills_requirements, experience_min, experience_max,
+       is_unpaid, salary_min, salary_max, currency, job_type, location_type, location_city,
+       status, publish_at, expires_at, pay_period
+FROM jobs
+WHERE recruiter_id = sqlc.arg(recruiter_id)
+  AND (sqlc.arg(include_closed)::bool OR status <> 'CLOSED')
+ORDER BY created_at, id;
+
+-- name: GetPublicJob :one
+-- An open job with what its public page and JSON-LD need; drafts and
+-- closed jobs are not public.
+SELECT
+  j.id, j.title, j.description, j.job_summary, j.education_requirements, j.skills_requirements,
+  j.experience_min, j.experience_max, j.job_type, j.location_type,
+  COALESCE(j.location_name, j.location_city) AS location_city,
+  j.salary_min, j.salary_max, j.currency, j.pay_period, j.is_unpaid,
+  j.created_at, j.updated_at, j.publish_at, j.expires_at,
+  u.organization_name, u.full_name AS recruiter_name,
+  l.admin1 AS location_region, l.country_code AS location_country, l.latitude, l.longitude
+FROM jobs j
+JOIN users u ON j.recruiter_id = u.id
+LEFT JOIN locations l ON l.id = j.location_id
+WHERE j.id = $1 AND j.status = 'OPEN';
+
+-- name: ListSitemapJobs :many
+-- Open jobs for the sitemap, most recently changed first. A sitemap file
+-- holds at most 50,000 URLs.
+SELECT id, updated_at
+FROM jobs
+WHERE status = 'OPEN'
+ORDER BY updated_at DESC, id
+LIMIT 50000;
+
+-- name: ListFeedJobs :many
+-- Open jobs for the syndication feeds, newest posting first, optionally
+-- for one recruiter or one organization (matched case-insensitively). A
+-- job is posted when it first opened, which is not publish_at for a draft
+-- opened by hand.
+SELECT
+  j.id, j.title, j.description, j.job_summary, j.skills_requirements,
+  j.experience_min, j.experience_max, j.job_type, j.location_type, j.location_city,
+  j.salary_min, j.salary_max, j.currency, j.pay_period, j.is_unpaid,
+  j.published_at AS posted_at, j.updated_at, j.expires_at,
+  u.organization_name, u.full_name AS recruiter_name
+FROM jobs j
+JOIN users u ON j.recruiter_id = u.id
+WHERE j.status = 'OPEN'
+  AND (sqlc.narg(recruiter_id)::uuid IS NULL OR j.recruiter_id = sqlc.narg(recruiter_id))
+  AND (sqlc.narg(organization)::text IS NULL OR lower(u.organization_name) = lower(sqlc.narg(organization)))
+ORDER BY posted_at DESC, j.id
+LIMIT sqlc.arg(max_items);
+
+-- name: GetFeedVersion :one
+-- Moves whenever a job in a feed with the same filters is posted, edited,
+-- opened or closed (closing bumps updated_at too), so cached feeds are
+-- regenerated only when they would change.
+SELECT
+  COUNT(*) FILTER (WHERE j.status = 'OPEN') AS open_jobs,
+  MAX(j.updated_at)::timestamptz AS last_modified
+FROM jobs j
+JOIN users u ON j.recruiter_id = u.id
+WHERE (sqlc.narg(recruiter_id)::uuid IS NULL OR j.recruiter_id = sqlc.narg(recruiter_id))
+  AND (sqlc.narg(organization)::text IS NULL OR lower(u.organization_name) = lower(sqlc.narg(organization)));
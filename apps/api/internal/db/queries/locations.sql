@@ -0,0 +1,39 @@
+-- name: UpsertLocation :one
+INSERT INTO locations (name, country_code, admin1, latitude, longitude, population)
+VALUES (sqlc.arg(name), sqlc.arg(country_code), sqlc.arg(admin1), sqlc.arg(latitude), sqlc.arg(longitude), sqlc.arg(population))
+ON CONFLICT (country_code, admin1, name) DO UPDATE
+SET latitude = EXCLUDED.latitude, longitude = EXCLUDED.longitude, population = EXCLUDED.population
+RETURNING id;
+
+-- name: AddLocationAliases :exec
+INSERT INTO location_aliases (alias_key, location_id)
+SELECT DISTINCT location_key(a), sqlc.arg(location_id)::int
+FROM unnest(sqlc.arg(aliases)::text[]) AS a
+WHERE location_key(a) IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+-- name: ResolveLocation :one
+-- The place free text such as "Bangalore, India" names, if the gazetteer
+-- knows it
+SELECT * FROM locations WHERE id = resolve_location(sqlc.arg(place)::text);
+
+-- name: SearchLocations :many
+-- Places with a name or alias starting with prefix, most populous first
+SELECT * FROM locations
+WHERE id IN (SELECT location_id FROM location_aliases WHERE alias_key LIKE location_key(sqlc.arg(prefix)::text) || '%')
+ORDER BY population DESC, name
+LIMIT sqlc.arg(max_results);
+
+-- name: GeocodeJobs :execrows
+-- Re-runs the geocoding trigger on jobs whose place the gazetteer now
+-- resolves differently. It makes no revision, but updated_at moves so
+-- public pages and feeds showing the place are refreshed.
+UPDATE jobs SET location_city = location_city, updated_at = NOW(), updated_by = NULL
+WHERE location_city IS NOT NULL
+  AND location_type IS DISTINCT FROM 'Remote'
+  AND location_id IS DISTINCT FROM resolve_location(location_city);
+
+-- name: GeocodeUsers :execrows
+UPDATE users SET location = location
+WHERE location IS NOT NULL
+  AND location_id IS DISTINCT FROM resolve_location(location);
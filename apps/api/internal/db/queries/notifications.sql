@@ -0,0 +1,22 @@
+-- name: CreateNotification :one
+INSERT INTO notifications (user_id, kind, title, body, data)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListNotifications :many
+SELECT * FROM notifications
+WHERE user_id = sqlc.arg(user_id)
+  AND (NOT sqlc.arg(unread_only)::bool OR read_at IS NULL)
+ORDER BY created_at DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: MarkNotificationRead :execrows
+UPDATE notifications SET read_at = COALESCE(read_at, NOW())
+WHERE id = $1 AND user_id = $2;
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications SET read_at = NOW()
+WHERE user_id = $1 AND read_at IS NULL;
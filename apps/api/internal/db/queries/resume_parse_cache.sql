@@ -0,0 +1,26 @@
+-- name: GetResumeParseCacheEntry :one
+SELECT * FROM resume_parse_cache
+WHERE content_hash = $1 AND prompt_version = $2 AND expires_at > NOW()
+LIMIT 1;
+
+-- name: GetCachedResumeText :one
+-- Extracted text does not depend on the prompt, so any live entry will do
+SELECT extracted_text FROM resume_parse_cache
+WHERE content_hash = $1 AND expires_at > NOW()
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: UpsertResumeParseCacheEntry :exec
+INSERT INTO resume_parse_cache (
+  content_hash, prompt_version, extracted_text, result, expires_at
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (content_hash, prompt_version) DO UPDATE
+SET extracted_text = EXCLUDED.extracted_text,
+    result = EXCLUDED.result,
+    expires_at = EXCLUDED.expires_at,
+    created_at = NOW();
+
+-- name: DeleteExpiredResumeParseCache :execrows
+DELETE FROM resume_parse_cache WHERE expires_at <= NOW();
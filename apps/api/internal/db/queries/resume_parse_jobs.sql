@@ -0,0 +1,62 @@
+-- name: CreateResumeParseJob :one
+INSERT INTO resume_parse_jobs (
+  source_url, max_attempts, parser
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetResumeParseJob :one
+SELECT * FROM resume_parse_jobs WHERE id = $1 LIMIT 1;
+
+-- name: ClaimResumeParseJob :one
+-- SKIP LOCKED lets several workers poll the queue without blocking each other
+UPDATE resume_parse_jobs
+SET status = 'RUNNING', attempts = attempts + 1, locked_at = NOW(), updated_at = NOW()
+WHERE id = (
+    SELECT id FROM resume_parse_jobs
+    WHERE status = 'QUEUED' AND run_at <= NOW()
+    ORDER BY run_at
+    FOR UPDATE SKIP LOCKED
+    LIMIT 1
+)
+RETURNING *;
+
+-- name: CompleteResumeParseJob :exec
+UPDATE resume_parse_jobs
+SET status = 'SUCCEEDED', result = $2, last_error = NULL, locked_at = NULL,
+    completed_at = NOW(), updated_at = NOW()
+WHERE id = $1;
+
+-- name: RetryResumeParseJob :exec
+UPDATE resume_parse_jobs
+SET status = 'QUEUED', last_error = $2, run_at = $3, locked_at = NULL, updated_at = NOW()
+WHERE id = $1;
+
+-- name: FailResumeParseJob :exec
+UPDATE resume_parse_jobs
+SET status = 'FAILED', last_error = $2, locked_at = NULL,
+    completed_at = NOW(), updated_at = NOW()
+WHERE id = $1;
+
+-- name: TouchResumeParseJob :exec
+-- Heartbeat from the worker running a job, so the reaper leaves it alone
+UPDATE resume_parse_jobs
+SET locked_at = NOW()
+WHERE id = $1 AND status = 'RUNNING';
+
+-- name: RequeueStaleResumeParseJobs :execrows
+-- Jobs left RUNNING by a crashed worker go back on the queue while they
+-- have attempts left
+UPDATE resume_parse_jobs
+SET status = 'QUEUED', locked_at = NULL, updated_at = NOW()
+WHERE status = 'RUNNING' AND locked_at < $1 AND attempts < max_attempts;
+
+-- name: FailStaleResumeParseJobs :many
+-- A stale job on its last attempt fails instead, so a resume that crashes
+-- the worker cannot crash it forever
+UPDATE resume_parse_jobs
+SET status = 'FAILED', last_error = 'The worker stopped while parsing this resume',
+    locked_at = NULL, completed_at = NOW(), updated_at = NOW()
+WHERE status = 'RUNNING' AND locked_at < $1 AND attempts >= max_attempts
+RETURNING id;
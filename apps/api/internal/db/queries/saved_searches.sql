@@ -0,0 +1,48 @@
+-- name: CreateSavedSearch :one
+INSERT INTO saved_searches (user_id, name, query, filters, salary_currency, alerts)
+VALUES (sqlc.arg(user_id), sqlc.arg(name), sqlc.arg(query), sqlc.arg(filters), sqlc.narg(salary_currency), sqlc.arg(alerts))
+RETURNING *;
+
+-- name: ListSavedSearches :many
+SELECT * FROM saved_searches WHERE user_id = $1 ORDER BY created_at DESC;
+
+-- name: GetSavedSearch :one
+SELECT * FROM saved_searches WHERE id = $1 AND user_id = $2;
+
+-- name: CountSavedSearches :one
+SELECT COUNT(*) FROM saved_searches WHERE user_id = $1;
+
+-- name: UpdateSavedSearch :one
+-- A new query, or alerts turned back on, restarts the alert clock so
+-- nobody is alerted about jobs posted before they asked
+UPDATE saved_searches
+SET name = sqlc.arg(name),
+    query = sqlc.arg(query),
+    filters = sqlc.arg(filters),
+    salary_currency = sqlc.narg(salary_currency),
+    last_notified_at = CASE WHEN sqlc.arg(query) <> query OR (sqlc.arg(alerts)::bool AND NOT alerts) THEN NOW() ELSE last_notified_at END,
+    alerts = sqlc.arg(alerts),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id) AND user_id = sqlc.arg(user_id)
+RETURNING *;
+
+-- name: DeleteSavedSearch :execrows
+DELETE FROM saved_searches WHERE id = $1 AND user_id = $2;
+
+-- name: ClaimSavedSearchAlerts :many
+-- Saved searches whose alert is due, moving their clock to now; see
+-- ClaimJobPreferenceDigests. target_rate is today's rate for the search's
+-- salary_currency, NULL when it has none or the rate has gone.
+WITH due AS (
+  SELECT id, last_notified_at FROM saved_searches
+  WHERE alerts AND last_notified_at <= NOW() - make_interval(days => sqlc.arg(interval_days)::int)
+  ORDER BY last_notified_at
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE SKIP LOCKED
+)
+UPDATE saved_searches s SET last_notified_at = NOW()
+FROM due
+WHERE s.id = due.id
+RETURNING s.id, s.user_id, s.name, s.filters, s.salary_currency,
+  (SELECT units_per_usd::float8 FROM exchange_rates WHERE currency = s.salary_currency) AS target_rate,
+  due.last_notified_at AS since;
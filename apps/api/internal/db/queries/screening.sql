@@ -0,0 +1,90 @@
+-- name: ListScreeningQuestionsByJob :many
+SELECT * FROM job_screening_questions
+WHERE job_id = $1
+ORDER BY position;
+
+-- name: GetScreeningQuestion :one
+SELECT * FROM job_screening_questions WHERE id = $1 LIMIT 1;
+
+-- name: CreateScreeningQuestion :one
+INSERT INTO job_screening_questions (
+  job_id, position, kind, prompt, options, required, knockout_answer, rubric
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+)
+RETURNING *;
+
+-- name: UpdateScreeningQuestion :one
+-- Edits a question in place, so answers already given keep pointing at it
+UPDATE job_screening_questions
+SET position = $3, kind = $4, prompt = $5, options = $6, required = $7,
+    knockout_answer = $8, rubric = $9
+WHERE id = $1 AND job_id = $2
+RETURNING *;
+
+-- name: DeleteScreeningQuestionsExcept :exec
+DELETE FROM job_screening_questions
+WHERE job_id = sqlc.arg(job_id) AND NOT (id = ANY(sqlc.arg(keep_ids)::uuid[]));
+
+-- name: CreateApplicationAnswer :one
+INSERT INTO application_answers (
+  application_id, question_id, question_prompt, answer, grade_status
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: DeleteApplicationAnswers :exec
+DELETE FROM application_answers WHERE application_id = $1;
+
+-- name: ListApplicationAnswers :many
+SELECT * FROM application_answers
+WHERE application_id = $1
+ORDER BY created_at, id;
+
+-- name: ClaimAnswerForGrading :one
+-- SKIP LOCKED lets several instances grade without blocking each other
+UPDATE application_answers
+SET grade_status = 'RUNNING', attempts = attempts + 1, locked_at = NOW()
+WHERE id = (
+    SELECT id FROM application_answers
+    WHERE grade_status = 'PENDING' AND run_at <= NOW()
+    ORDER BY run_at
+    FOR UPDATE SKIP LOCKED
+    LIMIT 1
+)
+RETURNING *;
+
+-- name: CompleteAnswerGrade :exec
+UPDATE application_answers
+SET grade_status = 'GRADED', grade = $2, rationale = $3, locked_at = NULL, graded_at = NOW()
+WHERE id = $1;
+
+-- name: RetryAnswerGrade :exec
+UPDATE application_answers
+SET grade_status = 'PENDING', run_at = $2, locked_at = NULL
+WHERE id = $1;
+
+-- name: FailAnswerGrade :exec
+UPDATE application_answers
+SET grade_status = 'FAILED', rationale = $2, locked_at = NULL
+WHERE id = $1;
+
+-- name: RequeueStaleAnswerGrades :execrows
+-- Answers left RUNNING by a crashed worker go back on the queue
+UPDATE application_answers
+SET grade_status = 'PENDING', locked_at = NULL
+WHERE grade_status = 'RUNNING' AND locked_at < $1;
+
+-- name: UpdateApplicationGatewayGrade :exec
+UPDATE applications
+SET gateway_grade = (
+    SELECT ROUND(AVG(grade))::int FROM application_answers
+    WHERE application_id = sqlc.arg(application_id) AND grade_status = 'GRADED'
+), updated_at = NOW()
+WHERE id = sqlc.arg(application_id);
+
+-- name: RejectApplication :exec
+UPDATE applications
+SET status = 'REJECTED', rejection_reason = $2, closed_at = NOW(), updated_at = NOW()
+WHERE id = $1;
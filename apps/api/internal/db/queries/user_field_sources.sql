@@ -0,0 +1,10 @@
+-- name: ListUserFieldSources :many
+SELECT * FROM user_field_sources
+WHERE user_id = $1
+ORDER BY field;
+
+-- name: UpsertUserFieldSource :exec
+INSERT INTO user_field_sources (user_id, field, source, parse_job_id)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, field) DO UPDATE
+SET source = EXCLUDED.source, parse_job_id = EXCLUDED.parse_job_id, updated_at = NOW();
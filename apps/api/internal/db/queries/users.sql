@@ -6,24 +6,24 @@ INSERT INTO users (
 )
 RETURNING id, wallet_address, email, role, full_name, password_hash, bio, skills, 
           experience, projects, education, job_role, phone, organization_name, 
-          organization_location, organization_bio, professional_email, created_at, updated_at;
+          organization_location, organization_bio, professional_email, location, created_at, updated_at;
 
 -- name: GetUserByWallet :one
 SELECT id, wallet_address, email, role, full_name, password_hash, bio, skills, 
        experience, projects, education, job_role, phone, organization_name, 
-       organization_location, organization_bio, professional_email, created_at, updated_at
+       organization_location, organization_bio, professional_email, location, created_at, updated_at
 FROM users WHERE wallet_address = $1 LIMIT 1;
 
 -- name: GetUserByID :one
 SELECT id, wallet_address, email, role, full_name, password_hash, bio, skills, 
        experience, projects, education, job_role, phone, organization_name, 
-       organization_location, organization_bio, professional_email, created_at, updated_at
+       organization_location, organization_bio, professional_email, location, created_at, updated_at
 FROM users WHERE id = $1 LIMIT 1;
 
 -- name: GetUserByEmail :one
 SELECT id, wallet_address, email, role, full_name, password_hash, bio, skills, 
        experience, projects, education, job_role, phone, organization_name, 
-       organization_location, organization_bio, professional_email, created_at, updated_at
+       organization_location, organization_bio, professional_email, location, created_at, updated_at
 FROM users WHERE email = $1 LIMIT 1;
 
 -- name: UpdateSeekerProfile :one
@@ -37,11 +37,12 @@ SET
   projects = COALESCE($6, projects),
   education = COALESCE($7, education),
   job_role = COALESCE($8, job_role),
-  professional_email = COALESCE($9, professional_email) -- <-- NEW
+  professional_email = COALESCE($9, professional_email), -- <-- NEW
+  location = COALESCE($10, location)
 WHERE id = $1
 RETURNING id, wallet_address, email, role, full_name, password_hash, bio, skills, 
           experience, projects, education, job_role, phone, organization_name, 
-          organization_location, organization_bio, professional_email, created_at, updated_at;
+          organization_location, organization_bio, professional_email, location, created_at, updated_at;
 
 -- name: UpdateRecruiterProfile :one
 UPDATE users
@@ -61,24 +62,76 @@ SET
 WHERE id = $1
 RETURNING id, wallet_address, email, role, full_name, password_hash, bio, skills, 
           experience, projects, education, job_role, phone, organization_name, 
-          organization_location, organization_bio, professional_email, created_at, updated_at;
+          organization_location, organization_bio, professional_email, location, created_at, updated_at;
 
 -- name: SearchCandidates :many
+-- Full-text matches on the weighted search_vector, plus fuzzy and partial
+-- matches on name and skills (e.g. 'Reac' or 'Raect' finding 'React'),
+-- which the trigram indexes serve
 SELECT id, wallet_address, email, role, full_name, password_hash, bio, skills, 
        experience, projects, education, job_role, phone, organization_name, 
-       organization_location, organization_bio, professional_email, created_at, updated_at
+       organization_location, organization_bio, professional_email, location, created_at, updated_at
 FROM users 
 WHERE role = 'CANDIDATE' 
   AND (
-    to_tsvector('english', 
-      COALESCE(full_name, '') || ' ' || 
-      COALESCE(skills, '') || ' ' || 
-      COALESCE(job_role, '') || ' ' || 
-      COALESCE(bio, '')
-    ) @@ websearch_to_tsquery('english', $1)
-    OR
-    -- Fallback for partial word matches (e.g. 'Reac' matching 'React') which TSVECTOR might miss if not stemmed
-    full_name ILIKE '%' || $1 || '%' OR
-    skills ILIKE '%' || $1 || '%'
+    search_vector @@ websearch_to_tsquery('english', $1)
+    OR full_name % $1
+    OR full_name ILIKE '%' || $1 || '%'
+    OR skills ILIKE '%' || $1 || '%'
   )
-LIMIT 20;
\ No newline at end of file
+ORDER BY ts_rank_cd(search_vector, websearch_to_tsquery('english', $1))
+         + GREATEST(similarity(COALESCE(full_name, ''), $1), word_similarity($1, COALESCE(skills, ''))) DESC,
+         updated_at DESC
+LIMIT 20;
+
+-- name: ApplyProfileFields :one
+-- Sets exactly the fields whose set_* flag is true, including to NULL, so
+-- accepted resume values can also clear a field
+UPDATE users
+SET
+  full_name = CASE WHEN sqlc.arg(set_full_name)::bool THEN sqlc.narg(full_name)::text ELSE full_name END,
+  job_role = CASE WHEN sqlc.arg(set_job_role)::bool THEN sqlc.narg(job_role)::text ELSE job_role END,
+  bio = CASE WHEN sqlc.arg(set_bio)::bool THEN sqlc.narg(bio)::text ELSE bio END,
+  skills = CASE WHEN sqlc.arg(set_skills)::bool THEN sqlc.narg(skills)::text ELSE skills END,
+  experience = CASE WHEN sqlc.arg(set_experience)::bool THEN sqlc.narg(experience)::text ELSE experience END,
+  education = CASE WHEN sqlc.arg(set_education)::bool THEN sqlc.narg(education)::text ELSE education END,
+  projects = CASE WHEN sqlc.arg(set_projects)::bool THEN sqlc.narg(projects)::jsonb ELSE projects END,
+  phone = CASE WHEN sqlc.arg(set_phone)::bool THEN sqlc.narg(phone)::text ELSE phone END,
+  professional_email = CASE WHEN sqlc.arg(set_professional_email)::bool THEN sqlc.narg(professional_email)::text ELSE professional_email END,
+  updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING id, wallet_address, email, role, full_name, password_hash, bio, skills, 
+          experience, projects, education, job_role, phone, organization_name, 
+          organization_location, organization_bio, professional_email, location, created_at, updated_at;
+
+-- name: FilterCandidates :many
+-- Tracer's structured search: every filter that is set must match, and
+-- matches are ranked by the role, skills and keywords in terms, plus how
+-- closely job_role resembles the requested role. location matches the
+-- start of what candidates typed or any spelling of the place it names;
+-- with a center, candidates must live within radius_km of it.
+SELECT id, full_name, email, professional_email, job_role, skills, experience,
+       experience_years, education, location, bio,
+       (ts_rank_cd(search_vector, websearch_to_tsquery('english', sqlc.arg(terms)::text))
+        + CASE WHEN sqlc.narg(job_role)::text IS NULL THEN 0
+               ELSE similarity(COALESCE(job_role, ''), sqlc.narg(job_role)) END)::float8 AS rank,
+       COUNT(*) OVER () AS total
+FROM users
+WHERE role = 'CANDIDATE'
+  -- strpos and starts_with, unlike LIKE, take % and _ in the input literally
+  AND (sqlc.narg(job_role)::text IS NULL OR strpos(lower(job_role), lower(sqlc.narg(job_role))) > 0)
+  -- skill_patterns are regexes, one per required skill
+  AND NOT EXISTS (
+    SELECT 1 FROM unnest(sqlc.arg(skill_patterns)::text[]) AS p
+    WHERE COALESCE(users.skills, '') || ' ' || COALESCE(users.bio, '') !~* p
+  )
+  AND experience_years >= sqlc.arg(min_experience)::int
+  AND (sqlc.narg(location)::text IS NULL OR starts_with(lower(location), lower(sqlc.narg(location)))
+       OR location_id = resolve_location(sqlc.narg(location)))
+  AND (sqlc.narg(center_lat)::float8 IS NULL OR EXISTS (
+    SELECT 1 FROM locations l
+    WHERE l.id = users.location_id
+      AND distance_km(l.latitude, l.longitude, sqlc.narg(center_lat)::float8, sqlc.narg(center_lng)::float8) <= sqlc.arg(radius_km)::float8
+  ))
+ORDER BY rank DESC, experience_years DESC, updated_at DESC, id
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
@@ -0,0 +1,55 @@
+-- name: ListWorkPositionsByUser :many
+SELECT * FROM work_positions
+WHERE user_id = $1
+ORDER BY end_date DESC NULLS FIRST, start_date DESC;
+
+-- name: CreateWorkPosition :one
+INSERT INTO work_positions (
+  user_id, company, title, start_date, end_date, description
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: UpdateWorkPosition :one
+UPDATE work_positions
+SET company = $3, title = $4, start_date = $5, end_date = $6, description = $7, updated_at = NOW()
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteWorkPosition :execrows
+DELETE FROM work_positions WHERE id = $1 AND user_id = $2;
+
+-- name: DeleteWorkPositionsByUser :exec
+DELETE FROM work_positions WHERE user_id = $1;
+
+-- name: ListEducationEntriesByUser :many
+SELECT * FROM education_entries
+WHERE user_id = $1
+ORDER BY graduation_year DESC NULLS FIRST, created_at DESC;
+
+-- name: CreateEducationEntry :one
+INSERT INTO education_entries (
+  user_id, institution, degree, field_of_study, graduation_year
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: UpdateEducationEntry :one
+UPDATE education_entries
+SET institution = $3, degree = $4, field_of_study = $5, graduation_year = $6, updated_at = NOW()
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteEducationEntry :execrows
+DELETE FROM education_entries WHERE id = $1 AND user_id = $2;
+
+-- name: DeleteEducationEntriesByUser :exec
+DELETE FROM education_entries WHERE user_id = $1;
+
+-- name: SetUserExperienceSummary :exec
+UPDATE users SET experience = $2, updated_at = NOW() WHERE id = $1;
+
+-- name: SetUserEducationSummary :exec
+UPDATE users SET education = $2, updated_at = NOW() WHERE id = $1;
@@ -11,6 +11,120 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const applyProfileFields = `-- name: ApplyProfileFields :one
+UPDATE users
+SET
+  full_name = CASE WHEN $1::bool THEN $2::text ELSE full_name END,
+  job_role = CASE WHEN $3::bool THEN $4::text ELSE job_role END,
+  bio = CASE WHEN $5::bool THEN $6::text ELSE bio END,
+  skills = CASE WHEN $7::bool THEN $8::text ELSE skills END,
+  experience = CASE WHEN $9::bool THEN $10::text ELSE experience END,
+  education = CASE WHEN $11::bool THEN $12::text ELSE education END,
+  projects = CASE WHEN $13::bool THEN $14::jsonb ELSE projects END,
+  phone = CASE WHEN $15::bool THEN $16::text ELSE phone END,
+  professional_email = CASE WHEN $17::bool THEN $18::text ELSE professional_email END,
+  updated_at = NOW()
+WHERE id = $19
+RETURNING id, wallet_address, email, role, full_name, password_hash, bio, skills, 
+          experience, projects, education, job_role, phone, organization_name, 
+          organization_location, organization_bio, professional_email, location, created_at, updated_at
+`
+
+type ApplyProfileFieldsParams struct {
+	SetFullName          bool        `json:"set_full_name"`
+	FullName             pgtype.Text `json:"full_name"`
+	SetJobRole           bool        `json:"set_job_role"`
+	JobRole              pgtype.Text `json:"job_role"`
+	SetBio               bool        `json:"set_bio"`
+	Bio                  pgtype.Text `json:"bio"`
+	SetSkills            bool        `json:"set_skills"`
+	Skills               pgtype.Text `json:"skills"`
+	SetExperience        bool        `json:"set_experience"`
+	Experience           pgtype.Text `json:"experience"`
+	SetEducation         bool        `json:"set_education"`
+	Education            pgtype.Text `json:"education"`
+	SetProjects          bool        `json:"set_projects"`
+	Projects             []byte      `json:"projects"`
+	SetPhone             bool        `json:"set_phone"`
+	Phone                pgtype.Text `json:"phone"`
+	SetProfessionalEmail bool        `json:"set_professional_email"`
+	ProfessionalEmail    pgtype.Text `json:"professional_email"`
+	ID                   pgtype.UUID `json:"id"`
+}
+
+type ApplyProfileFieldsRow struct {
+	ID                   pgtype.UUID        `json:"id"`
+	WalletAddress        pgtype.Text        `json:"wallet_address"`
+	Email                pgtype.Text        `json:"email"`
+	Role                 UserRole           `json:"role"`
+	FullName             pgtype.Text        `json:"full_name"`
+	PasswordHash         pgtype.Text        `json:"password_hash"`
+	Bio                  pgtype.Text        `json:"bio"`
+	Skills               pgtype.Text        `json:"skills"`
+	Experience           pgtype.Text        `json:"experience"`
+	Projects             []byte             `json:"projects"`
+	Education            pgtype.Text        `json:"education"`
+	JobRole              pgtype.Text        `json:"job_role"`
+	Phone                pgtype.Text        `json:"phone"`
+	OrganizationName     pgtype.Text        `json:"organization_name"`
+	OrganizationLocation pgtype.Text        `json:"organization_location"`
+	OrganizationBio      pgtype.Text        `json:"organization_bio"`
+	ProfessionalEmail    pgtype.Text        `json:"professional_email"`
+	Location             pgtype.Text        `json:"location"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+}
+
+// Sets exactly the fields whose set_* flag is true, including to NULL, so
+// accepted resume values can also clear a field
+func (q *Queries) ApplyProfileFields(ctx context.Context, arg ApplyProfileFieldsParams) (ApplyProfileFieldsRow, error) {
+	row := q.db.QueryRow(ctx, applyProfileFields,
+		arg.SetFullName,
+		arg.FullName,
+		arg.SetJobRole,
+		arg.JobRole,
+		arg.SetBio,
+		arg.Bio,
+		arg.SetSkills,
+		arg.Skills,
+		arg.SetExperience,
+		arg.Experience,
+		arg.SetEducation,
+		arg.Education,
+		arg.SetProjects,
+		arg.Projects,
+		arg.SetPhone,
+		arg.Phone,
+		arg.SetProfessionalEmail,
+		arg.ProfessionalEmail,
+		arg.ID,
+	)
+	var i ApplyProfileFieldsRow
+	err := row.Scan(
+		&i.ID,
+		&i.WalletAddress,
+		&i.Email,
+		&i.Role,
+		&i.FullName,
+		&i.PasswordHash,
+		&i.Bio,
+		&i.Skills,
+		&i.Experience,
+		&i.Projects,
+		&i.Education,
+		&i.JobRole,
+		&i.Phone,
+		&i.OrganizationName,
+		&i.OrganizationLocation,
+		&i.OrganizationBio,
+		&i.ProfessionalEmail,
+		&i.Location,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
   wallet_address, email, role, full_name, password_hash
@@ -19,7 +133,7 @@ INSERT INTO users (
 )
 RETURNING id, wallet_address, email, role, full_name, password_hash, bio, skills, 
           experience, projects, education, job_role, phone, organization_name, 
-          organization_location, organization_bio, professional_email, created_at, updated_at
+          organization_location, organization_bio, professional_email, location, created_at, updated_at
 `
 
 type CreateUserParams struct {
@@ -48,6 +162,7 @@ type CreateUserRow struct {
 	OrganizationLocation pgtype.Text        `json:"organization_location"`
 	OrganizationBio      pgtype.Text        `json:"organization_bio"`
 	ProfessionalEmail    pgtype.Text        `json:"professional_email"`
+	Location             pgtype.Text        `json:"location"`
 	CreatedAt            pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
 }
@@ -79,16 +194,124 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (CreateU
 		&i.OrganizationLocation,
 		&i.OrganizationBio,
 		&i.ProfessionalEmail,
+		&i.Location,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
 
+const filterCandidates = `-- name: FilterCandidates :many
+SELECT id, full_name, email, professional_email, job_role, skills, experience,
+       experience_years, education, location, bio,
+       (ts_rank_cd(search_vector, websearch_to_tsquery('english', $1::text))
+        + CASE WHEN $2::text IS NULL THEN 0
+               ELSE similarity(COALESCE(job_role, ''), $2) END)::float8 AS rank,
+       COUNT(*) OVER () AS total
+FROM users
+WHERE role = 'CANDIDATE'
+  -- strpos and starts_with, unlike LIKE, take % and _ in the input literally
+  AND ($2::text IS NULL OR strpos(lower(job_role), lower($2)) > 0)
+  -- skill_patterns are regexes, one per required skill
+  AND NOT EXISTS (
+    SELECT 1 FROM unnest($3::text[]) AS p
+    WHERE COALESCE(users.skills, '') || ' ' || COALESCE(users.bio, '') !~* p
+  )
+  AND experience_years >= $4::int
+  AND ($5::text IS NULL OR starts_with(lower(location), lower($5))
+       OR location_id = resolve_location($5))
+  AND ($6::float8 IS NULL OR EXISTS (
+    SELECT 1 FROM locations l
+    WHERE l.id = users.location_id
+      AND distance_km(l.latitude, l.longitude, $6::float8, $7::float8) <= $8::float8
+  ))
+ORDER BY rank DESC, experience_years DESC, updated_at DESC, id
+LIMIT $9 OFFSET $10
+`
+
+type FilterCandidatesParams struct {
+	Terms         string        `json:"terms"`
+	JobRole       pgtype.Text   `json:"job_role"`
+	SkillPatterns []string      `json:"skill_patterns"`
+	MinExperience int32         `json:"min_experience"`
+	Location      pgtype.Text   `json:"location"`
+	CenterLat     pgtype.Float8 `json:"center_lat"`
+	CenterLng     pgtype.Float8 `json:"center_lng"`
+	RadiusKm      float64       `json:"radius_km"`
+	PageLimit     int32         `json:"page_limit"`
+	PageOffset    int32         `json:"page_offset"`
+}
+
+type FilterCandidatesRow struct {
+	ID                pgtype.UUID `json:"id"`
+	FullName          pgtype.Text `json:"full_name"`
+	Email             pgtype.Text `json:"email"`
+	ProfessionalEmail pgtype.Text `json:"professional_email"`
+	JobRole           pgtype.Text `json:"job_role"`
+	Skills            pgtype.Text `json:"skills"`
+	Experience        pgtype.Text `json:"experience"`
+	ExperienceYears   pgtype.Int4 `json:"experience_years"`
+	Education         pgtype.Text `json:"education"`
+	Location          pgtype.Text `json:"location"`
+	Bio               pgtype.Text `json:"bio"`
+	Rank              float64     `json:"rank"`
+	Total             int64       `json:"total"`
+}
+
+// Tracer's structured search: every filter that is set must match, and
+// matches are ranked by the role, skills and keywords in terms, plus how
+// closely job_role resembles the requested role. location matches the
+// start of what candidates typed or any spelling of the place it names;
+// with a center, candidates must live within radius_km of it.
+func (q *Queries) FilterCandidates(ctx context.Context, arg FilterCandidatesParams) ([]FilterCandidatesRow, error) {
+	rows, err := q.db.Query(ctx, filterCandidates,
+		arg.Terms,
+		arg.JobRole,
+		arg.SkillPatterns,
+		arg.MinExperience,
+		arg.Location,
+		arg.CenterLat,
+		arg.CenterLng,
+		arg.RadiusKm,
+		arg.PageLimit,
+		arg.PageOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FilterCandidatesRow
+	for rows.Next() {
+		var i FilterCandidatesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FullName,
+			&i.Email,
+			&i.ProfessionalEmail,
+			&i.JobRole,
+			&i.Skills,
+			&i.Experience,
+			&i.ExperienceYears,
+			&i.Education,
+			&i.Location,
+			&i.Bio,
+			&i.Rank,
+			&i.Total,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUserByEmail = `-- name: GetUserByEmail :one
 SELECT id, wallet_address, email, role, full_name, password_hash, bio, skills, 
        experience, projects, education, job_role, phone, organization_name, 
-       organization_location, organization_bio, professional_email, created_at, updated_at
+       organization_location, organization_bio, professional_email, location, created_at, updated_at
 FROM users WHERE email = $1 LIMIT 1
 `
 
@@ -110,6 +333,7 @@ type GetUserByEmailRow struct {
 	OrganizationLocation pgtype.Text        `json:"organization_location"`
 	OrganizationBio      pgtype.Text        `json:"organization_bio"`
 	ProfessionalEmail    pgtype.Text        `json:"professional_email"`
+	Location             pgtype.Text        `json:"location"`
 	CreatedAt            pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
 }
@@ -135,6 +359,7 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email pgtype.Text) (GetUse
 		&i.OrganizationLocation,
 		&i.OrganizationBio,
 		&i.ProfessionalEmail,
+		&i.Location,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -144,7 +369,7 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email pgtype.Text) (GetUse
 const getUserByID = `-- name: GetUserByID :one
 SELECT id, wallet_address, email, role, full_name, password_hash, bio, skills, 
        experience, projects, education, job_role, phone, organization_name, 
-       organization_location, organization_bio, professional_email, created_at, updated_at
+       organization_location, organization_bio, professional_email, location, created_at, updated_at
 FROM users WHERE id = $1 LIMIT 1
 `
 
@@ -166,6 +391,7 @@ type GetUserByIDRow struct {
 	OrganizationLocation pgtype.Text        `json:"organization_location"`
 	OrganizationBio      pgtype.Text        `json:"organization_bio"`
 	ProfessionalEmail    pgtype.Text        `json:"professional_email"`
+	Location             pgtype.Text        `json:"location"`
 	CreatedAt            pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
 }
@@ -191,6 +417,7 @@ func (q *Queries) GetUserByID(ctx context.Context, id pgtype.UUID) (GetUserByIDR
 		&i.OrganizationLocation,
 		&i.OrganizationBio,
 		&i.ProfessionalEmail,
+		&i.Location,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -200,7 +427,7 @@ func (q *Queries) GetUserByID(ctx context.Context, id pgtype.UUID) (GetUserByIDR
 const getUserByWallet = `-- name: GetUserByWallet :one
 SELECT id, wallet_address, email, role, full_name, password_hash, bio, skills, 
        experience, projects, education, job_role, phone, organization_name, 
-       organization_location, organization_bio, professional_email, created_at, updated_at
+       organization_location, organization_bio, professional_email, location, created_at, updated_at
 FROM users WHERE wallet_address = $1 LIMIT 1
 `
 
@@ -222,6 +449,7 @@ type GetUserByWalletRow struct {
 	OrganizationLocation pgtype.Text        `json:"organization_location"`
 	OrganizationBio      pgtype.Text        `json:"organization_bio"`
 	ProfessionalEmail    pgtype.Text        `json:"professional_email"`
+	Location             pgtype.Text        `json:"location"`
 	CreatedAt            pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
 }
@@ -247,6 +475,7 @@ func (q *Queries) GetUserByWallet(ctx context.Context, walletAddress pgtype.Text
 		&i.OrganizationLocation,
 		&i.OrganizationBio,
 		&i.ProfessionalEmail,
+		&i.Location,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -256,21 +485,18 @@ func (q *Queries) GetUserByWallet(ctx context.Context, walletAddress pgtype.Text
 const searchCandidates = `-- name: SearchCandidates :many
 SELECT id, wallet_address, email, role, full_name, password_hash, bio, skills, 
        experience, projects, education, job_role, phone, organization_name, 
-       organization_location, organization_bio, professional_email, created_at, updated_at
+       organization_location, organization_bio, professional_email, location, created_at, updated_at
 FROM users 
 WHERE role = 'CANDIDATE' 
   AND (
-    to_tsvector('english', 
-      COALESCE(full_name, '') || ' ' || 
-      COALESCE(skills, '') || ' ' || 
-      COALESCE(job_role, '') || ' ' || 
-      COALESCE(bio, '')
-    ) @@ websearch_to_tsquery('english', $1)
-    OR
-    -- Fallback for partial word matches (e.g. 'Reac' matching 'React') which TSVECTOR might miss if not stemmed
-    full_name ILIKE '%' || $1 || '%' OR
-    skills ILIKE '%' || $1 || '%'
+    search_vector @@ websearch_to_tsquery('english', $1)
+    OR full_name % $1
+    OR full_name ILIKE '%' || $1 || '%'
+    OR skills ILIKE '%' || $1 || '%'
   )
+ORDER BY ts_rank_cd(search_vector, websearch_to_tsquery('english', $1))
+         + GREATEST(similarity(COALESCE(full_name, ''), $1), word_similarity($1, COALESCE(skills, ''))) DESC,
+         updated_at DESC
 LIMIT 20
 `
 
@@ -292,10 +518,14 @@ type SearchCandidatesRow struct {
 	OrganizationLocation pgtype.Text        `json:"organization_location"`
 	OrganizationBio      pgtype.Text        `json:"organization_bio"`
 	ProfessionalEmail    pgtype.Text        `json:"professional_email"`
+	Location             pgtype.Text        `json:"location"`
 	CreatedAt            pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
 }
 
+// Full-text matches on the weighted search_vector, plus fuzzy and partial
+// matches on name and skills (e.g. 'Reac' or 'Raect' finding 'React'),
+// which the trigram indexes serve
 func (q *Queries) SearchCandidates(ctx context.Context, websearchToTsquery string) ([]SearchCandidatesRow, error) {
 	rows, err := q.db.Query(ctx, searchCandidates, websearchToTsquery)
 	if err != nil {
@@ -323,6 +553,7 @@ func (q *Queries) SearchCandidates(ctx context.Context, websearchToTsquery strin
 			&i.OrganizationLocation,
 			&i.OrganizationBio,
 			&i.ProfessionalEmail,
+			&i.Location,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -354,7 +585,7 @@ SET
 WHERE id = $1
 RETURNING id, wallet_address, email, role, full_name, password_hash, bio, skills, 
           experience, projects, education, job_role, phone, organization_name, 
-          organization_location, organization_bio, professional_email, created_at, updated_at
+          organization_location, organization_bio, professional_email, location, created_at, updated_at
 `
 
 type UpdateRecruiterProfileParams struct {
@@ -390,6 +621,7 @@ type UpdateRecruiterProfileRow struct {
 	OrganizationLocation pgtype.Text        `json:"organization_location"`
 	OrganizationBio      pgtype.Text        `json:"organization_bio"`
 	ProfessionalEmail    pgtype.Text        `json:"professional_email"`
+	Location             pgtype.Text        `json:"location"`
 	CreatedAt            pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
 }
@@ -428,6 +660,7 @@ func (q *Queries) UpdateRecruiterProfile(ctx context.Context, arg UpdateRecruite
 		&i.OrganizationLocation,
 		&i.OrganizationBio,
 		&i.ProfessionalEmail,
+		&i.Location,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -445,11 +678,12 @@ SET
   projects = COALESCE($6, projects),
   education = COALESCE($7, education),
   job_role = COALESCE($8, job_role),
-  professional_email = COALESCE($9, professional_email) -- <-- NEW
+  professional_email = COALESCE($9, professional_email), -- <-- NEW
+  location = COALESCE($10, location)
 WHERE id = $1
 RETURNING id, wallet_address, email, role, full_name, password_hash, bio, skills, 
           experience, projects, education, job_role, phone, organization_name, 
-          organization_location, organization_bio, professional_email, created_at, updated_at
+          organization_location, organization_bio, professional_email, location, created_at, updated_at
 `
 
 type UpdateSeekerProfileParams struct {
@@ -462,6 +696,7 @@ type UpdateSeekerProfileParams struct {
 	Education         pgtype.Text `json:"education"`
 	JobRole           pgtype.Text `json:"job_role"`
 	ProfessionalEmail pgtype.Text `json:"professional_email"`
+	Location          pgtype.Text `json:"location"`
 }
 
 type UpdateSeekerProfileRow struct {
@@ -482,6 +717,7 @@ type UpdateSeekerProfileRow struct {
 	OrganizationLocation pgtype.Text        `json:"organization_location"`
 	OrganizationBio      pgtype.Text        `json:"organization_bio"`
 	ProfessionalEmail    pgtype.Text        `json:"professional_email"`
+	Location             pgtype.Text        `json:"location"`
 	CreatedAt            pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
 }
@@ -497,6 +733,7 @@ func (q *Queries) UpdateSeekerProfile(ctx context.Context, arg UpdateSeekerProfi
 		arg.Education,
 		arg.JobRole,
 		arg.ProfessionalEmail,
+		arg.Location,
 	)
 	var i UpdateSeekerProfileRow
 	err := row.Scan(
@@ -517,6 +754,7 @@ func (q *Queries) UpdateSeekerProfile(ctx context.Context, arg UpdateSeekerProfi
 		&i.OrganizationLocation,
 		&i.OrganizationBio,
 		&i.ProfessionalEmail,
+		&i.Location,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -1,32 +1,74 @@
 package handlers
 
 import (
+	"errors"
+	"strings"
+	"time"
+
 	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/aswinbala005/rizeos/api/internal/services"
+	"github.com/aswinbala005/rizeos/api/internal/workers"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type ApplicationHandler struct {
 	queries  *db.Queries
+	pool     *pgxpool.Pool
+	grader   *workers.AnswerGrader
+	screener *workers.ApplicantScreener
+	sender   *workers.EmailSender
+	reapply  services.ReapplyPolicy
 	validate *validator.Validate
 }
 
-func NewApplicationHandler(queries *db.Queries) *ApplicationHandler {
+func NewApplicationHandler(queries *db.Queries, pool *pgxpool.Pool, grader *workers.AnswerGrader, screener *workers.ApplicantScreener, sender *workers.EmailSender, reapply services.ReapplyPolicy) *ApplicationHandler {
 	return &ApplicationHandler{
 		queries:  queries,
+		pool:     pool,
+		grader:   grader,
+		screener: screener,
+		sender:   sender,
+		reapply:  reapply,
 		validate: validator.New(),
 	}
 }
 
+// Error codes returned alongside the error message when an application
+// cannot be made or withdrawn
+const (
+	applyCodeNotCandidate      = "NOT_A_CANDIDATE"
+	applyCodeJobNotOpen        = "JOB_NOT_OPEN"
+	applyCodeAlreadyApplied    = "ALREADY_APPLIED"
+	applyCodeReapplyCooldown   = "REAPPLY_COOLDOWN"
+	applyCodeApplicationClosed = "APPLICATION_CLOSED" // already rejected or withdrawn
+)
+
+// errAlreadyApplied is returned inside ApplyToJob's transaction when
+// another request applied first
+var errAlreadyApplied = errors.New("already applied")
+
 type CreateApplicationRequest struct {
-	JobID         string `json:"job_id" validate:"required,uuid"`
-	CandidateID   string `json:"candidate_id" validate:"required,uuid"`
-	MatchScore    int32  `json:"match_score"`
-	GatewayAnswer string `json:"gateway_answer"`
+	JobID         string              `json:"job_id" validate:"required,uuid"`
+	CandidateID   string              `json:"candidate_id" validate:"required,uuid"`
+	MatchScore    int32               `json:"match_score"`
+	GatewayAnswer string              `json:"gateway_answer"`
+	Answers       []ScreeningAnswerIn `json:"answers" validate:"dive"`
 }
 
-// ApplyToJob handles the application submission
+// ScreeningAnswerIn is a candidate's answer to one of the job's questions
+type ScreeningAnswerIn struct {
+	QuestionID string `json:"question_id" validate:"required,uuid"`
+	Answer     string `json:"answer"`
+}
+
+// ApplyToJob handles the application submission. Only candidates can
+// apply, only to open jobs, and once per job: re-applying is allowed after
+// a rejection or withdrawal once the re-apply cooldown has passed, and
+// reopens the same application.
 func (h *ApplicationHandler) ApplyToJob(c *fiber.Ctx) error {
 	var req CreateApplicationRequest
 	if err := c.BodyParser(&req); err != nil {
@@ -45,6 +87,45 @@ func (h *ApplicationHandler) ApplyToJob(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Candidate ID"})
 	}
 
+	candidate, err := h.queries.GetUserByID(c.Context(), candidateUUID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Candidate not found"})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch candidate"})
+	}
+	if candidate.Role != db.UserRoleCANDIDATE {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": "Only candidate accounts can apply to jobs", "code": applyCodeNotCandidate})
+	}
+
+	job, err := h.queries.GetJobByID(c.Context(), jobUUID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Job not found"})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch job"})
+	}
+	if job.Status.String != "OPEN" {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": "This job is not accepting applications", "code": applyCodeJobNotOpen})
+	}
+
+	// A previous application is either still in progress, or closed and
+	// reopened by this one once the cooldown has passed
+	previous, err := h.queries.GetCandidateApplication(c.Context(), db.GetCandidateApplicationParams{JobID: jobUUID, CandidateID: candidateUUID})
+	found := err == nil
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to check previous applications"})
+	}
+	if found {
+		reapplyAt, ok := h.reapply.ReapplyAt(previous.Status, previous.ClosedAt.Time)
+		if !ok || !previous.ClosedAt.Valid {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "You have already applied to this job", "code": applyCodeAlreadyApplied, "application_id": previous.ID})
+		}
+		if time.Now().Before(reapplyAt) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "You can apply to this job again after " + reapplyAt.Format("2 Jan 2006"), "code": applyCodeReapplyCooldown, "application_id": previous.ID, "reapply_after": reapplyAt})
+		}
+	}
+
     // Set default match score (Requirements and match calculation removed)
     matchScore := int32(50)
 
@@ -56,11 +137,105 @@ func (h *ApplicationHandler) ApplyToJob(c *fiber.Ctx) error {
 		GatewayAnswer: pgtype.Text{String: req.GatewayAnswer, Valid: true},
 	}
 
-	app, err := h.queries.CreateApplication(c.Context(), arg)
+	// Check the screening answers before anything is written
+	questionRows, err := h.queries.ListScreeningQuestionsByJob(c.Context(), jobUUID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch screening questions"})
+	}
+	questions := make([]services.ScreeningQuestion, 0, len(questionRows))
+	for _, row := range questionRows {
+		questions = append(questions, screeningQuestionFromRow(row))
+	}
+	answers := make(map[string]string, len(req.Answers))
+	for _, a := range req.Answers {
+		answers[a.QuestionID] = a.Answer
+	}
+	eval, err := services.EvaluateAnswers(questions, answers)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var app db.Application
+	queued, cancelled := false, false
+	err = inTx(c.Context(), h.pool, h.queries, func(q *db.Queries) error {
+		var err error
+		if found {
+			app, err = q.ReopenApplication(c.Context(), db.ReopenApplicationParams{
+				ID:            previous.ID,
+				Status:        arg.Status,
+				MatchScore:    arg.MatchScore,
+				GatewayAnswer: arg.GatewayAnswer,
+			})
+			if err == nil {
+				err = q.DeleteApplicationAnswers(c.Context(), app.ID)
+			}
+		} else {
+			app, err = q.CreateApplication(c.Context(), arg)
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errAlreadyApplied
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, a := range eval.Answers {
+			var questionID pgtype.UUID
+			if err := questionID.Scan(a.Question.ID); err != nil {
+				return err
+			}
+			status := a.GradeStatus
+			if eval.KnockedOut != nil {
+				status = services.GradeNone // not worth grading a rejected application
+			}
+			if _, err := q.CreateApplicationAnswer(c.Context(), db.CreateApplicationAnswerParams{
+				ApplicationID:  app.ID,
+				QuestionID:     questionID,
+				QuestionPrompt: a.Question.Prompt,
+				Answer:         a.Answer,
+				GradeStatus:    status,
+			}); err != nil {
+				return err
+			}
+			queued = queued || status == services.GradePending
+		}
+
+		if err := q.EnqueueApplicationScreening(c.Context(), app.ID); err != nil {
+			return err
+		}
+
+		if eval.KnockedOut == nil {
+			return nil
+		}
+		reason := pgtype.Text{String: "knockout: " + eval.KnockedOut.Prompt, Valid: true}
+		if err := q.RejectApplication(c.Context(), db.RejectApplicationParams{ID: app.ID, RejectionReason: reason}); err != nil {
+			return err
+		}
+		if cancelled, err = cancelApplicationInterviews(c.Context(), q, app.ID, "The application was rejected"); err != nil {
+			return err
+		}
+		app.Status = "REJECTED"
+		app.RejectionReason = reason
+		app.ClosedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+		return nil
+	})
+	if errors.Is(err, errAlreadyApplied) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "You have already applied to this job", "code": applyCodeAlreadyApplied})
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to apply: " + err.Error()})
 	}
 
+	if queued && h.grader != nil {
+		h.grader.Notify()
+	}
+	if h.screener != nil {
+		h.screener.Notify()
+	}
+	if cancelled && h.sender != nil {
+		h.sender.Notify()
+	}
+
 	return c.Status(fiber.StatusCreated).JSON(app)
 }
 
@@ -80,7 +255,14 @@ func (h *ApplicationHandler) GetMyApplications(c *fiber.Ctx) error {
 	return c.JSON(apps)
 }
 
-// WithdrawApplication deletes an application
+// WithdrawApplicationRequest optionally says why the candidate withdrew
+type WithdrawApplicationRequest struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+// WithdrawApplication marks an application WITHDRAWN and cancels its
+// upcoming interviews. It stays in the recruiter's pipeline as withdrawn;
+// only data retention deletes it.
 func (h *ApplicationHandler) WithdrawApplication(c *fiber.Ctx) error {
 	appID := c.Params("id")
 	var uuid pgtype.UUID
@@ -88,33 +270,101 @@ func (h *ApplicationHandler) WithdrawApplication(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Application ID"})
 	}
 
-	err := h.queries.DeleteApplication(c.Context(), uuid)
+	var req WithdrawApplicationRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	reason := strings.TrimSpace(req.Reason)
+
+	var app db.Application
+	cancelled := false
+	err := inTx(c.Context(), h.pool, h.queries, func(q *db.Queries) error {
+		var err error
+		app, err = q.WithdrawApplication(c.Context(), db.WithdrawApplicationParams{
+			ID:               uuid,
+			WithdrawalReason: pgtype.Text{String: reason, Valid: reason != ""},
+		})
+		if err != nil {
+			return err
+		}
+		cancelled, err = cancelApplicationInterviews(c.Context(), q, app.ID, "The application was withdrawn")
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		current, err := h.queries.GetApplicationByID(c.Context(), uuid)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Application not found"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to withdraw application"})
+		}
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "Application is already " + strings.ToLower(current.Status), "code": applyCodeApplicationClosed})
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to withdraw application"})
 	}
+	if cancelled && h.sender != nil {
+		h.sender.Notify()
+	}
+
+	return c.JSON(fiber.Map{"message": "Application withdrawn successfully", "application": app})
+}
 
-	return c.JSON(fiber.Map{"message": "Application withdrawn successfully"})
+// jobApplication is an application in a job's list, with its review
+// summary when a member of the recruiting team asks
+type jobApplication struct {
+	db.GetApplicationsByJobRow
+	Review *db.ListApplicationReviewSummariesByJobRow `json:"review,omitempty"`
 }
 
-// GetJobApplications fetches all applications for a specific job
+// GetJobApplications fetches all applications for a specific job. Tags,
+// note counts and scorecard summaries are private to the recruiting team,
+// so they are only added for a ?user_id= on it.
 func (h *ApplicationHandler) GetJobApplications(c *fiber.Ctx) error {
 	jobID := c.Params("id")
 	var uuid pgtype.UUID
 	if err := uuid.Scan(jobID); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Job ID"})
 	}
+	var reviewerID pgtype.UUID
+	if userID := c.Query("user_id"); userID != "" {
+		if err := reviewerID.Scan(userID); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+		}
+		if err := checkJobTeam(c.Context(), h.queries, uuid, reviewerID, notReviewer); err != nil {
+			return sendError(c, err)
+		}
+	}
 
 	apps, err := h.queries.GetApplicationsByJob(c.Context(), uuid)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch applications"})
 	}
 
-	// Handle empty result
-	if apps == nil {
-		return c.JSON([]interface{}{})
+	list := make([]jobApplication, len(apps))
+	for i, app := range apps {
+		list[i].GetApplicationsByJobRow = app
+	}
+	if reviewerID.Valid && len(list) > 0 {
+		summaries, err := h.queries.ListApplicationReviewSummariesByJob(c.Context(), uuid)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch reviews"})
+		}
+		byApp := make(map[string]*db.ListApplicationReviewSummariesByJobRow, len(summaries))
+		for i := range summaries {
+			byApp[summaries[i].ApplicationID.String()] = &summaries[i]
+		}
+		for i := range list {
+			list[i].Review = byApp[list[i].ID.String()]
+		}
 	}
 
-	return c.JSON(apps)
+	return c.JSON(list)
 }
 
 // GetApplicationVolume fetches daily application counts for a recruiter
@@ -156,4 +406,58 @@ func (h *ApplicationHandler) GetRecruiterApplications(c *fiber.Ctx) error {
     }
 
 	return c.JSON(apps)
-}
\ No newline at end of file
+}
+
+// GetRecruiterScreenings returns Faye's screening of every application to a
+// recruiter's jobs (or one job, with ?job_id=), best first within each job.
+// Applications not screened yet have a null screening_status.
+func (h *ApplicationHandler) GetRecruiterScreenings(c *fiber.Ctx) error {
+	var recruiterID pgtype.UUID
+	if err := recruiterID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Recruiter ID"})
+	}
+	var jobID pgtype.UUID
+	if c.Query("job_id") != "" {
+		if err := jobID.Scan(c.Query("job_id")); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Job ID"})
+		}
+	}
+
+	rows, err := h.queries.ListRecruiterScreenings(c.Context(), db.ListRecruiterScreeningsParams{
+		RecruiterID: recruiterID,
+		JobID:       jobID,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch screenings"})
+	}
+	if rows == nil {
+		return c.JSON([]interface{}{})
+	}
+	for i := range rows {
+		if rows[i].Strengths == nil {
+			rows[i].Strengths = []string{}
+		}
+		if rows[i].Gaps == nil {
+			rows[i].Gaps = []string{}
+		}
+	}
+	return c.JSON(rows)
+}
+
+// RescreenJob queues every application to a job for Faye again, e.g. after
+// the job's requirements were edited
+func (h *ApplicationHandler) RescreenJob(c *fiber.Ctx) error {
+	var jobID pgtype.UUID
+	if err := jobID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Job ID"})
+	}
+
+	n, err := h.queries.EnqueueJobScreenings(c.Context(), jobID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to queue screenings"})
+	}
+	if h.screener != nil {
+		h.screener.Notify()
+	}
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{"queued": n})
+}
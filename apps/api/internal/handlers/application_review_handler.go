@@ -0,0 +1,590 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/aswinbala005/rizeos/api/internal/services"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ApplicationReviewHandler serves what recruiters record about applicants:
+// private notes, tags and interview scorecards. Only the job's recruiter
+// and recruiters from the same organization can read or write them.
+type ApplicationReviewHandler struct {
+	queries  *db.Queries
+	pool     *pgxpool.Pool
+	validate *validator.Validate
+}
+
+func NewApplicationReviewHandler(queries *db.Queries, pool *pgxpool.Pool) *ApplicationReviewHandler {
+	return &ApplicationReviewHandler{queries: queries, pool: pool, validate: validator.New()}
+}
+
+// notReviewer is the 403 message for anyone outside the recruiting team
+const notReviewer = "Only the job's recruiting team can review this application"
+
+// CreateNoteRequest posts a note; mentions are reviewers to notify
+type CreateNoteRequest struct {
+	AuthorID string   `json:"author_id" validate:"required,uuid"`
+	Body     string   `json:"body" validate:"required,max=5000"`
+	Mentions []string `json:"mentions" validate:"max=10,dive,uuid"`
+}
+
+// ListNotes: GET /applications/:id/notes?user_id=
+func (h *ApplicationReviewHandler) ListNotes(c *fiber.Ctx) error {
+	var appID pgtype.UUID
+	if err := appID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Application ID"})
+	}
+	var userID pgtype.UUID
+	if err := userID.Scan(c.Query("user_id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+	}
+	if err := checkRecruitingTeam(c.Context(), h.queries, appID, userID, notReviewer); err != nil {
+		return sendError(c, err)
+	}
+	notes, err := h.queries.ListApplicationNotes(c.Context(), appID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch notes"})
+	}
+	if notes == nil {
+		return c.JSON([]interface{}{})
+	}
+	return c.JSON(notes)
+}
+
+// CreateNote adds a note and notifies the reviewers it mentions:
+// POST /applications/:id/notes
+func (h *ApplicationReviewHandler) CreateNote(c *fiber.Ctx) error {
+	var appID pgtype.UUID
+	if err := appID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Application ID"})
+	}
+	var req CreateNoteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	req.Body = strings.TrimSpace(req.Body)
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	target, err := h.queries.GetApplicationReviewTarget(c.Context(), appID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Application not found"})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch application"})
+	}
+
+	var authorID pgtype.UUID
+	if err := authorID.Scan(req.AuthorID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Author ID"})
+	}
+	mentions := make([]pgtype.UUID, 0, len(req.Mentions))
+	seen := map[string]bool{}
+	for _, id := range req.Mentions {
+		var mention pgtype.UUID
+		if err := mention.Scan(id); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid mention"})
+		}
+		if seen[mention.String()] || mention == authorID {
+			continue
+		}
+		seen[mention.String()] = true
+		mentions = append(mentions, mention)
+	}
+
+	allowed, err := recruitingTeam(c.Context(), h.queries, appID, append([]pgtype.UUID{authorID}, mentions...))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to check reviewers"})
+	}
+	if !allowed[authorID.String()] {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Only the job's recruiting team can add notes"})
+	}
+	for _, mention := range mentions {
+		if !allowed[mention.String()] {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Mentions must be on the job's recruiting team: " + mention.String()})
+		}
+	}
+
+	note, err := h.queries.CreateApplicationNote(c.Context(), db.CreateApplicationNoteParams{
+		ApplicationID: appID,
+		AuthorID:      authorID,
+		Body:          req.Body,
+		Mentions:      mentions,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to save note"})
+	}
+
+	// Mention notifications are best effort; the note is saved either way
+	if len(mentions) > 0 {
+		author, err := h.queries.GetUserByID(c.Context(), authorID)
+		if err != nil {
+			log.Printf("notes: failed to fetch author %s: %v", authorID.String(), err)
+		}
+		n := services.ApplicationMention(appID.String(), note.ID.String(), author.FullName.String, target.CandidateName.String, target.JobTitle, note.Body)
+		for _, mention := range mentions {
+			if _, err := h.queries.CreateNotification(c.Context(), db.CreateNotificationParams{
+				UserID: mention,
+				Kind:   n.Kind,
+				Title:  n.Title,
+				Body:   n.Body,
+				Data:   n.Data,
+			}); err != nil {
+				log.Printf("notes: failed to notify %s: %v", mention.String(), err)
+			}
+		}
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(note)
+}
+
+// DeleteNote lets a note's author delete it:
+// DELETE /applications/:id/notes/:noteId?author_id=
+func (h *ApplicationReviewHandler) DeleteNote(c *fiber.Ctx) error {
+	var appID, noteID, authorID pgtype.UUID
+	if err := appID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Application ID"})
+	}
+	if err := noteID.Scan(c.Params("noteId")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Note ID"})
+	}
+	if err := authorID.Scan(c.Query("author_id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Author ID"})
+	}
+	deleted, err := h.queries.DeleteApplicationNote(c.Context(), db.DeleteApplicationNoteParams{
+		ID:            noteID,
+		ApplicationID: appID,
+		AuthorID:      authorID,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete note"})
+	}
+	if deleted == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Note not found"})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// SetTagsRequest replaces an application's tags
+type SetTagsRequest struct {
+	UserID string   `json:"user_id" validate:"required,uuid"`
+	Tags   []string `json:"tags"`
+}
+
+// ListTags: GET /applications/:id/tags?user_id=
+func (h *ApplicationReviewHandler) ListTags(c *fiber.Ctx) error {
+	var appID pgtype.UUID
+	if err := appID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Application ID"})
+	}
+	var userID pgtype.UUID
+	if err := userID.Scan(c.Query("user_id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+	}
+	if err := checkRecruitingTeam(c.Context(), h.queries, appID, userID, notReviewer); err != nil {
+		return sendError(c, err)
+	}
+	tags, err := h.queries.ListApplicationTags(c.Context(), appID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch tags"})
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+	return c.JSON(tags)
+}
+
+// SetTags replaces an application's tags: PUT /applications/:id/tags
+func (h *ApplicationReviewHandler) SetTags(c *fiber.Ctx) error {
+	var appID pgtype.UUID
+	if err := appID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Application ID"})
+	}
+	var req SetTagsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	tags, err := services.NormalizeTags(req.Tags)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var userID pgtype.UUID
+	if err := userID.Scan(req.UserID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+	}
+	if err := checkRecruitingTeam(c.Context(), h.queries, appID, userID, notReviewer); err != nil {
+		return sendError(c, err)
+	}
+
+	err = inTx(c.Context(), h.pool, h.queries, func(q *db.Queries) error {
+		if err := q.DeleteApplicationTags(c.Context(), appID); err != nil {
+			return err
+		}
+		return q.AddApplicationTags(c.Context(), db.AddApplicationTagsParams{
+			ApplicationID: appID,
+			Tags:          tags,
+			CreatedBy:     userID,
+		})
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to save tags"})
+	}
+	return c.JSON(tags)
+}
+
+// ScorecardCriterionIn is one criterion in PUT /jobs/:id/scorecard-criteria
+type ScorecardCriterionIn struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=500"`
+}
+
+// GetScorecardCriteria: GET /jobs/:id/scorecard-criteria
+func (h *ApplicationReviewHandler) GetScorecardCriteria(c *fiber.Ctx) error {
+	var jobID pgtype.UUID
+	if err := jobID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Job ID"})
+	}
+	criteria, err := h.queries.ListScorecardCriteriaByJob(c.Context(), jobID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch scorecard criteria"})
+	}
+	if criteria == nil {
+		return c.JSON([]interface{}{})
+	}
+	return c.JSON(criteria)
+}
+
+// PutScorecardCriteria replaces a job's scorecard criteria with the list in
+// the body. Scorecards already submitted keep their copy of the old names.
+// PUT /jobs/:id/scorecard-criteria?recruiter_id=
+func (h *ApplicationReviewHandler) PutScorecardCriteria(c *fiber.Ctx) error {
+	var jobID, recruiterID pgtype.UUID
+	if err := jobID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Job ID"})
+	}
+	if err := recruiterID.Scan(c.Query("recruiter_id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Recruiter ID"})
+	}
+	var req []ScorecardCriterionIn
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if len(req) > services.MaxScorecardCriteria {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("A job can have at most %d scorecard criteria", services.MaxScorecardCriteria)})
+	}
+	names := map[string]bool{}
+	for i := range req {
+		req[i].Name = strings.TrimSpace(req[i].Name)
+		req[i].Description = strings.TrimSpace(req[i].Description)
+		if err := h.validate.Struct(req[i]); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("criterion %d: %v", i+1, err)})
+		}
+		if names[strings.ToLower(req[i].Name)] {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("criterion %d: %q is listed twice", i+1, req[i].Name)})
+		}
+		names[strings.ToLower(req[i].Name)] = true
+	}
+
+	if _, err := h.queries.GetJobByID(c.Context(), jobID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Job not found"})
+	}
+	if err := checkJobTeam(c.Context(), h.queries, jobID, recruiterID, "Only the job's recruiting team can change its scorecard criteria"); err != nil {
+		return sendError(c, err)
+	}
+
+	criteria := make([]db.JobScorecardCriterion, 0, len(req))
+	err := inTx(c.Context(), h.pool, h.queries, func(q *db.Queries) error {
+		if err := q.DeleteScorecardCriteriaByJob(c.Context(), jobID); err != nil {
+			return err
+		}
+		for i, criterion := range req {
+			row, err := q.CreateScorecardCriterion(c.Context(), db.CreateScorecardCriterionParams{
+				JobID:       jobID,
+				Position:    int32(i),
+				Name:        criterion.Name,
+				Description: pgtype.Text{String: criterion.Description, Valid: criterion.Description != ""},
+			})
+			if err != nil {
+				return err
+			}
+			criteria = append(criteria, row)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("scorecards: failed to save criteria for job %s: %v", jobID.String(), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to save scorecard criteria"})
+	}
+	return c.JSON(criteria)
+}
+
+// SubmitScorecardRequest is a reviewer's scorecard for an application. It
+// must rate every one of the job's criteria from 1 to 5.
+type SubmitScorecardRequest struct {
+	ReviewerID     string            `json:"reviewer_id" validate:"required,uuid"`
+	Recommendation string            `json:"recommendation" validate:"required,oneof=STRONG_NO NO YES STRONG_YES"`
+	Summary        string            `json:"summary" validate:"max=5000"`
+	Ratings        []ScorecardRating `json:"ratings" validate:"dive"`
+}
+
+// ScorecardRating rates one criterion
+type ScorecardRating struct {
+	CriterionID string `json:"criterion_id" validate:"required,uuid"`
+	Criterion   string `json:"criterion,omitempty"`
+	Rating      int32  `json:"rating" validate:"min=1,max=5"`
+}
+
+// Scorecard is a submitted scorecard with its ratings
+type Scorecard struct {
+	db.ListApplicationScorecardsRow
+	Ratings []ScorecardRating `json:"ratings"`
+}
+
+// ScorecardSummary aggregates an application's scorecards
+type ScorecardSummary struct {
+	Count           int                `json:"count"`
+	AverageRating   *float64           `json:"average_rating"`
+	CriteriaAverage map[string]float64 `json:"criteria_average"`
+	Recommendations map[string]int     `json:"recommendations"`
+}
+
+// ListScorecards returns an application's scorecards and their summary:
+// GET /applications/:id/scorecards?user_id=
+func (h *ApplicationReviewHandler) ListScorecards(c *fiber.Ctx) error {
+	var appID pgtype.UUID
+	if err := appID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Application ID"})
+	}
+	var userID pgtype.UUID
+	if err := userID.Scan(c.Query("user_id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+	}
+	if err := checkRecruitingTeam(c.Context(), h.queries, appID, userID, notReviewer); err != nil {
+		return sendError(c, err)
+	}
+	rows, err := h.queries.ListApplicationScorecards(c.Context(), appID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch scorecards"})
+	}
+	ratings, err := h.queries.ListApplicationScorecardRatings(c.Context(), appID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch scorecard ratings"})
+	}
+
+	byScorecard := map[string][]ScorecardRating{}
+	for _, r := range ratings {
+		criterionID := ""
+		if r.CriterionID.Valid {
+			criterionID = r.CriterionID.String()
+		}
+		byScorecard[r.ScorecardID.String()] = append(byScorecard[r.ScorecardID.String()], ScorecardRating{
+			CriterionID: criterionID,
+			Criterion:   r.Criterion,
+			Rating:      r.Rating,
+		})
+	}
+	scorecards := make([]Scorecard, 0, len(rows))
+	for _, row := range rows {
+		rs := byScorecard[row.ID.String()]
+		if rs == nil {
+			rs = []ScorecardRating{}
+		}
+		scorecards = append(scorecards, Scorecard{ListApplicationScorecardsRow: row, Ratings: rs})
+	}
+	return c.JSON(fiber.Map{"scorecards": scorecards, "summary": summarizeScorecards(scorecards)})
+}
+
+// SubmitScorecard saves the reviewer's scorecard for an application,
+// replacing any they submitted before: PUT /applications/:id/scorecards
+func (h *ApplicationReviewHandler) SubmitScorecard(c *fiber.Ctx) error {
+	var appID pgtype.UUID
+	if err := appID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Application ID"})
+	}
+	var req SubmitScorecardRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	req.Recommendation = strings.ToUpper(strings.TrimSpace(req.Recommendation))
+	req.Summary = strings.TrimSpace(req.Summary)
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var reviewerID pgtype.UUID
+	if err := reviewerID.Scan(req.ReviewerID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Reviewer ID"})
+	}
+	target, err := h.queries.GetApplicationReviewTarget(c.Context(), appID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Application not found"})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch application"})
+	}
+	if err := checkRecruitingTeam(c.Context(), h.queries, appID, reviewerID, notReviewer); err != nil {
+		return sendError(c, err)
+	}
+
+	// Every criterion of the job is rated exactly once
+	criteria, err := h.queries.ListScorecardCriteriaByJob(c.Context(), target.JobID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch scorecard criteria"})
+	}
+	given := make(map[string]int32, len(req.Ratings))
+	for _, r := range req.Ratings {
+		if _, dup := given[r.CriterionID]; dup {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "criterion " + r.CriterionID + " is rated twice"})
+		}
+		given[r.CriterionID] = r.Rating
+	}
+	ratings := make([]ScorecardRating, 0, len(criteria))
+	for _, criterion := range criteria {
+		rating, ok := given[criterion.ID.String()]
+		if !ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("%q needs a rating", criterion.Name)})
+		}
+		delete(given, criterion.ID.String())
+		ratings = append(ratings, ScorecardRating{CriterionID: criterion.ID.String(), Criterion: criterion.Name, Rating: rating})
+	}
+	for id := range given {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "criterion " + id + " is not one of this job's scorecard criteria"})
+	}
+
+	var scorecard db.ApplicationScorecard
+	err = inTx(c.Context(), h.pool, h.queries, func(q *db.Queries) error {
+		var err error
+		scorecard, err = q.UpsertApplicationScorecard(c.Context(), db.UpsertApplicationScorecardParams{
+			ApplicationID:  appID,
+			ReviewerID:     reviewerID,
+			Recommendation: req.Recommendation,
+			Summary:        pgtype.Text{String: req.Summary, Valid: req.Summary != ""},
+		})
+		if err != nil {
+			return err
+		}
+		if err := q.DeleteScorecardRatings(c.Context(), scorecard.ID); err != nil {
+			return err
+		}
+		for _, r := range ratings {
+			var criterionID pgtype.UUID
+			if err := criterionID.Scan(r.CriterionID); err != nil {
+				return err
+			}
+			if err := q.CreateScorecardRating(c.Context(), db.CreateScorecardRatingParams{
+				ScorecardID: scorecard.ID,
+				CriterionID: criterionID,
+				Criterion:   r.Criterion,
+				Rating:      r.Rating,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("scorecards: failed to save scorecard for application %s: %v", appID.String(), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to save scorecard"})
+	}
+	return c.JSON(fiber.Map{"scorecard": scorecard, "ratings": ratings})
+}
+
+func summarizeScorecards(scorecards []Scorecard) ScorecardSummary {
+	summary := ScorecardSummary{
+		Count:           len(scorecards),
+		CriteriaAverage: map[string]float64{},
+		Recommendations: map[string]int{
+			services.RecommendationStrongNo:  0,
+			services.RecommendationNo:        0,
+			services.RecommendationYes:       0,
+			services.RecommendationStrongYes: 0,
+		},
+	}
+	total, n := 0, 0
+	criterionTotal, criterionCount := map[string]int{}, map[string]int{}
+	for _, s := range scorecards {
+		summary.Recommendations[s.Recommendation]++
+		for _, r := range s.Ratings {
+			total += int(r.Rating)
+			n++
+			criterionTotal[r.Criterion] += int(r.Rating)
+			criterionCount[r.Criterion]++
+		}
+	}
+	if n > 0 {
+		avg := float64(total) / float64(n)
+		summary.AverageRating = &avg
+	}
+	for criterion, sum := range criterionTotal {
+		summary.CriteriaAverage[criterion] = float64(sum) / float64(criterionCount[criterion])
+	}
+	return summary
+}
+
+// recruitingTeam reports which of ids are on the application's recruiting
+// team: the job's recruiter and recruiters from the same organization
+func recruitingTeam(ctx context.Context, q *db.Queries, appID pgtype.UUID, ids []pgtype.UUID) (map[string]bool, error) {
+	rows, err := q.FilterApplicationReviewers(ctx, db.FilterApplicationReviewersParams{
+		UserIds:       ids,
+		ApplicationID: appID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	allowed := make(map[string]bool, len(rows))
+	for _, id := range rows {
+		allowed[id.String()] = true
+	}
+	return allowed, nil
+}
+
+// checkRecruitingTeam returns an *httpError, with forbidden as the message
+// for outsiders, unless userID is on the application's recruiting team
+func checkRecruitingTeam(ctx context.Context, q *db.Queries, appID, userID pgtype.UUID, forbidden string) error {
+	allowed, err := recruitingTeam(ctx, q, appID, []pgtype.UUID{userID})
+	if err != nil {
+		return &httpError{fiber.StatusInternalServerError, "Failed to check recruiting team"}
+	}
+	if !allowed[userID.String()] {
+		return &httpError{fiber.StatusForbidden, forbidden}
+	}
+	return nil
+}
+
+// onJobTeam reports whether userID is on the job's recruiting team
+func onJobTeam(ctx context.Context, q *db.Queries, jobID, userID pgtype.UUID) (bool, error) {
+	allowed, err := q.FilterJobReviewers(ctx, db.FilterJobReviewersParams{
+		UserIds: []pgtype.UUID{userID},
+		JobID:   jobID,
+	})
+	return len(allowed) > 0, err
+}
+
+// checkJobTeam is checkRecruitingTeam for a job rather than one of its
+// applications
+func checkJobTeam(ctx context.Context, q *db.Queries, jobID, userID pgtype.UUID, forbidden string) error {
+	onTeam, err := onJobTeam(ctx, q, jobID, userID)
+	if err != nil {
+		return &httpError{fiber.StatusInternalServerError, "Failed to check recruiting team"}
+	}
+	if !onTeam {
+		return &httpError{fiber.StatusForbidden, forbidden}
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// httpError is a failure a helper has already turned into the HTTP status
+// and message the handler should answer with
+type httpError struct {
+	status  int
+	message string
+}
+
+func (e *httpError) Error() string { return e.message }
+
+// sendError answers with err's status and message, or with a bare 500
+// when err is not an *httpError
+func sendError(c *fiber.Ctx, err error) error {
+	var hErr *httpError
+	if !errors.As(err, &hErr) {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Internal server error"})
+	}
+	return c.Status(hErr.status).JSON(fiber.Map{"error": hErr.message})
+}
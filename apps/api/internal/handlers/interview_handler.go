@@ -0,0 +1,669 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/aswinbala005/rizeos/api/internal/services"
+	"github.com/aswinbala005/rizeos/api/internal/workers"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// InterviewHandler schedules interviews for applications. The recruiting
+// team proposes times, the candidate picks one, and every change is
+// emailed to the participants with a calendar invite.
+type InterviewHandler struct {
+	queries   *db.Queries
+	pool      *pgxpool.Pool
+	sender    *workers.EmailSender
+	uidDomain string
+	validate  *validator.Validate
+}
+
+// NewInterviewHandler takes the public site URL, whose host names the
+// interviews' calendar UIDs
+func NewInterviewHandler(queries *db.Queries, pool *pgxpool.Pool, sender *workers.EmailSender, publicSiteURL string) *InterviewHandler {
+	domain := "grindlink"
+	if u, err := url.Parse(publicSiteURL); err == nil && u.Hostname() != "" {
+		domain = u.Hostname()
+	}
+	return &InterviewHandler{
+		queries:   queries,
+		pool:      pool,
+		sender:    sender,
+		uidDomain: domain,
+		validate:  validator.New(),
+	}
+}
+
+// notInterviewTeam is the 403 message for anyone outside the recruiting
+// team
+const notInterviewTeam = "Only the job's recruiting team can change this interview"
+
+// CreateInterviewRequest proposes an interview. Slots are the start times
+// the candidate can choose from.
+type CreateInterviewRequest struct {
+	OrganizerID     string      `json:"organizer_id" validate:"required,uuid"`
+	Title           string      `json:"title" validate:"required,max=200"`
+	DurationMinutes int32       `json:"duration_minutes" validate:"required,min=5,max=480"`
+	TimeZone        string      `json:"timezone" validate:"required"`
+	Location        string      `json:"location" validate:"max=500"`
+	VideoURL        string      `json:"video_url" validate:"omitempty,url,max=2000"`
+	InterviewerIDs  []string    `json:"interviewer_ids" validate:"max=10,dive,uuid"`
+	Slots           []time.Time `json:"slots" validate:"required,min=1"`
+}
+
+// ReviseInterviewRequest changes an interview; fields left out stay as
+// they are. StartsAt reschedules it, or schedules it directly.
+type ReviseInterviewRequest struct {
+	UserID          string     `json:"user_id" validate:"required,uuid"`
+	Title           *string    `json:"title" validate:"omitnil,min=1,max=200"`
+	DurationMinutes *int32     `json:"duration_minutes" validate:"omitempty,min=5,max=480"`
+	TimeZone        *string    `json:"timezone" validate:"omitnil,min=1"`
+	Location        *string    `json:"location" validate:"omitempty,max=500"`
+	VideoURL        *string    `json:"video_url" validate:"omitempty,url,max=2000"`
+	StartsAt        *time.Time `json:"starts_at"`
+}
+
+// SelectSlotRequest is the candidate picking a proposed time
+type SelectSlotRequest struct {
+	CandidateID string `json:"candidate_id" validate:"required,uuid"`
+	SlotID      string `json:"slot_id" validate:"required,uuid"`
+}
+
+// CancelInterviewRequest cancels an interview, by the recruiting team or
+// the candidate
+type CancelInterviewRequest struct {
+	UserID string `json:"user_id" validate:"required,uuid"`
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+// InterviewDetail is an interview with its proposed slots and everyone on
+// the invite
+type InterviewDetail struct {
+	db.Interview
+	Slots        []db.InterviewSlot                `json:"slots"`
+	Participants []db.ListInterviewParticipantsRow `json:"participants"`
+}
+
+// ListInterviews: GET /applications/:id/interviews
+func (h *InterviewHandler) ListInterviews(c *fiber.Ctx) error {
+	var appID pgtype.UUID
+	if err := appID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Application ID"})
+	}
+	interviews, err := h.queries.ListInterviewsByApplication(c.Context(), appID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch interviews"})
+	}
+	if interviews == nil {
+		return c.JSON([]interface{}{})
+	}
+	return c.JSON(interviews)
+}
+
+// CreateInterview proposes an interview and asks the candidate to pick a
+// time: POST /applications/:id/interviews
+func (h *InterviewHandler) CreateInterview(c *fiber.Ctx) error {
+	var appID pgtype.UUID
+	if err := appID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Application ID"})
+	}
+	var req CreateInterviewRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	req.Title = strings.TrimSpace(req.Title)
+	req.Location = strings.TrimSpace(req.Location)
+	req.VideoURL = strings.TrimSpace(req.VideoURL)
+	req.TimeZone = strings.TrimSpace(req.TimeZone)
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	if _, err := time.LoadLocation(req.TimeZone); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Unknown time zone %q", req.TimeZone)})
+	}
+	slots, err := proposedSlots(req.Slots)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var organizerID pgtype.UUID
+	if err := organizerID.Scan(req.OrganizerID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Organizer ID"})
+	}
+	interviewers := make([]pgtype.UUID, 0, len(req.InterviewerIDs))
+	seen := map[string]bool{organizerID.String(): true}
+	for _, id := range req.InterviewerIDs {
+		var interviewer pgtype.UUID
+		if err := interviewer.Scan(id); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid interviewer ID"})
+		}
+		if seen[interviewer.String()] {
+			continue
+		}
+		seen[interviewer.String()] = true
+		interviewers = append(interviewers, interviewer)
+	}
+
+	app, err := h.queries.GetApplicationByID(c.Context(), appID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Application not found"})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch application"})
+	}
+	if app.ClosedAt.Valid {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "Application is closed"})
+	}
+
+	allowed, err := recruitingTeam(c.Context(), h.queries, appID, append([]pgtype.UUID{organizerID}, interviewers...))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to check recruiting team"})
+	}
+	if !allowed[organizerID.String()] {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Only the job's recruiting team can schedule interviews"})
+	}
+	for _, interviewer := range interviewers {
+		if !allowed[interviewer.String()] {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Interviewers must be on the job's recruiting team: " + interviewer.String()})
+		}
+	}
+
+	var interview db.Interview
+	err = inTx(c.Context(), h.pool, h.queries, func(q *db.Queries) error {
+		var err error
+		interview, err = q.CreateInterview(c.Context(), db.CreateInterviewParams{
+			ApplicationID:   appID,
+			OrganizerID:     organizerID,
+			Title:           req.Title,
+			DurationMinutes: req.DurationMinutes,
+			Timezone:        req.TimeZone,
+			Location:        pgtype.Text{String: req.Location, Valid: req.Location != ""},
+			VideoUrl:        pgtype.Text{String: req.VideoURL, Valid: req.VideoURL != ""},
+			Column8:         h.uidDomain,
+		})
+		if err != nil {
+			return err
+		}
+		if err := q.AddInterviewSlots(c.Context(), db.AddInterviewSlotsParams{
+			InterviewID: interview.ID,
+			StartsAt:    slots,
+		}); err != nil {
+			return err
+		}
+		if len(interviewers) > 0 {
+			if err := q.AddInterviewers(c.Context(), db.AddInterviewersParams{
+				InterviewID: interview.ID,
+				UserIds:     interviewers,
+			}); err != nil {
+				return err
+			}
+		}
+		return announceInterview(c.Context(), q, interview, services.NotificationInterviewProposed)
+	})
+	if err != nil {
+		log.Printf("interviews: failed to create interview for application %s: %v", appID.String(), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create interview"})
+	}
+	h.sender.Notify()
+	return h.respond(c, fiber.StatusCreated, interview)
+}
+
+// GetInterview: GET /interviews/:id
+func (h *InterviewHandler) GetInterview(c *fiber.Ctx) error {
+	var id pgtype.UUID
+	if err := id.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Interview ID"})
+	}
+	interview, err := h.queries.GetInterview(c.Context(), id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Interview not found"})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch interview"})
+	}
+	return h.respond(c, fiber.StatusOK, interview)
+}
+
+// GetInvite downloads the interview's current calendar invite, or its
+// cancellation: GET /interviews/:id/invite.ics
+func (h *InterviewHandler) GetInvite(c *fiber.Ctx) error {
+	var id pgtype.UUID
+	if err := id.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Interview ID"})
+	}
+	interview, err := h.queries.GetInterview(c.Context(), id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Interview not found"})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch interview"})
+	}
+	if !interview.InvitedAt.Valid {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "The interview has not been scheduled yet"})
+	}
+
+	target, err := h.queries.GetApplicationReviewTarget(c.Context(), interview.ApplicationID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch application"})
+	}
+	participants, err := h.queries.ListInterviewParticipants(c.Context(), interview.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch participants"})
+	}
+	ics, err := interviewEvent(interview, target.JobTitle, participants).ICS()
+	if err != nil {
+		log.Printf("interviews: failed to build invite for %s: %v", id.String(), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to build invite"})
+	}
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="invite.ics"`)
+	return c.SendString(ics)
+}
+
+// SelectSlot schedules the interview at the slot the candidate picked and
+// sends everyone the invite: POST /interviews/:id/select
+func (h *InterviewHandler) SelectSlot(c *fiber.Ctx) error {
+	var id pgtype.UUID
+	if err := id.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Interview ID"})
+	}
+	var req SelectSlotRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	var candidateID, slotID pgtype.UUID
+	if err := candidateID.Scan(req.CandidateID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Candidate ID"})
+	}
+	if err := slotID.Scan(req.SlotID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Slot ID"})
+	}
+
+	interview, app, err := h.load(c.Context(), id)
+	if err != nil {
+		return sendError(c, err)
+	}
+	if app.CandidateID != candidateID {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Only the candidate can pick a time"})
+	}
+	if interview.Status != services.InterviewProposed {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "The interview is not waiting for a time to be picked"})
+	}
+
+	slots, err := h.queries.ListInterviewSlots(c.Context(), interview.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch interview slots"})
+	}
+	var slot *db.InterviewSlot
+	for i := range slots {
+		if slots[i].ID == slotID {
+			slot = &slots[i]
+		}
+	}
+	if slot == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Slot not found"})
+	}
+	if !slot.StartsAt.Time.After(time.Now()) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "That time has already passed"})
+	}
+
+	err = inTx(c.Context(), h.pool, h.queries, func(q *db.Queries) error {
+		var err error
+		interview, err = q.SelectInterviewSlot(c.Context(), db.SelectInterviewSlotParams{
+			ID:     interview.ID,
+			SlotID: slotID,
+		})
+		if err != nil {
+			return err
+		}
+		return announceInterview(c.Context(), q, interview, services.NotificationInterviewScheduled)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "The interview is not waiting for a time to be picked"})
+	}
+	if err != nil {
+		log.Printf("interviews: failed to schedule %s: %v", id.String(), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to schedule interview"})
+	}
+	h.sender.Notify()
+	return h.respond(c, fiber.StatusOK, interview)
+}
+
+// ReviseInterview edits or reschedules an interview. Once the invite has
+// gone out, participants get an update with a higher sequence:
+// PUT /interviews/:id
+func (h *InterviewHandler) ReviseInterview(c *fiber.Ctx) error {
+	var id pgtype.UUID
+	if err := id.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Interview ID"})
+	}
+	var req ReviseInterviewRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	for _, field := range []*string{req.Title, req.TimeZone, req.Location, req.VideoURL} {
+		if field != nil {
+			*field = strings.TrimSpace(*field)
+		}
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	if req.TimeZone != nil {
+		if _, err := time.LoadLocation(*req.TimeZone); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Unknown time zone %q", *req.TimeZone)})
+		}
+	}
+	if req.StartsAt != nil && !req.StartsAt.After(time.Now()) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "starts_at must be in the future"})
+	}
+
+	var userID pgtype.UUID
+	if err := userID.Scan(req.UserID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+	}
+	before, _, err := h.load(c.Context(), id)
+	if err != nil {
+		return sendError(c, err)
+	}
+	if err := checkRecruitingTeam(c.Context(), h.queries, before.ApplicationID, userID, notInterviewTeam); err != nil {
+		return sendError(c, err)
+	}
+
+	params := db.ReviseInterviewParams{ID: before.ID}
+	if req.Title != nil {
+		params.Title = pgtype.Text{String: *req.Title, Valid: true}
+	}
+	if req.DurationMinutes != nil {
+		params.DurationMinutes = pgtype.Int4{Int32: *req.DurationMinutes, Valid: true}
+	}
+	if req.TimeZone != nil {
+		params.Timezone = pgtype.Text{String: *req.TimeZone, Valid: true}
+	}
+	// An empty location or video link clears it
+	if req.Location != nil {
+		params.Location = pgtype.Text{String: *req.Location, Valid: true}
+	}
+	if req.VideoURL != nil {
+		params.VideoUrl = pgtype.Text{String: *req.VideoURL, Valid: true}
+	}
+	if req.StartsAt != nil {
+		params.StartsAt = pgtype.Timestamptz{Time: *req.StartsAt, Valid: true}
+	}
+
+	var interview db.Interview
+	err = inTx(c.Context(), h.pool, h.queries, func(q *db.Queries) error {
+		var err error
+		interview, err = q.ReviseInterview(c.Context(), params)
+		if err != nil {
+			return err
+		}
+		kind := services.NotificationInterviewUpdated
+		switch {
+		case interview.Status == services.InterviewProposed:
+			kind = services.NotificationInterviewProposed
+		case !before.InvitedAt.Valid:
+			kind = services.NotificationInterviewScheduled
+		}
+		return announceInterview(c.Context(), q, interview, kind)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "The interview has been cancelled"})
+	}
+	if err != nil {
+		log.Printf("interviews: failed to revise %s: %v", id.String(), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update interview"})
+	}
+	h.sender.Notify()
+	return h.respond(c, fiber.StatusOK, interview)
+}
+
+// CancelInterview cancels an interview and withdraws the invite from
+// everyone's calendar: POST /interviews/:id/cancel
+func (h *InterviewHandler) CancelInterview(c *fiber.Ctx) error {
+	var id pgtype.UUID
+	if err := id.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Interview ID"})
+	}
+	var req CancelInterviewRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	var userID pgtype.UUID
+	if err := userID.Scan(req.UserID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+	}
+
+	interview, app, err := h.load(c.Context(), id)
+	if err != nil {
+		return sendError(c, err)
+	}
+	if app.CandidateID != userID {
+		if err := checkRecruitingTeam(c.Context(), h.queries, interview.ApplicationID, userID, notInterviewTeam); err != nil {
+			return sendError(c, err)
+		}
+	}
+
+	err = inTx(c.Context(), h.pool, h.queries, func(q *db.Queries) error {
+		var err error
+		interview, err = q.CancelInterview(c.Context(), db.CancelInterviewParams{
+			ID:           interview.ID,
+			CancelReason: pgtype.Text{String: req.Reason, Valid: req.Reason != ""},
+		})
+		if err != nil {
+			return err
+		}
+		return announceInterview(c.Context(), q, interview, services.NotificationInterviewCancelled)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "The interview is already cancelled"})
+	}
+	if err != nil {
+		log.Printf("interviews: failed to cancel %s: %v", id.String(), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to cancel interview"})
+	}
+	h.sender.Notify()
+	return h.respond(c, fiber.StatusOK, interview)
+}
+
+// cancelApplicationInterviews cancels the interviews still to come of an
+// application that is closing, in the caller's transaction, and announces
+// each. It reports whether any emails were queued.
+func cancelApplicationInterviews(ctx context.Context, q *db.Queries, appID pgtype.UUID, reason string) (bool, error) {
+	interviews, err := q.CancelApplicationInterviews(ctx, db.CancelApplicationInterviewsParams{
+		ApplicationID: appID,
+		CancelReason:  pgtype.Text{String: reason, Valid: reason != ""},
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, interview := range interviews {
+		if err := announceInterview(ctx, q, interview, services.NotificationInterviewCancelled); err != nil {
+			return false, err
+		}
+	}
+	return len(interviews) > 0, nil
+}
+
+// announceInterview queues the emails and the candidate notification for a
+// change to interview, in the caller's transaction. Once an invite has gone
+// out, every participant's email carries the iCalendar REQUEST or CANCEL at
+// the interview's current sequence; before that only the candidate hears.
+func announceInterview(ctx context.Context, q *db.Queries, interview db.Interview, kind string) error {
+	target, err := q.GetApplicationReviewTarget(ctx, interview.ApplicationID)
+	if err != nil {
+		return err
+	}
+	participants, err := q.ListInterviewParticipants(ctx, interview.ID)
+	if err != nil {
+		return err
+	}
+	event := interviewEvent(interview, target.JobTitle, participants)
+	update := services.InterviewUpdate{
+		Kind:          kind,
+		InterviewID:   interview.ID.String(),
+		ApplicationID: interview.ApplicationID.String(),
+		JobTitle:      target.JobTitle,
+		Event:         event,
+		Reason:        interview.CancelReason.String,
+	}
+	if kind == services.NotificationInterviewProposed {
+		slots, err := q.ListInterviewSlots(ctx, interview.ID)
+		if err != nil {
+			return err
+		}
+		for _, slot := range slots {
+			update.Slots = append(update.Slots, slot.StartsAt.Time)
+		}
+	}
+
+	var calendar, method pgtype.Text
+	if interview.InvitedAt.Valid {
+		ics, err := event.ICS()
+		if err != nil {
+			return err
+		}
+		calendar = pgtype.Text{String: ics, Valid: true}
+		method = pgtype.Text{String: event.Method, Valid: true}
+	}
+
+	subject, body := update.Subject(), update.Body()
+	for _, p := range participants {
+		if !p.Email.Valid || p.Email.String == "" || (!calendar.Valid && p.Part != "CANDIDATE") {
+			continue
+		}
+		if err := q.EnqueueEmail(ctx, db.EnqueueEmailParams{
+			ToAddress:      p.Email.String,
+			Subject:        subject,
+			Body:           body,
+			Calendar:       calendar,
+			CalendarMethod: method,
+		}); err != nil {
+			return err
+		}
+		if p.Part == "CANDIDATE" {
+			n := update.Notice()
+			if _, err := q.CreateNotification(ctx, db.CreateNotificationParams{
+				UserID: p.ID,
+				Kind:   n.Kind,
+				Title:  n.Title,
+				Body:   n.Body,
+				Data:   n.Data,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// interviewEvent is the calendar event for interview. The organizer is the
+// ORGANIZER and the candidate and interviewers are attendees.
+func interviewEvent(interview db.Interview, jobTitle string, participants []db.ListInterviewParticipantsRow) services.CalendarEvent {
+	event := services.CalendarEvent{
+		UID:         interview.Uid,
+		Sequence:    int(interview.Sequence),
+		Method:      services.CalendarRequest,
+		Start:       interview.StartsAt.Time,
+		Duration:    time.Duration(interview.DurationMinutes) * time.Minute,
+		TimeZone:    interview.Timezone,
+		Summary:     interview.Title,
+		Description: "Interview for " + jobTitle,
+		Location:    interview.Location.String,
+		URL:         interview.VideoUrl.String,
+		Stamp:       time.Now(),
+	}
+	if interview.Status == services.InterviewCancelled {
+		event.Method = services.CalendarCancel
+	}
+	if event.Location == "" {
+		event.Location = event.URL
+	}
+	for _, p := range participants {
+		person := services.CalendarPerson{Name: p.FullName.String, Email: p.Email.String}
+		if p.Part == "ORGANIZER" {
+			event.Organizer = person
+		} else if person.Email != "" {
+			event.Attendees = append(event.Attendees, person)
+		}
+	}
+	return event
+}
+
+// proposedSlots checks the offered start times and returns them sorted,
+// without repeats
+func proposedSlots(times []time.Time) ([]pgtype.Timestamptz, error) {
+	if len(times) > services.MaxInterviewSlots {
+		return nil, fmt.Errorf("an interview can offer at most %d slots", services.MaxInterviewSlots)
+	}
+	sorted := append([]time.Time(nil), times...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+	now := time.Now()
+	slots := make([]pgtype.Timestamptz, 0, len(sorted))
+	for i, t := range sorted {
+		if !t.After(now) {
+			return nil, fmt.Errorf("slot %s is in the past", t.Format(time.RFC3339))
+		}
+		if i > 0 && t.Equal(sorted[i-1]) {
+			continue
+		}
+		slots = append(slots, pgtype.Timestamptz{Time: t, Valid: true})
+	}
+	return slots, nil
+}
+
+// respond writes interview with its slots and participants
+func (h *InterviewHandler) respond(c *fiber.Ctx, status int, interview db.Interview) error {
+	slots, err := h.queries.ListInterviewSlots(c.Context(), interview.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch interview slots"})
+	}
+	participants, err := h.queries.ListInterviewParticipants(c.Context(), interview.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch participants"})
+	}
+	if slots == nil {
+		slots = []db.InterviewSlot{}
+	}
+	if participants == nil {
+		participants = []db.ListInterviewParticipantsRow{}
+	}
+	return c.Status(status).JSON(InterviewDetail{Interview: interview, Slots: slots, Participants: participants})
+}
+
+// load fetches an interview and its application, returning an
+// *httpError when either cannot be had
+func (h *InterviewHandler) load(ctx context.Context, id pgtype.UUID) (db.Interview, db.Application, error) {
+	interview, err := h.queries.GetInterview(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return interview, db.Application{}, &httpError{fiber.StatusNotFound, "Interview not found"}
+	}
+	if err != nil {
+		return interview, db.Application{}, &httpError{fiber.StatusInternalServerError, "Failed to fetch interview"}
+	}
+	app, err := h.queries.GetApplicationByID(ctx, interview.ApplicationID)
+	if err != nil {
+		return interview, app, &httpError{fiber.StatusInternalServerError, "Failed to fetch application"}
+	}
+	return interview, app, nil
+}
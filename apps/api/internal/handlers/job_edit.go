@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Job statuses, as stored in jobs.status
+const (
+	JobStatusDraft  = "DRAFT"
+	JobStatusOpen   = "OPEN"
+	JobStatusClosed = "CLOSED"
+)
+
+// UpdateJobRequest is a partial update: omitted fields are left alone.
+// publish_at and expires_at take an RFC 3339 time, or null to clear them.
+// updated_at, or an If-Match header with the job's ETag, makes the update
+// fail with a conflict if someone else saved the job in the meantime.
+// updated_by is the user making the change, recorded in the job's revision
+// history.
+type UpdateJobRequest struct {
+	Title          *string         `json:"title" validate:"omitempty,min=1"`
+	JobSummary     *string         `json:"job_summary"`
+	Description    *string         `json:"description" validate:"omitempty,min=1"`
+	Education      *string         `json:"education_requirements"`
+	Skills         *string         `json:"skills_requirements"`
+	ExperienceMin  *int32          `json:"experience_min" validate:"omitempty,min=0"`
+	ExperienceMax  *int32          `json:"experience_max" validate:"omitempty,min=0"`
+	IsUnpaid       *bool           `json:"is_unpaid"`
+	SalaryMin      *int32          `json:"salary_min" validate:"omitempty,min=0"`
+	SalaryMax      *int32          `json:"salary_max" validate:"omitempty,min=0"`
+	Currency       *string         `json:"currency"`
+	PayPeriod      *string         `json:"pay_period" validate:"omitempty,oneof=HOUR DAY WEEK MONTH YEAR"`
+	JobType        *string         `json:"job_type"`
+	LocationType   *string         `json:"location_type" validate:"omitempty,oneof=Remote Hybrid In-Office"`
+	LocationCity   *string         `json:"location_city"`
+	RecruiterEmail *string         `json:"recruiter_email" validate:"omitempty,email"`
+	Status         *string         `json:"status" validate:"omitempty,oneof=DRAFT OPEN CLOSED"`
+	PublishAt      json.RawMessage `json:"publish_at"`
+	ExpiresAt      json.RawMessage `json:"expires_at"`
+	UpdatedAt      *time.Time      `json:"updated_at"`
+	UpdatedBy      string          `json:"updated_by" validate:"omitempty,uuid"`
+}
+
+// GetJob returns one job in any status, with its ETag
+func (h *JobHandler) GetJob(c *fiber.Ctx) error {
+	var jobID pgtype.UUID
+	if err := jobID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Job ID"})
+	}
+
+	job, err := h.queries.GetJobByID(c.Context(), jobID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Job not found"})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch job"})
+	}
+
+	etag := jobETag(job.UpdatedAt)
+	c.Set(fiber.HeaderETag, etag)
+	if c.Get(fiber.HeaderIfNoneMatch) == etag {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	return c.JSON(job)
+}
+
+// UpdateJob applies a partial update to a job: PUT /jobs/:id
+func (h *JobHandler) UpdateJob(c *fiber.Ctx) error {
+	var jobID pgtype.UUID
+	if err := jobID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Job ID"})
+	}
+
+	var req UpdateJobRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	setPublishAt, publishAt, err := optionalTime(req.PublishAt, "publish_at")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	setExpiresAt, expiresAt, err := optionalTime(req.ExpiresAt, "expires_at")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var updatedBy pgtype.UUID
+	if req.UpdatedBy != "" {
+		if err := updatedBy.Scan(req.UpdatedBy); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid updated_by"})
+		}
+	}
+
+	current, err := h.queries.GetJobByID(c.Context(), jobID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Job not found"})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch job"})
+	}
+
+	// The version the client edited. Without one, the version just read
+	// still guards against a write landing between the read and the update.
+	expected := current.UpdatedAt
+	conflictStatus := fiber.StatusConflict
+	if ifMatch := c.Get(fiber.HeaderIfMatch); ifMatch != "" && ifMatch != "*" {
+		if ifMatch != jobETag(current.UpdatedAt) {
+			return jobConflict(c, fiber.StatusPreconditionFailed, current)
+		}
+		conflictStatus = fiber.StatusPreconditionFailed
+	} else if req.UpdatedAt != nil {
+		if !req.UpdatedAt.Equal(current.UpdatedAt.Time) {
+			return jobConflict(c, fiber.StatusConflict, current)
+		}
+	}
+
+	// Check the job as it will be after the update
+	if !setPublishAt {
+		publishAt = current.PublishAt
+	}
+	if !setExpiresAt {
+		expiresAt = current.ExpiresAt
+	}
+	status := current.Status.String
+	if req.Status != nil {
+		status = *req.Status
+	}
+	if err := checkJobSchedule(status, publishAt, expiresAt, setPublishAt || req.Status != nil, setExpiresAt || req.Status != nil); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err := checkJobRanges(pickInt4(req.SalaryMin, current.SalaryMin), pickInt4(req.SalaryMax, current.SalaryMax), "salary"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err := checkJobRanges(pickInt4(req.ExperienceMin, current.ExperienceMin), pickInt4(req.ExperienceMax, current.ExperienceMax), "experience"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	job, err := h.queries.UpdateJob(c.Context(), db.UpdateJobParams{
+		Title:                 optionalText(req.Title),
+		Description:           optionalText(req.Description),
+		JobSummary:            optionalText(req.JobSummary),
+		EducationRequirements: optionalText(req.Education),
+		SkillsRequirements:    optionalText(req.Skills),
+		ExperienceMin:         optionalInt4(req.ExperienceMin),
+		ExperienceMax:         optionalInt4(req.ExperienceMax),
+		IsUnpaid:              optionalBool(req.IsUnpaid),
+		SalaryMin:             optionalInt4(req.SalaryMin),
+		SalaryMax:             optionalInt4(req.SalaryMax),
+		Currency:              optionalText(req.Currency),
+		PayPeriod:             optionalText(req.PayPeriod),
+		JobType:               optionalText(req.JobType),
+		LocationType:          optionalText(req.LocationType),
+		LocationCity:          optionalText(req.LocationCity),
+		RecruiterEmail:        optionalText(req.RecruiterEmail),
+		Status:                optionalText(req.Status),
+		SetPublishAt:          setPublishAt,
+		PublishAt:             publishAt,
+		SetExpiresAt:          setExpiresAt,
+		ExpiresAt:             expiresAt,
+		UpdatedBy:             updatedBy,
+		ID:                    jobID,
+		ExpectedUpdatedAt:     expected,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Saved by someone else since we read it
+		latest, err := h.queries.GetJobByID(c.Context(), jobID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch job"})
+		}
+		return jobConflict(c, conflictStatus, latest)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update job: " + err.Error()})
+	}
+
+	if kind, reason, ok := statusChangeEvent(current.Status.String, job.Status.String); ok {
+		if err := h.queries.CreateJobEvent(c.Context(), db.CreateJobEventParams{
+			JobID:   jobID,
+			Kind:    kind,
+			Reason:  reason,
+			ActorID: updatedBy,
+		}); err != nil {
+			fmt.Printf("Warning: could not record %s event for job %s: %v\n", kind, jobID.String(), err)
+		}
+	}
+	if h.scheduler != nil && (job.PublishAt.Valid || job.ExpiresAt.Valid) {
+		h.scheduler.Notify()
+	}
+	c.Set(fiber.HeaderETag, jobETag(job.UpdatedAt))
+	return c.JSON(job)
+}
+
+// jobConflict reports a lost update along with the job as it is now, so
+// the client can merge and retry with the new ETag
+func jobConflict(c *fiber.Ctx, status int, current db.GetJobByIDRow) error {
+	c.Set(fiber.HeaderETag, jobETag(current.UpdatedAt))
+	return c.Status(status).JSON(fiber.Map{
+		"error": "Job was modified by someone else; reload it and try again",
+		"job":   current,
+	})
+}
+
+// jobETag is a job's version: its updated_at to the microsecond, which is
+// the precision Postgres stores
+func jobETag(updatedAt pgtype.Timestamptz) string {
+	return `"` + strconv.FormatInt(updatedAt.Time.UnixMicro(), 36) + `"`
+}
+
+// resolveJobStatus picks the status for a new job. A job published in the
+// future starts as a DRAFT that the scheduler opens.
+func resolveJobStatus(status string, publishAt pgtype.Timestamptz) (string, error) {
+	scheduled := publishAt.Valid && publishAt.Time.After(time.Now())
+	switch {
+	case status == "" && scheduled:
+		return JobStatusDraft, nil
+	case status == "":
+		return JobStatusOpen, nil
+	case status == JobStatusOpen && scheduled:
+		return "", fmt.Errorf("a job with a future publish_at must start as a DRAFT")
+	}
+	return status, nil
+}
+
+// checkJobSchedule validates publish_at and expires_at for a job in status.
+// A time that was not just set is only checked against the other one, so an
+// old publish_at does not block unrelated edits.
+func checkJobSchedule(status string, publishAt, expiresAt pgtype.Timestamptz, publishChanged, expiryChanged bool) error {
+	now := time.Now()
+	if publishAt.Valid && expiresAt.Valid && !expiresAt.Time.After(publishAt.Time) {
+		return fmt.Errorf("expires_at must be after publish_at")
+	}
+	if expiryChanged && expiresAt.Valid && status != JobStatusClosed && !expiresAt.Time.After(now) {
+		return fmt.Errorf("expires_at must be in the future")
+	}
+	if publishChanged && status == JobStatusOpen && publishAt.Valid && publishAt.Time.After(now) {
+		return fmt.Errorf("a job with a future publish_at must be a DRAFT")
+	}
+	return nil
+}
+
+func checkJobRanges(min, max pgtype.Int4, name string) error {
+	if min.Valid && max.Valid && max.Int32 > 0 && min.Int32 > max.Int32 {
+		return fmt.Errorf("%s_min cannot be greater than %s_max", name, name)
+	}
+	return nil
+}
+
+// optionalTime reads a field that may be absent (leave alone), null (clear)
+// or an RFC 3339 time
+func optionalTime(raw json.RawMessage, name string) (bool, pgtype.Timestamptz, error) {
+	if len(raw) == 0 {
+		return false, pgtype.Timestamptz{}, nil
+	}
+	if strings.TrimSpace(string(raw)) == "null" {
+		return true, pgtype.Timestamptz{}, nil
+	}
+	var t time.Time
+	if err := json.Unmarshal(raw, &t); err != nil {
+		return false, pgtype.Timestamptz{}, fmt.Errorf("%s must be an RFC 3339 time or null", name)
+	}
+	return true, pgtype.Timestamptz{Time: t, Valid: true}, nil
+}
+
+func optionalText(s *string) pgtype.Text {
+	if s == nil {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: strings.TrimSpace(*s), Valid: true}
+}
+
+func optionalInt4(n *int32) pgtype.Int4 {
+	if n == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: *n, Valid: true}
+}
+
+func optionalBool(b *bool) pgtype.Bool {
+	if b == nil {
+		return pgtype.Bool{}
+	}
+	return pgtype.Bool{Bool: *b, Valid: true}
+}
+
+func pickInt4(n *int32, current pgtype.Int4) pgtype.Int4 {
+	if n != nil {
+		return pgtype.Int4{Int32: *n, Valid: true}
+	}
+	return current
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/aswinbala005/rizeos/api/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	feedMaxItems       = 200   // RSS and Atom readers only want recent jobs
+	indeedFeedMaxItems = 10000 // aggregators want every open job
+	// Cached feeds are rebuilt when a job in them changes, and at least this
+	// often so organization renames show up
+	feedCacheTTL        = 15 * time.Minute
+	maxFeedCacheEntries = 256
+)
+
+type feedFormat struct {
+	contentType string
+	maxItems    int32
+	render      func(services.FeedInfo, []db.ListFeedJobsRow, services.JobURL) ([]byte, error)
+}
+
+var (
+	rssFormat    = feedFormat{"application/rss+xml; charset=utf-8", feedMaxItems, services.RSSFeed}
+	atomFormat   = feedFormat{"application/atom+xml; charset=utf-8", feedMaxItems, services.AtomFeed}
+	indeedFormat = feedFormat{"application/xml; charset=utf-8", indeedFeedMaxItems, services.IndeedFeed}
+)
+
+// feedCache keeps each generated feed until a job in it changes. Keys
+// include the filters, so the number of entries is capped.
+type feedCache struct {
+	mu      sync.Mutex
+	entries map[string]feedEntry
+}
+
+type feedEntry struct {
+	version      string
+	builtAt      time.Time
+	body         []byte
+	etag         string
+	lastModified time.Time
+}
+
+func newFeedCache() *feedCache {
+	return &feedCache{entries: map[string]feedEntry{}}
+}
+
+func (fc *feedCache) get(key, version string) (feedEntry, bool) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	e, ok := fc.entries[key]
+	if !ok || e.version != version || time.Since(e.builtAt) > feedCacheTTL {
+		return feedEntry{}, false
+	}
+	return e, true
+}
+
+func (fc *feedCache) put(key string, e feedEntry) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	if len(fc.entries) >= maxFeedCacheEntries {
+		fc.entries = map[string]feedEntry{}
+	}
+	fc.entries[key] = e
+}
+
+// RSSFeed: GET /public/feeds/jobs.rss?recruiter_id=&organization=
+func (h *PublicJobHandler) RSSFeed(c *fiber.Ctx) error {
+	return h.serveFeed(c, rssFormat)
+}
+
+// AtomFeed: GET /public/feeds/jobs.atom?recruiter_id=&organization=
+func (h *PublicJobHandler) AtomFeed(c *fiber.Ctx) error {
+	return h.serveFeed(c, atomFormat)
+}
+
+// IndeedFeed is every open job in the XML format job aggregators crawl:
+// GET /public/feeds/indeed.xml?recruiter_id=&organization=
+func (h *PublicJobHandler) IndeedFeed(c *fiber.Ctx) error {
+	return h.serveFeed(c, indeedFormat)
+}
+
+func (h *PublicJobHandler) serveFeed(c *fiber.Ctx, format feedFormat) error {
+	var recruiterID pgtype.UUID
+	if v := c.Query("recruiter_id"); v != "" {
+		if err := recruiterID.Scan(v); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Recruiter ID"})
+		}
+	}
+	organization := strings.TrimSpace(c.Query("organization"))
+	orgFilter := pgtype.Text{String: organization, Valid: organization != ""}
+
+	// The canonical URL doubles as the cache key
+	query := url.Values{}
+	if recruiterID.Valid {
+		query.Set("recruiter_id", recruiterID.String())
+	}
+	if organization != "" {
+		query.Set("organization", strings.ToLower(organization))
+	}
+	selfURL := c.BaseURL() + c.Path()
+	if len(query) > 0 {
+		selfURL += "?" + query.Encode()
+	}
+
+	v, err := h.queries.GetFeedVersion(c.Context(), db.GetFeedVersionParams{RecruiterID: recruiterID, Organization: orgFilter})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch jobs"})
+	}
+	version := strconv.FormatInt(v.LastModified.Time.UnixMicro(), 36) + "/" + strconv.FormatInt(v.OpenJobs, 10)
+
+	entry, ok := h.feeds.get(selfURL, version)
+	if !ok {
+		jobs, err := h.queries.ListFeedJobs(c.Context(), db.ListFeedJobsParams{
+			RecruiterID:  recruiterID,
+			Organization: orgFilter,
+			MaxItems:     format.maxItems,
+		})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch jobs"})
+		}
+		info := services.FeedInfo{
+			Title:   services.FeedPublisher + " jobs",
+			SiteURL: h.siteURL,
+			SelfURL: selfURL,
+			Updated: v.LastModified.Time,
+		}
+		if organization != "" && len(jobs) > 0 && jobs[0].OrganizationName.Valid {
+			info.Title += " at " + jobs[0].OrganizationName.String
+		}
+		if info.SiteURL == "" {
+			info.SiteURL = c.BaseURL()
+		}
+		if !v.LastModified.Valid {
+			info.Updated = time.Now()
+		}
+		body, err := format.render(info, jobs, func(id string) string { return h.jobURL(c, id) })
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to encode feed"})
+		}
+		sum := sha256.Sum256(body)
+		entry = feedEntry{
+			version:      version,
+			builtAt:      time.Now(),
+			body:         body,
+			etag:         `"` + hex.EncodeToString(sum[:12]) + `"`,
+			lastModified: info.Updated.UTC().Truncate(time.Second),
+		}
+		h.feeds.put(selfURL, entry)
+	}
+
+	c.Set(fiber.HeaderETag, entry.etag)
+	c.Set(fiber.HeaderLastModified, entry.lastModified.Format(http.TimeFormat))
+	c.Set(fiber.HeaderCacheControl, publicCacheControl)
+	if notModified(c, entry.etag, entry.lastModified) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	c.Set(fiber.HeaderContentType, format.contentType)
+	return c.Send(entry.body)
+}
+
+// notModified evaluates a conditional GET. If-None-Match wins over
+// If-Modified-Since when both are sent.
+func notModified(c *fiber.Ctx, etag string, lastModified time.Time) bool {
+	if inm := c.Get(fiber.HeaderIfNoneMatch); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == etag {
+				return true
+			}
+		}
+		return false
+	}
+	if ims := c.Get(fiber.HeaderIfModifiedSince); ims != "" {
+		since, err := http.ParseTime(ims)
+		return err == nil && !lastModified.After(since)
+	}
+	return false
+}
@@ -7,22 +7,31 @@ import (
 	"math"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/aswinbala005/rizeos/api/internal/services"
+	"github.com/aswinbala005/rizeos/api/internal/workers"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type JobHandler struct {
-	queries  *db.Queries
-	validate *validator.Validate
+	queries   *db.Queries
+	pool      *pgxpool.Pool
+	validate  *validator.Validate
+	scheduler *workers.JobScheduler
 }
 
-func NewJobHandler(queries *db.Queries) *JobHandler {
+func NewJobHandler(queries *db.Queries, pool *pgxpool.Pool, scheduler *workers.JobScheduler) *JobHandler {
 	return &JobHandler{
-		queries:  queries,
-		validate: validator.New(),
+		queries:   queries,
+		pool:      pool,
+		validate:  validator.New(),
+		scheduler: scheduler,
 	}
 }
 
@@ -41,9 +50,15 @@ type CreateJobRequest struct {
 	SalaryMin       int32  `json:"salary_min"`
 	SalaryMax       int32  `json:"salary_max"`
 	Currency        string `json:"currency"`
+	// What salary_min and salary_max cover; defaults to YEAR
+	PayPeriod       string `json:"pay_period" validate:"omitempty,oneof=HOUR DAY WEEK MONTH YEAR"`
 	JobType         string `json:"job_type"`
-	LocationType    string `json:"location_type"`
+	LocationType    string `json:"location_type" validate:"omitempty,oneof=Remote Hybrid In-Office"`
 	LocationCity    string `json:"location_city"`
+	// DRAFT or OPEN; defaults to DRAFT when publish_at is in the future
+	Status          string     `json:"status" validate:"omitempty,oneof=DRAFT OPEN"`
+	PublishAt       *time.Time `json:"publish_at"`
+	ExpiresAt       *time.Time `json:"expires_at"`
 }
 
 func (h *JobHandler) CreateJob(c *fiber.Ctx) error {
@@ -54,11 +69,53 @@ func (h *JobHandler) CreateJob(c *fiber.Ctx) error {
 	if err := h.validate.Struct(req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
+	arg, err := req.params()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	job, err := h.queries.CreateJob(c.Context(), arg)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create job: " + err.Error()})
+	}
+	if h.scheduler != nil && (arg.PublishAt.Valid || arg.ExpiresAt.Valid) {
+		h.scheduler.Notify()
+	}
+	return c.Status(fiber.StatusCreated).JSON(job)
+}
+
+// params checks what the validator tags cannot and builds the insert
+func (req CreateJobRequest) params() (db.CreateJobParams, error) {
 	var recruiterUUID pgtype.UUID
 	if err := recruiterUUID.Scan(req.RecruiterID); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Recruiter ID"})
+		return db.CreateJobParams{}, errors.New("Invalid Recruiter ID")
+	}
+	publishAt, expiresAt := pgtype.Timestamptz{}, pgtype.Timestamptz{}
+	if req.PublishAt != nil {
+		publishAt = pgtype.Timestamptz{Time: *req.PublishAt, Valid: true}
+	}
+	if req.ExpiresAt != nil {
+		expiresAt = pgtype.Timestamptz{Time: *req.ExpiresAt, Valid: true}
+	}
+	status, err := resolveJobStatus(req.Status, publishAt)
+	if err != nil {
+		return db.CreateJobParams{}, err
+	}
+	if err := checkJobSchedule(status, publishAt, expiresAt, true, true); err != nil {
+		return db.CreateJobParams{}, err
 	}
-	arg := db.CreateJobParams{
+	salaryMin, salaryMax := pgtype.Int4{Int32: req.SalaryMin, Valid: true}, pgtype.Int4{Int32: req.SalaryMax, Valid: true}
+	if err := checkJobRanges(salaryMin, salaryMax, "salary"); err != nil {
+		return db.CreateJobParams{}, err
+	}
+	experienceMin, experienceMax := pgtype.Int4{Int32: req.ExperienceMin, Valid: true}, pgtype.Int4{Int32: req.ExperienceMax, Valid: true}
+	if err := checkJobRanges(experienceMin, experienceMax, "experience"); err != nil {
+		return db.CreateJobParams{}, err
+	}
+	payPeriod := req.PayPeriod
+	if payPeriod == "" {
+		payPeriod = services.PayPeriodYear
+	}
+	return db.CreateJobParams{
 		RecruiterID:           recruiterUUID,
 		RecruiterEmail:        pgtype.Text{String: req.RecruiterEmail, Valid: true},
 		Title:                 req.Title,
@@ -69,19 +126,18 @@ func (h *JobHandler) CreateJob(c *fiber.Ctx) error {
 		SkillsRequirements:    pgtype.Text{String: req.Skills, Valid: true},
 		IsUnpaid:              pgtype.Bool{Bool: req.IsUnpaid, Valid: true},
 		JobType:               pgtype.Text{String: req.JobType, Valid: true},
-		LocationType:          pgtype.Text{String: req.LocationType, Valid: true},
+		LocationType:          pgtype.Text{String: req.LocationType, Valid: req.LocationType != ""},
 		LocationCity:          pgtype.Text{String: req.LocationCity, Valid: true},
-		SalaryMin:             pgtype.Int4{Int32: req.SalaryMin, Valid: true},
-		SalaryMax:             pgtype.Int4{Int32: req.SalaryMax, Valid: true},
+		SalaryMin:             salaryMin,
+		SalaryMax:             salaryMax,
 		Currency:              pgtype.Text{String: req.Currency, Valid: true},
-		ExperienceMin:         pgtype.Int4{Int32: req.ExperienceMin, Valid: true},
-		ExperienceMax:         pgtype.Int4{Int32: req.ExperienceMax, Valid: true},
-	}
-	job, err := h.queries.CreateJob(c.Context(), arg)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create job: " + err.Error()})
-	}
-	return c.Status(fiber.StatusCreated).JSON(job)
+		PayPeriod:             payPeriod,
+		ExperienceMin:         experienceMin,
+		ExperienceMax:         experienceMax,
+		Status:                pgtype.Text{String: status, Valid: true},
+		PublishAt:             publishAt,
+		ExpiresAt:             expiresAt,
+	}, nil
 }
 
 // --- SMART MATCHING ALGORITHM ---
@@ -211,16 +267,46 @@ func (h *JobHandler) ListJobs(c *fiber.Ctx) error {
 
 	type JobWithMatch struct {
 		db.ListJobsRow
-		MatchScore int `json:"match_score"`
+		MatchScore      int `json:"match_score"`
+		PreferenceBoost int `json:"preference_boost"`
 	}
 
-	var response []JobWithMatch
+	// With job preferences, jobs failing the hard ones are left out and
+	// jobs meeting the soft ones are ranked higher
+	var preferred map[string]db.ListPreferredJobsRow
+	if _, err := h.queries.GetJobPreferences(c.Context(), uuid); err == nil {
+		rows, err := h.queries.ListPreferredJobs(c.Context(), db.ListPreferredJobsParams{CandidateID: uuid})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to apply job preferences"})
+		}
+		preferred = make(map[string]db.ListPreferredJobsRow, len(rows))
+		for _, row := range rows {
+			preferred[row.ID.String()] = row
+		}
+	}
+
+	response := []JobWithMatch{}
 
 	for _, job := range jobs {
 		score := calculateSmartScore(user, job)
+		boost := 0
+		if preferred != nil {
+			pref, ok := preferred[job.ID.String()]
+			if !ok {
+				continue
+			}
+			if pref.RoleMatch {
+				boost += preferenceRoleBoost
+			}
+			if pref.LocationMatch {
+				boost += preferenceLocationBoost
+			}
+			score = min(score+boost, 99)
+		}
 		response = append(response, JobWithMatch{
-			ListJobsRow: job,
-			MatchScore:  score,
+			ListJobsRow:     job,
+			MatchScore:      score,
+			PreferenceBoost: boost,
 		})
 	}
 
@@ -231,6 +317,12 @@ func (h *JobHandler) ListJobs(c *fiber.Ctx) error {
 	return c.JSON(response)
 }
 
+// Match score added for jobs meeting a candidate's soft preferences
+const (
+	preferenceRoleBoost     = 15
+	preferenceLocationBoost = 10
+)
+
 // --- LIST JOBS BY RECRUITER ---
 func (h *JobHandler) ListJobsByRecruiter(c *fiber.Ctx) error {
 	recruiterID := c.Params("id")
@@ -263,7 +355,15 @@ func (h *JobHandler) CloseJob(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Job ID"})
 	}
 
-	err := h.queries.CloseJob(c.Context(), uuid)
+	actorID, err := h.jobActor(c)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	_, err = h.queries.CloseJob(c.Context(), db.CloseJobParams{ActorID: actorID, ID: uuid})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return h.jobActionConflict(c, uuid, "Only open jobs can be closed (this job is %s)")
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to close job"})
 	}
@@ -281,7 +381,15 @@ func (h *JobHandler) ReopenJob(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Job ID"})
 	}
 
-	err := h.queries.ReopenJob(c.Context(), uuid)
+	actorID, err := h.jobActor(c)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	_, err = h.queries.ReopenJob(c.Context(), db.ReopenJobParams{ActorID: actorID, ID: uuid})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return h.jobActionConflict(c, uuid, "Only closed jobs can be reopened (this job is %s)")
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to reopen job"})
 	}
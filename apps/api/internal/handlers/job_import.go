@@ -0,0 +1,402 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const maxImportRows = 500
+
+// ImportRowError lists what is wrong with one imported job. Row 1 is the
+// first job: the first element of a JSON array, or the line after a CSV
+// header.
+type ImportRowError struct {
+	Row    int      `json:"row"`
+	Errors []string `json:"errors"`
+}
+
+// jobColumns are the CSV columns for import and export: CreateJobRequest's
+// JSON names, in field order, so an export can be edited and imported again
+var jobColumns = func() []string {
+	t := reflect.TypeOf(CreateJobRequest{})
+	columns := make([]string, t.NumField())
+	for i := range columns {
+		columns[i] = jsonName(t.Field(i))
+	}
+	return columns
+}()
+
+// ImportJobs creates many jobs at once: POST /jobs/import?dry_run=true
+// The body is a JSON array of CreateJobRequest objects, or CSV with a
+// header row of the same field names (Content-Type text/csv or ?format=csv).
+// recruiter_id and recruiter_email may be given as query parameters to
+// fill rows that leave them blank. Every row is validated; if any fails,
+// nothing is inserted and each bad row is reported. Otherwise all rows are
+// inserted in one transaction, unless dry_run is set.
+func (h *JobHandler) ImportJobs(c *fiber.Ctx) error {
+	format, err := jobsFormat(c, string(c.Request().Header.ContentType()), "json")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	var rows []CreateJobRequest
+	var rowErrors map[int][]string
+	if format == "csv" {
+		rows, rowErrors, err = decodeJobsCSV(c.Body())
+	} else {
+		rows, rowErrors, err = decodeJobsJSON(c.Body())
+	}
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	if len(rows) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "No jobs to import"})
+	}
+	if len(rows) > maxImportRows {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("At most %d jobs can be imported at once", maxImportRows)})
+	}
+
+	params := make([]db.CreateJobParams, len(rows))
+	for i, req := range rows {
+		row := i + 1
+		if _, bad := rowErrors[row]; bad {
+			continue // could not be decoded
+		}
+		if req.RecruiterID == "" {
+			req.RecruiterID = c.Query("recruiter_id")
+		}
+		if req.RecruiterEmail == "" {
+			req.RecruiterEmail = c.Query("recruiter_email")
+		}
+		if err := h.validate.Struct(req); err != nil {
+			rowErrors[row] = validationMessages(err)
+			continue
+		}
+		if params[i], err = req.params(); err != nil {
+			rowErrors[row] = []string{err.Error()}
+		}
+	}
+
+	dryRun := c.QueryBool("dry_run")
+	if len(rowErrors) > 0 {
+		errs := make([]ImportRowError, 0, len(rowErrors))
+		for row := 1; row <= len(rows); row++ {
+			if messages, ok := rowErrors[row]; ok {
+				errs = append(errs, ImportRowError{Row: row, Errors: messages})
+			}
+		}
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"dry_run": dryRun,
+			"total":   len(rows),
+			"valid":   len(rows) - len(errs),
+			"errors":  errs,
+		})
+	}
+	if dryRun {
+		return c.JSON(fiber.Map{"dry_run": true, "total": len(rows), "valid": len(rows), "errors": []ImportRowError{}})
+	}
+
+	created := make([]db.CreateJobRow, 0, len(params))
+	scheduled := false
+	err = inTx(c.Context(), h.pool, h.queries, func(q *db.Queries) error {
+		for i, arg := range params {
+			job, err := q.CreateJob(c.Context(), arg)
+			if err != nil {
+				return fmt.Errorf("row %d: %w", i+1, err)
+			}
+			created = append(created, job)
+			scheduled = scheduled || arg.PublishAt.Valid || arg.ExpiresAt.Valid
+		}
+		return nil
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to import jobs, nothing was saved: " + err.Error()})
+	}
+	if h.scheduler != nil && scheduled {
+		h.scheduler.Notify()
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"created": len(created), "jobs": created})
+}
+
+// ExportJobs returns a recruiter's jobs in the import format:
+// GET /jobs/recruiter/:id/export?format=csv|json&include_closed=true
+// Closed jobs are left out unless include_closed is set; they cannot be
+// imported again as they are.
+func (h *JobHandler) ExportJobs(c *fiber.Ctx) error {
+	var recruiterID pgtype.UUID
+	if err := recruiterID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Recruiter ID"})
+	}
+	format, err := jobsFormat(c, "", "csv")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	jobs, err := h.queries.ListJobsForExport(c.Context(), db.ListJobsForExportParams{
+		RecruiterID:   recruiterID,
+		IncludeClosed: c.QueryBool("include_closed"),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch jobs"})
+	}
+	rows := make([]CreateJobRequest, 0, len(jobs))
+	for _, job := range jobs {
+		rows = append(rows, exportRow(job))
+	}
+
+	if format == "json" {
+		c.Set(fiber.HeaderContentDisposition, `attachment; filename="jobs.json"`)
+		return c.JSON(rows)
+	}
+	body, err := encodeJobsCSV(rows)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to write CSV"})
+	}
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="jobs.csv"`)
+	return c.Send(body)
+}
+
+// jobsFormat reads ?format=, falling back to the request's content type
+// and then to fallback
+func jobsFormat(c *fiber.Ctx, contentType, fallback string) (string, error) {
+	switch f := strings.ToLower(c.Query("format")); f {
+	case "csv", "json":
+		return f, nil
+	case "":
+	default:
+		return "", fmt.Errorf("format must be csv or json")
+	}
+	if strings.Contains(contentType, "csv") {
+		return "csv", nil
+	}
+	if strings.Contains(contentType, "json") {
+		return "json", nil
+	}
+	return fallback, nil
+}
+
+func decodeJobsJSON(body []byte) ([]CreateJobRequest, map[int][]string, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, nil, fmt.Errorf("Body must be a JSON array of jobs")
+	}
+	rows := make([]CreateJobRequest, len(raw))
+	rowErrors := map[int][]string{}
+	for i, r := range raw {
+		if err := json.Unmarshal(r, &rows[i]); err != nil {
+			rowErrors[i+1] = []string{"invalid job object: " + err.Error()}
+		}
+	}
+	return rows, rowErrors, nil
+}
+
+func decodeJobsCSV(body []byte) ([]CreateJobRequest, map[int][]string, error) {
+	r := csv.NewReader(bytes.NewReader(body))
+	r.TrimLeadingSpace = true
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+
+	fields := map[string]int{}
+	t := reflect.TypeOf(CreateJobRequest{})
+	for i := 0; i < t.NumField(); i++ {
+		fields[jsonName(t.Field(i))] = i
+	}
+	columns := make([]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		field, ok := fields[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown CSV column %q; columns are %s", name, strings.Join(jobColumns, ", "))
+		}
+		columns[i] = field
+	}
+
+	var rows []CreateJobRequest
+	rowErrors := map[int][]string{}
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		row := len(rows) + 1
+		rows = append(rows, CreateJobRequest{})
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, nil, err
+			}
+			rowErrors[row] = []string{parseErr.Err.Error()}
+			if !errors.Is(parseErr.Err, csv.ErrFieldCount) {
+				// The reader cannot resync after a quoting error
+				return rows, rowErrors, nil
+			}
+			continue
+		}
+		v := reflect.ValueOf(&rows[len(rows)-1]).Elem()
+		for i, cell := range record {
+			if err := setJobField(v.Field(columns[i]), cell); err != nil {
+				rowErrors[row] = append(rowErrors[row], fmt.Sprintf("%s: %v", header[i], err))
+			}
+		}
+	}
+	return rows, rowErrors, nil
+}
+
+func encodeJobsCSV(rows []CreateJobRequest) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(jobColumns); err != nil {
+		return nil, err
+	}
+	record := make([]string, len(jobColumns))
+	for _, row := range rows {
+		v := reflect.ValueOf(row)
+		for i := range record {
+			record[i] = formatJobField(v.Field(i))
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// setJobField parses one CSV cell into a CreateJobRequest field; an empty
+// cell leaves the zero value
+func setJobField(f reflect.Value, cell string) error {
+	cell = strings.TrimSpace(cell)
+	if cell == "" {
+		return nil
+	}
+	switch f.Interface().(type) {
+	case string:
+		f.SetString(cell)
+	case int32:
+		n, err := strconv.ParseInt(cell, 10, 32)
+		if err != nil {
+			return fmt.Errorf("must be a whole number")
+		}
+		f.SetInt(n)
+	case bool:
+		b, err := strconv.ParseBool(cell)
+		if err != nil {
+			return fmt.Errorf("must be true or false")
+		}
+		f.SetBool(b)
+	case *time.Time:
+		t, err := time.Parse(time.RFC3339, cell)
+		if err != nil {
+			return fmt.Errorf("must be an RFC 3339 time")
+		}
+		f.Set(reflect.ValueOf(&t))
+	default:
+		return fmt.Errorf("unsupported column")
+	}
+	return nil
+}
+
+func formatJobField(f reflect.Value) string {
+	switch v := f.Interface().(type) {
+	case string:
+		return v
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case bool:
+		return strconv.FormatBool(v)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.UTC().Format(time.RFC3339)
+	}
+	return ""
+}
+
+func exportRow(job db.ListJobsForExportRow) CreateJobRequest {
+	row := CreateJobRequest{
+		RecruiterID:    job.RecruiterID.String(),
+		RecruiterEmail: job.RecruiterEmail.String,
+		Title:          job.Title,
+		JobSummary:     job.JobSummary.String,
+		Description:    job.Description,
+		Education:      job.EducationRequirements.String,
+		Skills:         job.SkillsRequirements.String,
+		ExperienceMin:  job.ExperienceMin.Int32,
+		ExperienceMax:  job.ExperienceMax.Int32,
+		IsUnpaid:       job.IsUnpaid.Bool,
+		SalaryMin:      job.SalaryMin.Int32,
+		SalaryMax:      job.SalaryMax.Int32,
+		Currency:       job.Currency.String,
+		PayPeriod:      job.PayPeriod,
+		JobType:        job.JobType.String,
+		LocationType:   job.LocationType.String,
+		LocationCity:   job.LocationCity.String,
+		Status:         job.Status.String,
+	}
+	if job.PublishAt.Valid {
+		t := job.PublishAt.Time
+		row.PublishAt = &t
+	}
+	if job.ExpiresAt.Valid {
+		t := job.ExpiresAt.Time
+		row.ExpiresAt = &t
+	}
+	return row
+}
+
+// validationMessages turns validator errors into one readable message per
+// field, named as in the JSON and CSV
+func validationMessages(err error) []string {
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return []string{err.Error()}
+	}
+	t := reflect.TypeOf(CreateJobRequest{})
+	messages := make([]string, 0, len(fieldErrors))
+	for _, fe := range fieldErrors {
+		name := fe.Field()
+		if sf, ok := t.FieldByName(fe.StructField()); ok {
+			name = jsonName(sf)
+		}
+		switch fe.Tag() {
+		case "required":
+			messages = append(messages, name+": is required")
+		case "email":
+			messages = append(messages, name+": must be an email address")
+		case "uuid":
+			messages = append(messages, name+": must be a UUID")
+		case "oneof":
+			messages = append(messages, name+": must be one of "+fe.Param())
+		default:
+			messages = append(messages, fmt.Sprintf("%s: failed %s validation", name, fe.Tag()))
+		}
+	}
+	return messages
+}
+
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/aswinbala005/rizeos/api/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultJobEventLimit = 50
+	maxJobEventLimit     = 200
+)
+
+// JobActionRequest is the optional body of extend, close and reopen: who
+// is acting, recorded on the job and its event
+type JobActionRequest struct {
+	UpdatedBy string `json:"updated_by" validate:"omitempty,uuid"`
+}
+
+// jobActor reads the acting user from a JobActionRequest body; NULL when
+// there is none. Errors are *httpError.
+func (h *JobHandler) jobActor(c *fiber.Ctx) (pgtype.UUID, error) {
+	var actorID pgtype.UUID
+	var req JobActionRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return actorID, &httpError{fiber.StatusBadRequest, "Invalid request body"}
+		}
+		if err := h.validate.Struct(req); err != nil {
+			return actorID, &httpError{fiber.StatusBadRequest, err.Error()}
+		}
+	}
+	if req.UpdatedBy != "" {
+		if err := actorID.Scan(req.UpdatedBy); err != nil {
+			return actorID, &httpError{fiber.StatusBadRequest, "Invalid updated_by"}
+		}
+	}
+	return actorID, nil
+}
+
+// jobActionConflict answers a job action that changed nothing: 404 when
+// the job does not exist, else 409 with conflict, formatted with the job's
+// status
+func (h *JobHandler) jobActionConflict(c *fiber.Ctx, jobID pgtype.UUID, conflict string) error {
+	job, err := h.queries.GetJobByID(c.Context(), jobID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Job not found"})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch job"})
+	}
+	return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": fmt.Sprintf(conflict, job.Status.String)})
+}
+
+// ExtendJob restarts an open job's age and inactivity clocks so the
+// sweeper leaves it open: POST /jobs/:id/extend
+func (h *JobHandler) ExtendJob(c *fiber.Ctx) error {
+	var jobID pgtype.UUID
+	if err := jobID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Job ID"})
+	}
+	actorID, err := h.jobActor(c)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	extendedAt, err := h.queries.ExtendJob(c.Context(), db.ExtendJobParams{ActorID: actorID, ID: jobID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return h.jobActionConflict(c, jobID, "Only open jobs can be extended (this job is %s); reopen it instead")
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to extend job"})
+	}
+	return c.JSON(fiber.Map{"job_id": jobID, "extended_at": extendedAt})
+}
+
+// ListJobEvents is the lifecycle feed for the recruiter dashboard:
+// GET /jobs/recruiter/:id/events?job_id=&limit=
+func (h *JobHandler) ListJobEvents(c *fiber.Ctx) error {
+	var recruiterID, jobID pgtype.UUID
+	if err := recruiterID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Recruiter ID"})
+	}
+	if v := c.Query("job_id"); v != "" {
+		if err := jobID.Scan(v); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Job ID"})
+		}
+	}
+	limit := c.QueryInt("limit", defaultJobEventLimit)
+	if limit < 1 || limit > maxJobEventLimit {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("limit must be between 1 and %d", maxJobEventLimit)})
+	}
+
+	events, err := h.queries.ListRecruiterJobEvents(c.Context(), db.ListRecruiterJobEventsParams{
+		RecruiterID: recruiterID,
+		JobID:       jobID,
+		PageLimit:   int32(limit),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch job events"})
+	}
+	if events == nil {
+		return c.JSON([]interface{}{})
+	}
+	return c.JSON(events)
+}
+
+// statusChangeEvent is the job_events kind for a status change made
+// through PUT /jobs/:id, if it is one worth recording
+func statusChangeEvent(from, to string) (kind string, reason pgtype.Text, ok bool) {
+	switch {
+	case from == to:
+		return "", reason, false
+	case to == JobStatusClosed:
+		return services.JobEventClosed, pgtype.Text{String: services.CloseReasonManual, Valid: true}, true
+	case to == JobStatusOpen && from == JobStatusDraft:
+		return services.JobEventPublished, reason, true
+	case to == JobStatusOpen:
+		return services.JobEventReopened, reason, true
+	}
+	return "", reason, false
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/aswinbala005/rizeos/api/internal/services"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// How long a job description parse may take before the request fails
+const jobParseTimeout = 60 * time.Second
+
+type JobParserHandler struct {
+	parser   services.JobDescriptionParser
+	validate *validator.Validate
+}
+
+func NewJobParserHandler(parser services.JobDescriptionParser) *JobParserHandler {
+	return &JobParserHandler{
+		parser:   parser,
+		validate: validator.New(),
+	}
+}
+
+type ParseJobDescriptionRequest struct {
+	Description string `json:"description" validate:"required,min=50,max=20000"`
+}
+
+// ParseJobDescription turns a pasted job description into a draft for the
+// post-job form. Nothing is saved; the recruiter reviews the draft and
+// submits it through POST /jobs.
+func (h *JobParserHandler) ParseJobDescription(c *fiber.Ctx) error {
+	var req ParseJobDescriptionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	req.Description = strings.TrimSpace(req.Description)
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), jobParseTimeout)
+	defer cancel()
+
+	draft, err := h.parser.Parse(ctx, req.Description)
+	if err != nil {
+		if errors.Is(err, services.ErrLLMNotConfigured) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "AI parsing is not configured"})
+		}
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": "Failed to parse job description: " + err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"description": req.Description,
+		"draft":       draft,
+	})
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aswinbala005/rizeos/api/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+const testJobDescription = "We are hiring a Senior Go Engineer in Bengaluru to build payment APIs. " +
+	"5-8 years of experience with Go and PostgreSQL. Salary 30-45 LPA. Hybrid, full-time."
+
+func parseJobDescription(t *testing.T, llm *services.FakeLLMClient, body string) (int, map[string]json.RawMessage) {
+	t.Helper()
+	app := fiber.New()
+	app.Post("/parse-job-description", NewJobParserHandler(services.JobDescriptionParser{Client: llm}).ParseJobDescription)
+
+	req := httptest.NewRequest(http.MethodPost, "/parse-job-description", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read response: %v", err)
+	}
+	out := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatalf("response is not a JSON object: %s", raw)
+	}
+	return resp.StatusCode, out
+}
+
+func requestBody(t *testing.T, description string) string {
+	t.Helper()
+	b, err := json.Marshal(ParseJobDescriptionRequest{Description: description})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestParseJobDescriptionRejectsBadRequests(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"not JSON", "description=hello"},
+		{"missing description", `{}`},
+		{"too short", requestBody(t, "Go engineer, Bengaluru")},
+		{"only whitespace", requestBody(t, strings.Repeat(" ", 80))},
+		{"too long", requestBody(t, strings.Repeat("a", 20001))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm := &services.FakeLLMClient{Reply: `{"title": "Go Engineer"}`}
+			status, body := parseJobDescription(t, llm, tt.body)
+			if status != fiber.StatusBadRequest {
+				t.Fatalf("status = %d, want %d (body %s)", status, fiber.StatusBadRequest, body["error"])
+			}
+			if len(llm.Prompts()) != 0 {
+				t.Errorf("the model was called for an invalid request")
+			}
+		})
+	}
+}
+
+func TestParseJobDescriptionModelFailures(t *testing.T) {
+	tests := []struct {
+		name   string
+		llm    *services.FakeLLMClient
+		status int
+	}{
+		{"not configured", &services.FakeLLMClient{Err: services.ErrLLMNotConfigured}, fiber.StatusServiceUnavailable},
+		{"model error", &services.FakeLLMClient{Err: errors.New("upstream timeout")}, fiber.StatusBadGateway},
+		{"no JSON in reply", &services.FakeLLMClient{Reply: "Sorry, I can't help with that."}, fiber.StatusBadGateway},
+		{"malformed JSON", &services.FakeLLMClient{Reply: `{"title": "Go Engineer", "experience_min": }`}, fiber.StatusBadGateway},
+		{"JSON of the wrong shape", &services.FakeLLMClient{Reply: `{"title": ["Go", "Engineer"]}`}, fiber.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := parseJobDescription(t, tt.llm, requestBody(t, testJobDescription))
+			if status != tt.status {
+				t.Fatalf("status = %d, want %d (body %s)", status, tt.status, body["error"])
+			}
+			if _, ok := body["draft"]; ok {
+				t.Errorf("a failed parse returned a draft")
+			}
+		})
+	}
+}
+
+func TestParseJobDescriptionReturnsDraft(t *testing.T) {
+	llm := &services.FakeLLMClient{Reply: "```json\n" + `{
+		"title": "Senior Go Engineer",
+		"job_summary": "Build payment APIs.",
+		"skills_requirements": "Go, PostgreSQL, go",
+		"experience_min": "8",
+		"experience_max": 5,
+		"salary_min": "30,00,000",
+		"salary_max": 4500000,
+		"currency": "inr",
+		"job_type": "Full time",
+		"location_type": "hybrid",
+		"location_city": "Bengaluru"
+	}` + "\n```"}
+
+	status, body := parseJobDescription(t, llm, requestBody(t, "  "+testJobDescription+"\n"))
+	if status != fiber.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", status, body["error"])
+	}
+
+	var description string
+	if err := json.Unmarshal(body["description"], &description); err != nil || description != testJobDescription {
+		t.Errorf("description = %s, want the trimmed input", body["description"])
+	}
+	var draft services.JobDraft
+	if err := json.Unmarshal(body["draft"], &draft); err != nil {
+		t.Fatalf("draft did not decode: %v", err)
+	}
+	if draft.Title != "Senior Go Engineer" || draft.JobType != "Full-time" || draft.LocationType != "Hybrid" {
+		t.Errorf("draft = %+v", draft)
+	}
+	if draft.ExperienceMin != 5 || draft.ExperienceMax != 8 {
+		t.Errorf("experience = %d-%d, want the reversed range swapped to 5-8", draft.ExperienceMin, draft.ExperienceMax)
+	}
+	if draft.SalaryMin != 3000000 || draft.SalaryMax != 4500000 || draft.Currency != "INR" {
+		t.Errorf("salary = %d-%d %s, want 3000000-4500000 INR", draft.SalaryMin, draft.SalaryMax, draft.Currency)
+	}
+	if draft.SkillsRequirements != "Go, PostgreSQL" {
+		t.Errorf("skills = %q, want duplicates dropped", draft.SkillsRequirements)
+	}
+	if len(draft.Warnings) != 1 || !strings.Contains(draft.Warnings[0], "reversed") {
+		t.Errorf("warnings = %q, want only the swapped experience range", draft.Warnings)
+	}
+
+	prompts := llm.Prompts()
+	if len(prompts) != 1 || !strings.Contains(prompts[0], testJobDescription) {
+		t.Errorf("the model was not sent the description exactly once")
+	}
+}
@@ -0,0 +1,357 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/aswinbala005/rizeos/api/internal/services"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const maxSavedSearches = 25
+
+// JobPreferencesHandler serves what candidates are looking for: their job
+// preferences, which shape ListJobs, and their saved searches. Both feed
+// the job digest.
+type JobPreferencesHandler struct {
+	queries  *db.Queries
+	validate *validator.Validate
+}
+
+func NewJobPreferencesHandler(queries *db.Queries) *JobPreferencesHandler {
+	return &JobPreferencesHandler{queries: queries, validate: validator.New()}
+}
+
+// JobPreferencesRequest replaces a candidate's preferences. remote_only,
+// job_types and min_salary (per pay_period, in salary_currency) rule jobs
+// out; roles and locations (within radius_km) rank jobs higher. alerts
+// turns on the job digest.
+type JobPreferencesRequest struct {
+	Roles          []string `json:"roles" validate:"max=10,dive,required,max=100"`
+	Locations      []string `json:"locations" validate:"max=10,dive,required,max=100"`
+	RadiusKm       int32    `json:"radius_km" validate:"omitempty,min=1,max=1000"`
+	RemoteOnly     bool     `json:"remote_only"`
+	JobTypes       []string `json:"job_types" validate:"dive,oneof=Full-time Part-time Contract Internship Freelance"`
+	MinSalary      *int32   `json:"min_salary" validate:"omitempty,min=0"`
+	SalaryCurrency string   `json:"salary_currency"`
+	PayPeriod      string   `json:"pay_period" validate:"omitempty,oneof=HOUR DAY WEEK MONTH YEAR"`
+	Alerts         bool     `json:"alerts"`
+}
+
+// GetJobPreferences returns a candidate's preferences, or the defaults if
+// they have not set any: GET /users/:id/job-preferences
+func (h *JobPreferencesHandler) GetJobPreferences(c *fiber.Ctx) error {
+	var userID pgtype.UUID
+	if err := userID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+	}
+	prefs, err := h.queries.GetJobPreferences(c.Context(), userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.JSON(db.JobPreference{
+			UserID:    userID,
+			Roles:     []string{},
+			Locations: []string{},
+			RadiusKm:  defaultRadiusKm,
+			JobTypes:  []string{},
+			PayPeriod: services.PayPeriodYear,
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch job preferences"})
+	}
+	return c.JSON(prefs)
+}
+
+// UpdateJobPreferences replaces a candidate's preferences:
+// PUT /users/:id/job-preferences
+func (h *JobPreferencesHandler) UpdateJobPreferences(c *fiber.Ctx) error {
+	userID, err := h.candidateID(c.Context(), c.Params("id"))
+	if err != nil {
+		return sendError(c, err)
+	}
+	var req JobPreferencesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	currency := strings.ToUpper(strings.TrimSpace(req.SalaryCurrency))
+	if req.MinSalary != nil && len(currency) != 3 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "min_salary needs a salary_currency such as INR"})
+	}
+	if req.RadiusKm == 0 {
+		req.RadiusKm = defaultRadiusKm
+	}
+	if req.PayPeriod == "" {
+		req.PayPeriod = services.PayPeriodYear
+	}
+
+	prefs, err := h.queries.UpsertJobPreferences(c.Context(), db.UpsertJobPreferencesParams{
+		UserID:         userID,
+		Roles:          trimmedList(req.Roles),
+		Locations:      trimmedList(req.Locations),
+		RadiusKm:       req.RadiusKm,
+		RemoteOnly:     req.RemoteOnly,
+		JobTypes:       trimmedList(req.JobTypes),
+		MinSalary:      optionalInt4(req.MinSalary),
+		SalaryCurrency: pgtype.Text{String: currency, Valid: req.MinSalary != nil},
+		PayPeriod:      req.PayPeriod,
+		Alerts:         req.Alerts,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to save job preferences"})
+	}
+	return c.JSON(prefs)
+}
+
+// DeleteJobPreferences clears a candidate's preferences:
+// DELETE /users/:id/job-preferences
+func (h *JobPreferencesHandler) DeleteJobPreferences(c *fiber.Ctx) error {
+	var userID pgtype.UUID
+	if err := userID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+	}
+	if _, err := h.queries.DeleteJobPreferences(c.Context(), userID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete job preferences"})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// SavedSearchRequest names a /jobs/search query string, e.g.
+// "q=golang&near=Bangalore&salary_min=1500000"; alerts defaults to true
+type SavedSearchRequest struct {
+	Name   string `json:"name" validate:"required,max=100"`
+	Query  string `json:"query" validate:"required,max=2000"`
+	Alerts *bool  `json:"alerts"`
+}
+
+// SavedSearchResponse is a saved search as clients see it
+type SavedSearchResponse struct {
+	ID             pgtype.UUID        `json:"id"`
+	Name           string             `json:"name"`
+	Query          string             `json:"query"`
+	Alerts         bool               `json:"alerts"`
+	LastNotifiedAt pgtype.Timestamptz `json:"last_notified_at"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
+
+func newSavedSearchResponse(s db.SavedSearch) SavedSearchResponse {
+	return SavedSearchResponse{
+		ID:             s.ID,
+		Name:           s.Name,
+		Query:          s.Query,
+		Alerts:         s.Alerts,
+		LastNotifiedAt: s.LastNotifiedAt,
+		CreatedAt:      s.CreatedAt,
+		UpdatedAt:      s.UpdatedAt,
+	}
+}
+
+// ListSavedSearches: GET /users/:id/saved-searches
+func (h *JobPreferencesHandler) ListSavedSearches(c *fiber.Ctx) error {
+	var userID pgtype.UUID
+	if err := userID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+	}
+	searches, err := h.queries.ListSavedSearches(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch saved searches"})
+	}
+	response := make([]SavedSearchResponse, 0, len(searches))
+	for _, s := range searches {
+		response = append(response, newSavedSearchResponse(s))
+	}
+	return c.JSON(response)
+}
+
+// CreateSavedSearch saves a search; its query is checked the way
+// /jobs/search would check it: POST /users/:id/saved-searches
+func (h *JobPreferencesHandler) CreateSavedSearch(c *fiber.Ctx) error {
+	userID, err := h.candidateID(c.Context(), c.Params("id"))
+	if err != nil {
+		return sendError(c, err)
+	}
+	var req SavedSearchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	count, err := h.queries.CountSavedSearches(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to count saved searches"})
+	}
+	if count >= maxSavedSearches {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": fmt.Sprintf("You can save at most %d searches", maxSavedSearches)})
+	}
+	compiled, err := compileSavedSearch(c.Context(), h.queries, req.Query)
+	if err != nil {
+		return savedSearchError(c, err)
+	}
+
+	search, err := h.queries.CreateSavedSearch(c.Context(), db.CreateSavedSearchParams{
+		UserID:         userID,
+		Name:           strings.TrimSpace(req.Name),
+		Query:          compiled.Query,
+		Filters:        compiled.Filters,
+		SalaryCurrency: compiled.SalaryCurrency,
+		Alerts:         req.Alerts == nil || *req.Alerts,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to save search"})
+	}
+	return c.Status(fiber.StatusCreated).JSON(newSavedSearchResponse(search))
+}
+
+// UpdateSavedSearch replaces a saved search:
+// PUT /users/:id/saved-searches/:searchId
+func (h *JobPreferencesHandler) UpdateSavedSearch(c *fiber.Ctx) error {
+	var userID, searchID pgtype.UUID
+	if err := userID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+	}
+	if err := searchID.Scan(c.Params("searchId")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Saved Search ID"})
+	}
+	var req SavedSearchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	compiled, err := compileSavedSearch(c.Context(), h.queries, req.Query)
+	if err != nil {
+		return savedSearchError(c, err)
+	}
+
+	search, err := h.queries.UpdateSavedSearch(c.Context(), db.UpdateSavedSearchParams{
+		Name:           strings.TrimSpace(req.Name),
+		Query:          compiled.Query,
+		Filters:        compiled.Filters,
+		SalaryCurrency: compiled.SalaryCurrency,
+		Alerts:         req.Alerts == nil || *req.Alerts,
+		ID:             searchID,
+		UserID:         userID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Saved search not found"})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update saved search"})
+	}
+	return c.JSON(newSavedSearchResponse(search))
+}
+
+// DeleteSavedSearch: DELETE /users/:id/saved-searches/:searchId
+func (h *JobPreferencesHandler) DeleteSavedSearch(c *fiber.Ctx) error {
+	var userID, searchID pgtype.UUID
+	if err := userID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+	}
+	if err := searchID.Scan(c.Params("searchId")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Saved Search ID"})
+	}
+	deleted, err := h.queries.DeleteSavedSearch(c.Context(), db.DeleteSavedSearchParams{ID: searchID, UserID: userID})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete saved search"})
+	}
+	if deleted == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Saved search not found"})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// candidateID parses a user ID and checks it belongs to a candidate
+func (h *JobPreferencesHandler) candidateID(ctx context.Context, id string) (pgtype.UUID, error) {
+	var userID pgtype.UUID
+	if err := userID.Scan(id); err != nil {
+		return userID, &httpError{fiber.StatusBadRequest, "Invalid User ID"}
+	}
+	user, err := h.queries.GetUserByID(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return userID, &httpError{fiber.StatusNotFound, "User not found"}
+	}
+	if err != nil {
+		return userID, &httpError{fiber.StatusInternalServerError, "Failed to fetch user"}
+	}
+	if user.Role != db.UserRoleCANDIDATE {
+		return userID, &httpError{fiber.StatusForbidden, "Only candidates can save job preferences and searches"}
+	}
+	return userID, nil
+}
+
+// compiledSearch is a saved search as stored: the query string without
+// paging, and the SearchJobs filters the digest runs
+type compiledSearch struct {
+	Query          string
+	Filters        []byte
+	SalaryCurrency pgtype.Text
+}
+
+// compileSavedSearch parses a /jobs/search query string into the
+// SearchJobs filters the digest runs, with its place looked up now. The
+// salary currency is only checked: the digest converts at the rate of the
+// day it runs. Paging and posted_since are left to whoever runs it.
+func compileSavedSearch(ctx context.Context, queries *db.Queries, query string) (compiledSearch, error) {
+	query = strings.TrimPrefix(strings.TrimSpace(query), "?")
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return compiledSearch{}, invalidFilterError("query is not a valid query string")
+	}
+	for _, paging := range []string{"cursor", "limit", "posted_since", "candidate_id"} {
+		values.Del(paging)
+	}
+	f, err := parseJobSearchFilters(savedQuery(values))
+	if err != nil {
+		return compiledSearch{}, invalidFilterError(err.Error())
+	}
+	if err := f.resolve(ctx, queries); err != nil {
+		return compiledSearch{}, err
+	}
+	params := f.params()
+	params.TargetRate = pgtype.Float8{}
+	filters, err := json.Marshal(params)
+	if err != nil {
+		return compiledSearch{}, err
+	}
+	return compiledSearch{
+		Query:          values.Encode(),
+		Filters:        filters,
+		SalaryCurrency: pgtype.Text{String: f.SalaryCurrency, Valid: f.SalaryCurrency != ""},
+	}, nil
+}
+
+func savedSearchError(c *fiber.Ctx, err error) error {
+	var invalid invalidFilterError
+	if errors.As(err, &invalid) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to prepare search"})
+}
+
+// trimmedList trims each value and drops blanks and repeats
+func trimmedList(values []string) []string {
+	out := []string{}
+	seen := map[string]bool{}
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[strings.ToLower(v)] {
+			continue
+		}
+		seen[strings.ToLower(v)] = true
+		out = append(out, v)
+	}
+	return out
+}
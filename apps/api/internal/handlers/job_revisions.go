@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/aswinbala005/rizeos/api/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// JobRevision is one saved version of a job and what changed in it
+type JobRevision struct {
+	Revision      int32                  `json:"revision"`
+	ChangedBy     pgtype.UUID            `json:"changed_by"`
+	ChangedByName pgtype.Text            `json:"changed_by_name"`
+	CreatedAt     pgtype.Timestamptz     `json:"created_at"`
+	Changes       []services.FieldChange `json:"changes"`
+}
+
+// GetJobRevisions returns a job's history, newest first, each revision with
+// its field-level changes: GET /jobs/:id/revisions?since=
+// With since (usually an application's job_revision), only later revisions
+// are listed and changes_since sums them up: what changed after the
+// candidate applied.
+func (h *JobHandler) GetJobRevisions(c *fiber.Ctx) error {
+	var jobID pgtype.UUID
+	if err := jobID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Job ID"})
+	}
+	since := int32(0)
+	if v := c.Query("since"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || n < 1 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "since must be a revision number"})
+		}
+		since = int32(n)
+	}
+
+	rows, err := h.queries.ListJobRevisions(c.Context(), jobID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch revisions"})
+	}
+	// Every job has at least the revision written when it was created
+	if len(rows) == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Job not found"})
+	}
+	if since > rows[len(rows)-1].Revision {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "since is after the latest revision"})
+	}
+
+	revisions := []JobRevision{}
+	var previous, base []byte
+	for _, row := range rows {
+		if row.Revision == since {
+			base = row.Snapshot
+		}
+		if row.Revision > since {
+			changes, err := services.DiffJobSnapshots(previous, row.Snapshot)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			}
+			revisions = append(revisions, JobRevision{
+				Revision:      row.Revision,
+				ChangedBy:     row.ChangedBy,
+				ChangedByName: row.ChangedByName,
+				CreatedAt:     row.CreatedAt,
+				Changes:       changes,
+			})
+		}
+		previous = row.Snapshot
+	}
+	for i, j := 0, len(revisions)-1; i < j; i, j = i+1, j-1 {
+		revisions[i], revisions[j] = revisions[j], revisions[i]
+	}
+
+	response := fiber.Map{
+		"job_id":           jobID,
+		"current_revision": rows[len(rows)-1].Revision,
+		"revisions":        revisions,
+	}
+	if since > 0 {
+		changes := []services.FieldChange{}
+		if base != nil {
+			if changes, err = services.DiffJobSnapshots(base, previous); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
+		response["since"] = since
+		response["changes_since"] = changes
+	}
+	return c.JSON(response)
+}
@@ -0,0 +1,426 @@
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/aswinbala005/rizeos/api/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultJobPageSize = 20
+	maxJobPageSize     = 50
+	maxCityFacets      = 20
+)
+
+// FacetCount is one filter chip: a value and how many jobs it would match
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// jobSearchFilters holds the parsed query string shared by the result and
+// facet queries
+type jobSearchFilters struct {
+	Q              pgtype.Text
+	LocationTypes  []string
+	LocationCities []string
+	JobTypes       []string
+	Currencies     []string
+	IsUnpaid       pgtype.Bool
+	SalaryMin      pgtype.Int8 // yearly
+	SalaryMax      pgtype.Int8 // yearly
+	SalaryCurrency string
+	TargetRate     pgtype.Float8 // SalaryCurrency units per US dollar
+	ExperienceMin  pgtype.Int4
+	ExperienceMax  pgtype.Int4
+	PostedSince    pgtype.Timestamptz
+	Geo            geoFilter
+	IncludeRemote  bool // with Geo, whether remote jobs match wherever they are
+}
+
+// SearchJobs is the filtered, paginated job feed:
+// GET /jobs/search?q=&location_type=&location_city=&job_type=&currency=
+// &salary_min=&salary_max=&salary_period=&salary_currency=
+// &experience_min=&experience_max=&is_unpaid=&posted_since=
+// &near=&lat=&lng=&radius_km=&include_remote=&limit=&cursor=
+// List filters take comma-separated values. Salary filters are amounts per
+// salary_period (default YEAR) compared with each job's yearly salary; with
+// salary_currency they are in that currency and jobs are converted to it.
+// near (a place name) or lat and lng limit jobs to those within radius_km
+// (default 50) of that point, each with its distance_km; remote jobs
+// still match unless include_remote=false.
+// The response carries the page of jobs, a next_cursor (null on the last
+// page) and facet counts for the filter chips. With candidate_id, each job
+// also gets its match_score.
+func (h *JobHandler) SearchJobs(c *fiber.Ctx) error {
+	f, err := parseJobSearchFilters(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	var invalid invalidFilterError
+	if err := f.resolve(c.Context(), h.queries); errors.As(err, &invalid) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to prepare search"})
+	}
+
+	limit := c.QueryInt("limit", defaultJobPageSize)
+	if limit < 1 || limit > maxJobPageSize {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("limit must be between 1 and %d", maxJobPageSize)})
+	}
+	var cursorAt pgtype.Timestamptz
+	var cursorID pgtype.UUID
+	if cursor := c.Query("cursor"); cursor != "" {
+		if cursorAt, cursorID, err = decodeJobCursor(cursor); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid cursor"})
+		}
+	}
+
+	// One extra row tells us whether there is a next page
+	arg := f.params()
+	arg.CursorCreatedAt, arg.CursorID, arg.PageLimit = cursorAt, cursorID, int32(limit+1)
+	jobs, err := h.queries.SearchJobs(c.Context(), arg)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to search jobs"})
+	}
+
+	var nextCursor interface{}
+	if len(jobs) > limit {
+		jobs = jobs[:limit]
+		last := jobs[len(jobs)-1]
+		nextCursor = encodeJobCursor(last.CreatedAt, last.ID)
+	}
+
+	facetRows, err := h.queries.SearchJobFacets(c.Context(), db.SearchJobFacetsParams{
+		LocationTypes:  f.LocationTypes,
+		LocationCities: f.LocationCities,
+		JobTypes:       f.JobTypes,
+		Currencies:     f.Currencies,
+		IsUnpaid:       f.IsUnpaid,
+		PostedSince:    f.PostedSince,
+		Q:              f.Q,
+		SalaryMin:      f.SalaryMin,
+		TargetRate:     f.TargetRate,
+		SalaryMax:      f.SalaryMax,
+		ExperienceMin:  f.ExperienceMin,
+		ExperienceMax:  f.ExperienceMax,
+		CenterLat:      f.Geo.CenterLat,
+		IncludeRemote:  f.IncludeRemote,
+		CenterLng:      f.Geo.CenterLng,
+		RadiusKm:       f.Geo.RadiusKm,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to count facets"})
+	}
+	total := int64(0)
+	facets := map[string][]FacetCount{
+		"location_type": {},
+		"location_city": {},
+		"job_type":      {},
+		"currency":      {},
+		"is_unpaid":     {},
+		"posted_since":  {},
+	}
+	for _, row := range facetRows {
+		if row.Facet == "total" {
+			total = row.Count
+			continue
+		}
+		if row.Value == "" || (row.Facet == "location_city" && len(facets[row.Facet]) >= maxCityFacets) {
+			continue
+		}
+		facets[row.Facet] = append(facets[row.Facet], FacetCount{Value: row.Value, Count: row.Count})
+	}
+
+	return c.JSON(fiber.Map{
+		"jobs":        h.withMatchScores(c, jobs),
+		"next_cursor": nextCursor,
+		"total":       total,
+		"facets":      facets,
+		"near":        f.Geo.Place,
+	})
+}
+
+// withMatchScores adds the Smart Score for ?candidate_id= to each job,
+// keeping the search order so cursors stay valid
+func (h *JobHandler) withMatchScores(c *fiber.Ctx, jobs []db.SearchJobsRow) interface{} {
+	if jobs == nil {
+		return []interface{}{}
+	}
+
+	var candidateID pgtype.UUID
+	if err := candidateID.Scan(c.Query("candidate_id")); err != nil {
+		return jobs
+	}
+	user, err := h.queries.GetUserByID(c.Context(), candidateID)
+	if err != nil {
+		return jobs
+	}
+
+	type JobWithMatch struct {
+		db.SearchJobsRow
+		MatchScore int `json:"match_score"`
+	}
+	response := make([]JobWithMatch, 0, len(jobs))
+	for _, job := range jobs {
+		score := calculateSmartScore(user, db.ListJobsRow{
+			ID:                    job.ID,
+			RecruiterID:           job.RecruiterID,
+			Title:                 job.Title,
+			Description:           job.Description,
+			IsPaid:                job.IsPaid,
+			CreatedAt:             job.CreatedAt,
+			UpdatedAt:             job.UpdatedAt,
+			JobType:               job.JobType,
+			LocationType:          job.LocationType,
+			LocationCity:          job.LocationCity,
+			SalaryMin:             job.SalaryMin,
+			SalaryMax:             job.SalaryMax,
+			Currency:              job.Currency,
+			JobSummary:            job.JobSummary,
+			EducationRequirements: job.EducationRequirements,
+			SkillsRequirements:    job.SkillsRequirements,
+			IsUnpaid:              job.IsUnpaid,
+			OrganizationName:      job.OrganizationName,
+			PayPeriod:             job.PayPeriod,
+		})
+		response = append(response, JobWithMatch{SearchJobsRow: job, MatchScore: score})
+	}
+	return response
+}
+
+// ListExchangeRates lists the currencies salary_currency accepts, with
+// how many units of each buy one US dollar: GET /exchange-rates
+func (h *JobHandler) ListExchangeRates(c *fiber.Ctx) error {
+	rates, err := h.queries.ListExchangeRates(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch exchange rates"})
+	}
+	if rates == nil {
+		return c.JSON([]interface{}{})
+	}
+	return c.JSON(rates)
+}
+
+// queryArgs is where search filters are read from: a request, or a saved
+// search's query string
+type queryArgs interface {
+	Query(key string, defaultValue ...string) string
+}
+
+// savedQuery reads a saved query string the way fiber reads a request's
+type savedQuery url.Values
+
+func (q savedQuery) Query(key string, defaultValue ...string) string {
+	if v := url.Values(q).Get(key); v != "" {
+		return v
+	}
+	if len(defaultValue) > 0 {
+		return defaultValue[0]
+	}
+	return ""
+}
+
+// invalidFilterError is a search filter the client has to fix, such as a
+// salary_currency we have no exchange rate for
+type invalidFilterError string
+
+func (e invalidFilterError) Error() string { return string(e) }
+
+// resolve looks up the exchange rate of the salary currency and the place
+// near names
+func (f *jobSearchFilters) resolve(ctx context.Context, queries *db.Queries) error {
+	if f.SalaryCurrency != "" {
+		rate, err := queries.GetExchangeRate(ctx, f.SalaryCurrency)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return invalidFilterError("No exchange rate for salary_currency " + f.SalaryCurrency)
+		}
+		if err != nil {
+			return err
+		}
+		f.TargetRate = pgtype.Float8{Float64: rate, Valid: true}
+	}
+	if err := f.Geo.resolve(ctx, queries); errors.Is(err, errUnknownPlace) {
+		return invalidFilterError("Unknown place for near: " + f.Geo.Near)
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// params is the SearchJobs query for the filters, less the page
+func (f *jobSearchFilters) params() db.SearchJobsParams {
+	return db.SearchJobsParams{
+		TargetRate:     f.TargetRate,
+		CenterLat:      f.Geo.CenterLat,
+		CenterLng:      f.Geo.CenterLng,
+		Q:              f.Q,
+		LocationTypes:  f.LocationTypes,
+		LocationCities: f.LocationCities,
+		JobTypes:       f.JobTypes,
+		Currencies:     f.Currencies,
+		IsUnpaid:       f.IsUnpaid,
+		SalaryMin:      f.SalaryMin,
+		SalaryMax:      f.SalaryMax,
+		ExperienceMin:  f.ExperienceMin,
+		ExperienceMax:  f.ExperienceMax,
+		PostedSince:    f.PostedSince,
+		IncludeRemote:  f.IncludeRemote,
+		RadiusKm:       f.Geo.RadiusKm,
+	}
+}
+
+func parseJobSearchFilters(c queryArgs) (*jobSearchFilters, error) {
+	f := &jobSearchFilters{
+		LocationTypes:  queryList(c, "location_type", false),
+		LocationCities: queryList(c, "location_city", true),
+		JobTypes:       queryList(c, "job_type", false),
+		Currencies:     queryList(c, "currency", false),
+	}
+	for i, currency := range f.Currencies {
+		f.Currencies[i] = strings.ToUpper(currency)
+	}
+
+	if q := strings.TrimSpace(c.Query("q")); q != "" {
+		f.Q = pgtype.Text{String: q, Valid: true}
+	}
+	if v := c.Query("is_unpaid"); v != "" {
+		unpaid, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("is_unpaid must be true or false")
+		}
+		f.IsUnpaid = pgtype.Bool{Bool: unpaid, Valid: true}
+	}
+
+	var err error
+	var salaryMin, salaryMax pgtype.Int4
+	for _, p := range []struct {
+		name string
+		dst  *pgtype.Int4
+	}{
+		{"salary_min", &salaryMin},
+		{"salary_max", &salaryMax},
+		{"experience_min", &f.ExperienceMin},
+		{"experience_max", &f.ExperienceMax},
+	} {
+		if *p.dst, err = queryInt4(c, p.name); err != nil {
+			return nil, err
+		}
+	}
+	if salaryMin.Valid && salaryMax.Valid && salaryMin.Int32 > salaryMax.Int32 {
+		return nil, fmt.Errorf("salary_min cannot be greater than salary_max")
+	}
+	period := strings.ToUpper(c.Query("salary_period", services.PayPeriodYear))
+	perYear, ok := services.PayPeriodsPerYear[period]
+	if !ok {
+		return nil, fmt.Errorf("salary_period must be one of HOUR, DAY, WEEK, MONTH or YEAR")
+	}
+	f.SalaryMin = pgtype.Int8{Int64: int64(salaryMin.Int32) * perYear, Valid: salaryMin.Valid}
+	f.SalaryMax = pgtype.Int8{Int64: int64(salaryMax.Int32) * perYear, Valid: salaryMax.Valid}
+	f.SalaryCurrency = strings.ToUpper(strings.TrimSpace(c.Query("salary_currency")))
+	if f.ExperienceMin.Valid && f.ExperienceMax.Valid && f.ExperienceMin.Int32 > f.ExperienceMax.Int32 {
+		return nil, fmt.Errorf("experience_min cannot be greater than experience_max")
+	}
+
+	if v := c.Query("posted_since"); v != "" {
+		since, err := parsePostedSince(v, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		f.PostedSince = pgtype.Timestamptz{Time: since, Valid: true}
+	}
+
+	if f.Geo, err = parseGeoFilter(c); err != nil {
+		return nil, err
+	}
+	f.IncludeRemote = true
+	if v := c.Query("include_remote"); v != "" {
+		if f.IncludeRemote, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("include_remote must be true or false")
+		}
+	}
+	return f, nil
+}
+
+// queryList splits a comma-separated query parameter, dropping blanks
+func queryList(c queryArgs, name string, lower bool) []string {
+	values := []string{}
+	for _, v := range strings.Split(c.Query(name), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			if lower {
+				v = strings.ToLower(v)
+			}
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func queryInt4(c queryArgs, name string) (pgtype.Int4, error) {
+	v := c.Query(name)
+	if v == "" {
+		return pgtype.Int4{}, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 32)
+	if err != nil || n < 0 {
+		return pgtype.Int4{}, fmt.Errorf("%s must be a non-negative number", name)
+	}
+	return pgtype.Int4{Int32: int32(n), Valid: true}, nil
+}
+
+// parsePostedSince accepts a relative age ("7d", "24h") or a date or
+// RFC 3339 timestamp
+func parsePostedSince(v string, now time.Time) (time.Time, error) {
+	if strings.HasSuffix(v, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(v, "d")); err == nil && days >= 0 {
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+	if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", v); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("posted_since must be like 7d, 24h, 2024-01-31 or an RFC 3339 time")
+}
+
+// Cursors are opaque to clients: the last job's created_at and id
+func encodeJobCursor(createdAt pgtype.Timestamptz, id pgtype.UUID) string {
+	raw := strconv.FormatInt(createdAt.Time.UnixMicro(), 10) + "|" + id.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeJobCursor(cursor string) (pgtype.Timestamptz, pgtype.UUID, error) {
+	var id pgtype.UUID
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return pgtype.Timestamptz{}, id, err
+	}
+	micros, uuid, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return pgtype.Timestamptz{}, id, fmt.Errorf("malformed cursor")
+	}
+	us, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return pgtype.Timestamptz{}, id, err
+	}
+	if err := id.Scan(uuid); err != nil {
+		return pgtype.Timestamptz{}, id, err
+	}
+	return pgtype.Timestamptz{Time: time.UnixMicro(us), Valid: true}, id, nil
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultRadiusKm        = 50
+	maxRadiusKm            = 1000
+	defaultLocationResults = 10
+	maxLocationResults     = 50
+)
+
+// LocationHandler serves the gazetteer loaded by cmd/gazetteer
+type LocationHandler struct {
+	queries *db.Queries
+}
+
+func NewLocationHandler(queries *db.Queries) *LocationHandler {
+	return &LocationHandler{queries: queries}
+}
+
+// SearchLocations suggests places for a location field, matching any name
+// a place goes by: GET /locations?q=banga&limit=
+func (h *LocationHandler) SearchLocations(c *fiber.Ctx) error {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "q is required"})
+	}
+	limit := c.QueryInt("limit", defaultLocationResults)
+	if limit < 1 || limit > maxLocationResults {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("limit must be between 1 and %d", maxLocationResults)})
+	}
+	locations, err := h.queries.SearchLocations(c.Context(), db.SearchLocationsParams{Prefix: q, MaxResults: int32(limit)})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to search locations"})
+	}
+	if locations == nil {
+		return c.JSON([]interface{}{})
+	}
+	return c.JSON(locations)
+}
+
+// geoFilter is a "within radius_km of a place" search filter. The center
+// is either near, a place name, or lat and lng.
+type geoFilter struct {
+	Near      string
+	CenterLat pgtype.Float8
+	CenterLng pgtype.Float8
+	RadiusKm  float64
+	Place     *db.Location // what Near resolved to
+}
+
+var errUnknownPlace = errors.New("unknown place")
+
+// parseGeoFilter reads near or lat and lng, and radius_km (default 50)
+func parseGeoFilter(c queryArgs) (geoFilter, error) {
+	g := geoFilter{Near: strings.TrimSpace(c.Query("near")), RadiusKm: defaultRadiusKm}
+	lat, lng := c.Query("lat"), c.Query("lng")
+	switch {
+	case g.Near != "" && (lat != "" || lng != ""):
+		return g, fmt.Errorf("use either near or lat and lng, not both")
+	case lat != "" || lng != "":
+		la, err := strconv.ParseFloat(lat, 64)
+		if err != nil || la < -90 || la > 90 {
+			return g, fmt.Errorf("lat must be between -90 and 90")
+		}
+		lo, err := strconv.ParseFloat(lng, 64)
+		if err != nil || lo < -180 || lo > 180 {
+			return g, fmt.Errorf("lng must be between -180 and 180")
+		}
+		g.CenterLat = pgtype.Float8{Float64: la, Valid: true}
+		g.CenterLng = pgtype.Float8{Float64: lo, Valid: true}
+	}
+	if v := c.Query("radius_km"); v != "" {
+		if g.Near == "" && !g.CenterLat.Valid {
+			return g, fmt.Errorf("radius_km needs near, or lat and lng")
+		}
+		radius, err := strconv.ParseFloat(v, 64)
+		if err != nil || radius <= 0 || radius > maxRadiusKm {
+			return g, fmt.Errorf("radius_km must be more than 0 and at most %d", maxRadiusKm)
+		}
+		g.RadiusKm = radius
+	}
+	return g, nil
+}
+
+// resolve looks near up in the gazetteer, returning errUnknownPlace when
+// it is not there
+func (g *geoFilter) resolve(ctx context.Context, queries *db.Queries) error {
+	if g.Near == "" {
+		return nil
+	}
+	place, err := queries.ResolveLocation(ctx, g.Near)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errUnknownPlace
+	}
+	if err != nil {
+		return err
+	}
+	g.Place = &place
+	g.CenterLat = pgtype.Float8{Float64: place.Latitude, Valid: true}
+	g.CenterLng = pgtype.Float8{Float64: place.Longitude, Valid: true}
+	return nil
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultNotificationLimit = 50
+	maxNotificationLimit     = 200
+)
+
+// NotificationHandler serves a user's in-app notifications, such as the
+// sweeper's warnings about jobs that are about to be closed
+type NotificationHandler struct {
+	queries *db.Queries
+}
+
+func NewNotificationHandler(queries *db.Queries) *NotificationHandler {
+	return &NotificationHandler{queries: queries}
+}
+
+// ListNotifications returns the newest notifications and the unread count:
+// GET /users/:id/notifications?unread=true&limit=
+func (h *NotificationHandler) ListNotifications(c *fiber.Ctx) error {
+	var userID pgtype.UUID
+	if err := userID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+	}
+	limit := c.QueryInt("limit", defaultNotificationLimit)
+	if limit < 1 || limit > maxNotificationLimit {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("limit must be between 1 and %d", maxNotificationLimit)})
+	}
+
+	notifications, err := h.queries.ListNotifications(c.Context(), db.ListNotificationsParams{
+		UserID:     userID,
+		UnreadOnly: c.QueryBool("unread"),
+		PageLimit:  int32(limit),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch notifications"})
+	}
+	unread, err := h.queries.CountUnreadNotifications(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to count notifications"})
+	}
+
+	var list interface{} = notifications
+	if notifications == nil {
+		list = []interface{}{}
+	}
+	return c.JSON(fiber.Map{"notifications": list, "unread": unread})
+}
+
+// MarkNotificationRead: PUT /users/:id/notifications/:notificationId/read
+func (h *NotificationHandler) MarkNotificationRead(c *fiber.Ctx) error {
+	var userID, notificationID pgtype.UUID
+	if err := userID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+	}
+	if err := notificationID.Scan(c.Params("notificationId")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Notification ID"})
+	}
+
+	n, err := h.queries.MarkNotificationRead(c.Context(), db.MarkNotificationReadParams{ID: notificationID, UserID: userID})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update notification"})
+	}
+	if n == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Notification not found"})
+	}
+	return c.JSON(fiber.Map{"message": "Notification marked as read"})
+}
+
+// MarkAllNotificationsRead: PUT /users/:id/notifications/read
+func (h *NotificationHandler) MarkAllNotificationsRead(c *fiber.Ctx) error {
+	var userID pgtype.UUID
+	if err := userID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+	}
+
+	n, err := h.queries.MarkAllNotificationsRead(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update notifications"})
+	}
+	return c.JSON(fiber.Map{"marked": n})
+}
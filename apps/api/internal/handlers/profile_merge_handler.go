@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/aswinbala005/rizeos/api/internal/services"
+	"github.com/aswinbala005/rizeos/api/internal/workers"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ProfileMergeHandler applies a parsed resume to an existing profile field
+// by field: preview shows current against parsed values, apply writes only
+// the fields the user accepted and records them as AI-sourced.
+type ProfileMergeHandler struct {
+	queries *db.Queries
+	pool    *pgxpool.Pool
+}
+
+func NewProfileMergeHandler(queries *db.Queries, pool *pgxpool.Pool) *ProfileMergeHandler {
+	return &ProfileMergeHandler{queries: queries, pool: pool}
+}
+
+// ResumeMergeRequest names the parsed resume either by the parse job that
+// produced it or inline, as returned by GET /parse-resume/:jobId
+type ResumeMergeRequest struct {
+	JobID  string               `json:"job_id"`
+	Parsed *services.ResumeData `json:"parsed"`
+	// Apply only: the fields to take from the parsed resume
+	Accept []string `json:"accept"`
+}
+
+// resolveParsed loads the resume a request refers to. The returned job ID
+// is invalid when the resume was sent inline.
+func (h *ProfileMergeHandler) resolveParsed(ctx context.Context, req ResumeMergeRequest) (*services.ResumeData, pgtype.UUID, error) {
+	var jobID pgtype.UUID
+	if req.JobID == "" {
+		if req.Parsed == nil {
+			return nil, jobID, &httpError{fiber.StatusBadRequest, "job_id or parsed is required"}
+		}
+		return req.Parsed, jobID, nil
+	}
+
+	if err := jobID.Scan(req.JobID); err != nil {
+		return nil, jobID, &httpError{fiber.StatusBadRequest, "Invalid Job ID"}
+	}
+	job, err := h.queries.GetResumeParseJob(ctx, jobID)
+	if err != nil {
+		return nil, jobID, &httpError{fiber.StatusNotFound, "Parse job not found"}
+	}
+	if job.Status != workers.ParseJobSucceeded {
+		return nil, jobID, &httpError{fiber.StatusConflict, "Parse job has not succeeded (status " + job.Status + ")"}
+	}
+	var data services.ResumeData
+	if err := json.Unmarshal(job.Result, &data); err != nil {
+		return nil, jobID, &httpError{fiber.StatusInternalServerError, "Parse job result is unreadable"}
+	}
+	return &data, jobID, nil
+}
+
+// currentProfile gathers the stored values of every mergeable field
+func currentProfile(ctx context.Context, q *db.Queries, userID pgtype.UUID) (services.ProfileValues, error) {
+	user, err := q.GetUserByID(ctx, userID)
+	if err != nil {
+		return services.ProfileValues{}, err
+	}
+	positions, err := q.ListWorkPositionsByUser(ctx, userID)
+	if err != nil {
+		return services.ProfileValues{}, err
+	}
+	entries, err := q.ListEducationEntriesByUser(ctx, userID)
+	if err != nil {
+		return services.ProfileValues{}, err
+	}
+
+	var projects []services.Project
+	if len(user.Projects) > 0 {
+		_ = json.Unmarshal(user.Projects, &projects)
+	}
+	return services.ProfileValues{
+		FullName:          user.FullName.String,
+		JobRole:           user.JobRole.String,
+		Bio:               user.Bio.String,
+		Skills:            user.Skills.String,
+		Experience:        user.Experience.String,
+		Education:         user.Education.String,
+		Projects:          projects,
+		Phone:             user.Phone.String,
+		ProfessionalEmail: user.ProfessionalEmail.String,
+		WorkHistory:       WorkPositionsFromRows(positions),
+		EducationHistory:  EducationEntriesFromRows(entries),
+	}, nil
+}
+
+func (h *ProfileMergeHandler) fieldSources(ctx context.Context, userID pgtype.UUID) (map[string]string, error) {
+	rows, err := h.queries.ListUserFieldSources(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sources := make(map[string]string, len(rows))
+	for _, row := range rows {
+		sources[row.Field] = row.Source
+	}
+	return sources, nil
+}
+
+// PreviewResumeMerge returns a field-by-field diff of the profile against
+// a parsed resume without changing anything
+func (h *ProfileMergeHandler) PreviewResumeMerge(c *fiber.Ctx) error {
+	var userID pgtype.UUID
+	if err := userID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+	}
+	var req ResumeMergeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	parsed, jobID, err := h.resolveParsed(c.Context(), req)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	current, err := currentProfile(c.Context(), h.queries, userID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+	}
+	sources, err := h.fieldSources(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to load field sources"})
+	}
+
+	return c.JSON(fiber.Map{
+		"parse_job_id": jobID,
+		"fields":       services.DiffProfiles(current, services.ProfileValuesFromResume(parsed), sources),
+	})
+}
+
+// ApplyResumeMerge writes the accepted fields of a parsed resume in one
+// transaction. Accepted empty values clear the field. Accepting
+// work_history or education_history replaces the structured entries and
+// re-derives their summary unless experience/education is accepted too.
+func (h *ProfileMergeHandler) ApplyResumeMerge(c *fiber.Ctx) error {
+	var userID pgtype.UUID
+	if err := userID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+	}
+	var req ResumeMergeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if len(req.Accept) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "accept must list at least one field"})
+	}
+	accepted := map[string]bool{}
+	for _, field := range req.Accept {
+		if !services.IsMergeableProfileField(field) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Unknown field: " + field})
+		}
+		accepted[field] = true
+	}
+
+	parsed, jobID, err := h.resolveParsed(c.Context(), req)
+	if err != nil {
+		return sendError(c, err)
+	}
+	if _, err := h.queries.GetUserByID(c.Context(), userID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+	}
+	values := services.ProfileValuesFromResume(parsed)
+
+	text := func(field, value string) (bool, pgtype.Text) {
+		return accepted[field], pgtype.Text{String: value, Valid: value != ""}
+	}
+	arg := db.ApplyProfileFieldsParams{ID: userID}
+	arg.SetFullName, arg.FullName = text(services.ProfileFieldFullName, values.FullName)
+	arg.SetJobRole, arg.JobRole = text(services.ProfileFieldJobRole, values.JobRole)
+	arg.SetBio, arg.Bio = text(services.ProfileFieldBio, values.Bio)
+	arg.SetSkills, arg.Skills = text(services.ProfileFieldSkills, values.Skills)
+	arg.SetExperience, arg.Experience = text(services.ProfileFieldExperience, values.Experience)
+	arg.SetEducation, arg.Education = text(services.ProfileFieldEducation, values.Education)
+	arg.SetPhone, arg.Phone = text(services.ProfileFieldPhone, values.Phone)
+	arg.SetProfessionalEmail, arg.ProfessionalEmail = text(services.ProfileFieldProfessionalEmail, values.ProfessionalEmail)
+	if accepted[services.ProfileFieldProjects] {
+		arg.SetProjects = true
+		arg.Projects, _ = json.Marshal(values.Field(services.ProfileFieldProjects))
+	}
+
+	var user db.ApplyProfileFieldsRow
+	var positions []db.WorkPosition
+	var entries []db.EducationEntry
+	err = inTx(c.Context(), h.pool, h.queries, func(q *db.Queries) error {
+		var err error
+		// History first, so an explicitly accepted summary wins over the
+		// derived one
+		if accepted[services.ProfileFieldWorkHistory] {
+			params := make([]db.CreateWorkPositionParams, 0, len(values.WorkHistory))
+			for _, p := range values.WorkHistory {
+				if arg, ok := parsedWorkPosition(userID, p); ok {
+					params = append(params, arg)
+				}
+			}
+			if _, err = replaceWorkPositions(c.Context(), q, userID, params); err != nil {
+				return err
+			}
+		}
+		if accepted[services.ProfileFieldEducationHistory] {
+			params := make([]db.CreateEducationEntryParams, 0, len(values.EducationHistory))
+			for _, e := range values.EducationHistory {
+				if arg, ok := parsedEducationEntry(userID, e); ok {
+					params = append(params, arg)
+				}
+			}
+			if _, err = replaceEducationEntries(c.Context(), q, userID, params); err != nil {
+				return err
+			}
+		}
+
+		if user, err = q.ApplyProfileFields(c.Context(), arg); err != nil {
+			return err
+		}
+		for field := range accepted {
+			if err := markFieldSource(c.Context(), q, userID, field, services.FieldSourceAI, jobID); err != nil {
+				return err
+			}
+		}
+
+		if positions, err = q.ListWorkPositionsByUser(c.Context(), userID); err != nil {
+			return err
+		}
+		entries, err = q.ListEducationEntriesByUser(c.Context(), userID)
+		return err
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to apply resume: " + err.Error()})
+	}
+
+	applied := make([]string, 0, len(accepted))
+	for _, field := range services.MergeableProfileFields {
+		if accepted[field] {
+			applied = append(applied, field)
+		}
+	}
+	if positions == nil {
+		positions = []db.WorkPosition{}
+	}
+	if entries == nil {
+		entries = []db.EducationEntry{}
+	}
+	return c.JSON(fiber.Map{
+		"applied":           applied,
+		"user":              user,
+		"work_positions":    positions,
+		"education_entries": entries,
+	})
+}
+
+// parsedWorkPosition converts a parsed position for storage. Dates the
+// parser got wrong are dropped rather than failing the whole merge.
+func parsedWorkPosition(userID pgtype.UUID, p services.WorkPosition) (db.CreateWorkPositionParams, bool) {
+	if p.Company == "" && p.Title == "" {
+		return db.CreateWorkPositionParams{}, false
+	}
+	start, _ := historyDate(p.Start)
+	end, _ := historyDate(p.End)
+	if start.Valid && end.Valid && end.Time.Before(start.Time) {
+		end = pgtype.Date{}
+	}
+	return db.CreateWorkPositionParams{
+		UserID:      userID,
+		Company:     p.Company,
+		Title:       p.Title,
+		StartDate:   start,
+		EndDate:     end,
+		Description: pgtype.Text{String: p.Description, Valid: p.Description != ""},
+	}, true
+}
+
+// parsedEducationEntry converts a parsed education entry for storage
+func parsedEducationEntry(userID pgtype.UUID, e services.EducationEntry) (db.CreateEducationEntryParams, bool) {
+	if e.Institution == "" {
+		return db.CreateEducationEntryParams{}, false
+	}
+	return educationParams(userID, EducationEntryRequest{
+		Institution:    e.Institution,
+		Degree:         e.Degree,
+		FieldOfStudy:   e.FieldOfStudy,
+		GraduationYear: int32(e.GraduationYear),
+	}), true
+}
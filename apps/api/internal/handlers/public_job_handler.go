@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/aswinbala005/rizeos/api/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const publicCacheControl = "public, max-age=300"
+
+// PublicJobHandler serves open jobs to anyone, including search engine
+// crawlers. Nothing here needs a login or exposes drafts, closed jobs or
+// recruiter contact details.
+type PublicJobHandler struct {
+	queries *db.Queries
+	siteURL string
+	feeds   *feedCache
+}
+
+// NewPublicJobHandler takes the public site's base URL, under which each
+// job's page is /jobs/:id. When it is empty, job URLs point at this API's
+// own /public/jobs/:id instead.
+func NewPublicJobHandler(queries *db.Queries, siteURL string) *PublicJobHandler {
+	return &PublicJobHandler{queries: queries, siteURL: strings.TrimRight(siteURL, "/"), feeds: newFeedCache()}
+}
+
+// GetPublicJob returns an open job as schema.org JobPosting JSON-LD:
+// GET /public/jobs/:id
+func (h *PublicJobHandler) GetPublicJob(c *fiber.Ctx) error {
+	var jobID pgtype.UUID
+	if err := jobID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Job ID"})
+	}
+
+	job, err := h.queries.GetPublicJob(c.Context(), jobID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Job not found"})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch job"})
+	}
+
+	body, err := json.Marshal(services.NewJobPosting(job, h.jobURL(c, job.ID.String())))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to encode job"})
+	}
+	c.Set(fiber.HeaderContentType, "application/ld+json")
+	c.Set(fiber.HeaderCacheControl, publicCacheControl)
+	c.Set(fiber.HeaderLastModified, job.UpdatedAt.Time.UTC().Format(http.TimeFormat))
+	return c.Send(body)
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+// Sitemap lists every open job's page for crawlers: GET /public/sitemap.xml
+func (h *PublicJobHandler) Sitemap(c *fiber.Ctx) error {
+	jobs, err := h.queries.ListSitemapJobs(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch jobs"})
+	}
+
+	set := sitemapURLSet{XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9", URLs: make([]sitemapURL, 0, len(jobs))}
+	for _, job := range jobs {
+		set.URLs = append(set.URLs, sitemapURL{
+			Loc:     h.jobURL(c, job.ID.String()),
+			LastMod: job.UpdatedAt.Time.UTC().Format(time.RFC3339),
+		})
+	}
+	body, err := xml.Marshal(set)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to encode sitemap"})
+	}
+	c.Set(fiber.HeaderContentType, "application/xml; charset=utf-8")
+	c.Set(fiber.HeaderCacheControl, publicCacheControl)
+	return c.Send(append([]byte(xml.Header), body...))
+}
+
+func (h *PublicJobHandler) jobURL(c *fiber.Ctx, id string) string {
+	if h.siteURL != "" {
+		return h.siteURL + "/jobs/" + id
+	}
+	return c.BaseURL() + "/api/v1/public/jobs/" + id
+}
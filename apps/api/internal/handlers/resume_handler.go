@@ -1,20 +1,76 @@
 package handlers
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aswinbala005/rizeos/api/internal/db"
 	"github.com/aswinbala005/rizeos/api/internal/services"
+	"github.com/aswinbala005/rizeos/api/internal/workers"
 	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
-type ResumeHandler struct{}
+// How long an event stream stays open before the client has to reconnect
+const parseEventsTimeout = 3 * time.Minute
 
-func NewResumeHandler() *ResumeHandler {
-	return &ResumeHandler{}
+type ResumeHandler struct {
+	queries       *db.Queries
+	parser        *workers.ResumeParser
+	cache         *services.ResumeCache
+	maxAttempts   int32
+	defaultParser string
+}
+
+func NewResumeHandler(queries *db.Queries, parser *workers.ResumeParser, cache *services.ResumeCache, maxAttempts int, defaultParser string) *ResumeHandler {
+	return &ResumeHandler{
+		queries:       queries,
+		parser:        parser,
+		cache:         cache,
+		maxAttempts:   int32(maxAttempts),
+		defaultParser: defaultParser,
+	}
 }
 
 type ParseRequest struct {
 	Url string `json:"url"`
+	// Optional: "auto", "llm" or "heuristic"; defaults to RESUME_PARSER
+	Parser string `json:"parser"`
+}
+
+// ParseJobResponse is the public view of a resume_parse_jobs row
+type ParseJobResponse struct {
+	JobID       string             `json:"job_id"`
+	Status      string             `json:"status"`
+	Parser      string             `json:"parser"`
+	Attempts    int32              `json:"attempts"`
+	Result      json.RawMessage    `json:"result,omitempty"`
+	Error       string             `json:"error,omitempty"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	CompletedAt pgtype.Timestamptz `json:"completed_at"`
+}
+
+func newParseJobResponse(job db.ResumeParseJob) ParseJobResponse {
+	return ParseJobResponse{
+		JobID:       job.ID.String(),
+		Status:      job.Status,
+		Parser:      job.Parser,
+		Attempts:    job.Attempts,
+		Result:      job.Result,
+		Error:       job.LastError.String,
+		CreatedAt:   job.CreatedAt,
+		CompletedAt: job.CompletedAt,
+	}
 }
 
+func isTerminalParseStatus(status string) bool {
+	return status == workers.ParseJobSucceeded || status == workers.ParseJobFailed
+}
+
+// ParseResume enqueues a resume for background parsing and returns the job ID
 func (h *ResumeHandler) ParseResume(c *fiber.Ctx) error {
 	var req ParseRequest
 	if err := c.BodyParser(&req); err != nil {
@@ -25,17 +81,107 @@ func (h *ResumeHandler) ParseResume(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "URL is required"})
 	}
 
-	// 1. Extract Text
-	text, err := services.ExtractTextFromPDF(req.Url)
+	if req.Parser == "" {
+		req.Parser = h.defaultParser
+	}
+	if !services.IsValidResumeParser(req.Parser) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "parser must be one of auto, llm, heuristic"})
+	}
+
+	job, err := h.queries.CreateResumeParseJob(c.Context(), db.CreateResumeParseJobParams{
+		SourceUrl:   req.Url,
+		MaxAttempts: h.maxAttempts,
+		Parser:      req.Parser,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to queue resume: " + err.Error()})
+	}
+	h.parser.Notify()
+
+	return c.Status(fiber.StatusAccepted).JSON(newParseJobResponse(job))
+}
+
+// GetParseJob returns the current status of a parse job, including the
+// parsed resume once it has succeeded
+func (h *ResumeHandler) GetParseJob(c *fiber.Ctx) error {
+	var uuid pgtype.UUID
+	if err := uuid.Scan(c.Params("jobId")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Job ID"})
+	}
+
+	job, err := h.queries.GetResumeParseJob(c.Context(), uuid)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to read PDF: " + err.Error()})
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Parse job not found"})
 	}
 
-	// 2. AI Parse
-	data, err := services.ParseResumeWithAI(text)
+	return c.JSON(newParseJobResponse(job))
+}
+
+// StreamParseJob streams the job as Server-Sent Events: one "status" event
+// immediately and on every change, closing after the job completes
+func (h *ResumeHandler) StreamParseJob(c *fiber.Ctx) error {
+	var uuid pgtype.UUID
+	if err := uuid.Scan(c.Params("jobId")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Job ID"})
+	}
+
+	job, err := h.queries.GetResumeParseJob(c.Context(), uuid)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "AI Parsing failed: " + err.Error()})
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Parse job not found"})
 	}
 
-	return c.JSON(data)
-}
\ No newline at end of file
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+
+	// Subscribe before handing off to the stream writer so a completion that
+	// lands in between is not missed
+	changed, unsubscribe := h.parser.Events().Subscribe(job.ID.String())
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer unsubscribe()
+
+		ctx, cancel := context.WithTimeout(context.Background(), parseEventsTimeout)
+		defer cancel()
+
+		// The event hub only sees jobs finished by this instance, so re-read
+		// the row periodically as well
+		ticker := time.NewTicker(2 * time.Second)
+		defer ticker.Stop()
+
+		lastStatus := ""
+		for {
+			if job.Status != lastStatus {
+				payload, _ := json.Marshal(newParseJobResponse(job))
+				fmt.Fprintf(w, "event: status\ndata: %s\n\n", payload)
+				if err := w.Flush(); err != nil {
+					return // client went away
+				}
+				lastStatus = job.Status
+			}
+			if isTerminalParseStatus(job.Status) {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-changed:
+			case <-ticker.C:
+			}
+
+			latest, err := h.queries.GetResumeParseJob(ctx, job.ID)
+			if err != nil {
+				return
+			}
+			job = latest
+		}
+	})
+
+	return nil
+}
+
+// GetCacheStats exposes the resume cache hit/miss counters for monitoring
+func (h *ResumeHandler) GetCacheStats(c *fiber.Ctx) error {
+	return c.JSON(h.cache.Stats())
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/aswinbala005/rizeos/api/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ScreeningHandler manages the screening questions attached to a job and
+// exposes the graded answers of an application
+type ScreeningHandler struct {
+	queries *db.Queries
+	pool    *pgxpool.Pool
+}
+
+func NewScreeningHandler(queries *db.Queries, pool *pgxpool.Pool) *ScreeningHandler {
+	return &ScreeningHandler{queries: queries, pool: pool}
+}
+
+func screeningQuestionFromRow(row db.JobScreeningQuestion) services.ScreeningQuestion {
+	return services.ScreeningQuestion{
+		ID:             row.ID.String(),
+		Kind:           row.Kind,
+		Prompt:         row.Prompt,
+		Options:        row.Options,
+		Required:       row.Required,
+		KnockoutAnswer: row.KnockoutAnswer.String,
+		Rubric:         row.Rubric.String,
+	}
+}
+
+// GetJobQuestions lists a job's questions in order. Knockout answers and
+// rubrics are recruiter-only: they are left out unless ?view=recruiter
+// comes with a user_id on the job's recruiting team.
+func (h *ScreeningHandler) GetJobQuestions(c *fiber.Ctx) error {
+	var jobID pgtype.UUID
+	if err := jobID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Job ID"})
+	}
+	recruiterView := false
+	var userID pgtype.UUID
+	if c.Query("view") == "recruiter" && userID.Scan(c.Query("user_id")) == nil {
+		onTeam, err := onJobTeam(c.Context(), h.queries, jobID, userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to check recruiting team"})
+		}
+		recruiterView = onTeam
+	}
+
+	rows, err := h.queries.ListScreeningQuestionsByJob(c.Context(), jobID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch questions"})
+	}
+
+	questions := make([]services.ScreeningQuestion, 0, len(rows))
+	for _, row := range rows {
+		q := screeningQuestionFromRow(row)
+		if !recruiterView {
+			q.KnockoutAnswer, q.Rubric = "", ""
+		}
+		questions = append(questions, q)
+	}
+	return c.JSON(questions)
+}
+
+// PutJobQuestions replaces a job's questions with the list in the body.
+// Questions sent with their id are updated in place, so answers already
+// given keep their question and rubric; questions left out are deleted and
+// their answers keep a copy of the question text.
+func (h *ScreeningHandler) PutJobQuestions(c *fiber.Ctx) error {
+	var jobID pgtype.UUID
+	if err := jobID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Job ID"})
+	}
+
+	var req []services.ScreeningQuestion
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if len(req) > services.MaxScreeningQuestions {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("A job can have at most %d questions", services.MaxScreeningQuestions)})
+	}
+	for i := range req {
+		if err := req[i].Normalize(); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("question %d: %v", i+1, err)})
+		}
+	}
+
+	if _, err := h.queries.GetJobByID(c.Context(), jobID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Job not found"})
+	}
+
+	existing, err := h.queries.ListScreeningQuestionsByJob(c.Context(), jobID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch questions"})
+	}
+	known := make(map[string]bool, len(existing))
+	for _, row := range existing {
+		known[row.ID.String()] = true
+	}
+	ids := make([]pgtype.UUID, len(req))
+	keep := make([]pgtype.UUID, 0, len(req))
+	for i, question := range req {
+		if question.ID == "" {
+			continue
+		}
+		if err := ids[i].Scan(question.ID); err != nil || !known[ids[i].String()] {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("question %d: %q is not one of this job's questions", i+1, question.ID)})
+		}
+		delete(known, ids[i].String()) // a question can only be sent once
+		keep = append(keep, ids[i])
+	}
+
+	questions := make([]services.ScreeningQuestion, 0, len(req))
+	err = inTx(c.Context(), h.pool, h.queries, func(q *db.Queries) error {
+		if err := q.DeleteScreeningQuestionsExcept(c.Context(), db.DeleteScreeningQuestionsExceptParams{
+			JobID:   jobID,
+			KeepIds: keep,
+		}); err != nil {
+			return err
+		}
+		for i, question := range req {
+			knockout := pgtype.Text{String: question.KnockoutAnswer, Valid: question.KnockoutAnswer != ""}
+			rubric := pgtype.Text{String: question.Rubric, Valid: question.Rubric != ""}
+			var row db.JobScreeningQuestion
+			var err error
+			if ids[i].Valid {
+				row, err = q.UpdateScreeningQuestion(c.Context(), db.UpdateScreeningQuestionParams{
+					ID:             ids[i],
+					JobID:          jobID,
+					Position:       int32(i),
+					Kind:           question.Kind,
+					Prompt:         question.Prompt,
+					Options:        question.Options,
+					Required:       question.Required,
+					KnockoutAnswer: knockout,
+					Rubric:         rubric,
+				})
+			} else {
+				row, err = q.CreateScreeningQuestion(c.Context(), db.CreateScreeningQuestionParams{
+					JobID:          jobID,
+					Position:       int32(i),
+					Kind:           question.Kind,
+					Prompt:         question.Prompt,
+					Options:        question.Options,
+					Required:       question.Required,
+					KnockoutAnswer: knockout,
+					Rubric:         rubric,
+				})
+			}
+			if err != nil {
+				return err
+			}
+			questions = append(questions, screeningQuestionFromRow(row))
+		}
+		return nil
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to save questions: " + err.Error()})
+	}
+	return c.JSON(questions)
+}
+
+// GetApplicationAnswers returns an application's answers with their grades
+func (h *ScreeningHandler) GetApplicationAnswers(c *fiber.Ctx) error {
+	var appID pgtype.UUID
+	if err := appID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Application ID"})
+	}
+
+	answers, err := h.queries.ListApplicationAnswers(c.Context(), appID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch answers"})
+	}
+	if answers == nil {
+		return c.JSON([]interface{}{})
+	}
+	return c.JSON(answers)
+}
@@ -1,11 +1,16 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/aswinbala005/rizeos/api/internal/services"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -68,13 +73,13 @@ func (h *UserHandler) GetUser(c *fiber.Ctx) error {
 	if len(identifier) == 42 && identifier[0] == '0' && identifier[1] == 'x' {
 		walletUser, walletErr := h.queries.GetUserByWallet(c.Context(), pgtype.Text{String: identifier, Valid: true})
 		if walletErr == nil {
-			return c.JSON(fiber.Map{"exists": true, "user": walletUser})
+			return c.JSON(h.userResponse(c, walletUser.ID, walletUser))
 		}
 		err = walletErr
 	} else {
 		emailUser, emailErr := h.queries.GetUserByEmail(c.Context(), pgtype.Text{String: identifier, Valid: true})
 		if emailErr == nil {
-			return c.JSON(fiber.Map{"exists": true, "user": emailUser})
+			return c.JSON(h.userResponse(c, emailUser.ID, emailUser))
 		}
 		err = emailErr
 	}
@@ -90,6 +95,26 @@ func (h *UserHandler) GetUser(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"exists": false})
 }
 
+// userResponse adds the user's structured work history and education to
+// the GetUser payload. They are best effort: a failed lookup leaves the
+// arrays empty rather than failing the whole request.
+func (h *UserHandler) userResponse(c *fiber.Ctx, userID pgtype.UUID, user interface{}) fiber.Map {
+	positions, err := h.queries.ListWorkPositionsByUser(c.Context(), userID)
+	if err != nil || positions == nil {
+		positions = []db.WorkPosition{}
+	}
+	entries, err := h.queries.ListEducationEntriesByUser(c.Context(), userID)
+	if err != nil || entries == nil {
+		entries = []db.EducationEntry{}
+	}
+	return fiber.Map{
+		"exists":            true,
+		"user":              user,
+		"work_positions":    positions,
+		"education_entries": entries,
+	}
+}
+
 // --- CREATE USER ---
 type CreateUserRequest struct {
 	WalletAddress string `json:"wallet_address" validate:"required"`
@@ -141,6 +166,102 @@ func (h *UserHandler) SearchCandidates(c *fiber.Ctx) error {
 	return c.JSON(users)
 }
 
+const (
+	defaultCandidatePageSize = 20
+	maxCandidatePageSize     = 50
+)
+
+// QueryCandidates is Tracer's natural-language search. The q text is
+// compiled into a structured filter (role, skills, minimum experience,
+// location); any of role, skills (comma-separated), min_experience and
+// location given explicitly replace what was parsed, so the UI can send
+// back an edited filter. near (a place name) or lat and lng, with
+// radius_km, keep candidates living within that distance. Results are
+// ranked and paginated with page and page_size, and the filter that ran is
+// returned alongside them.
+func (h *UserHandler) QueryCandidates(c *fiber.Ctx) error {
+	filter := services.ParseCandidateQuery(c.Query("q"))
+
+	args := c.Context().QueryArgs()
+	if args.Has("role") {
+		filter.Role = strings.TrimSpace(c.Query("role"))
+	}
+	if args.Has("skills") {
+		filter.Skills = []string{}
+		for _, skill := range strings.Split(c.Query("skills"), ",") {
+			if skill = strings.TrimSpace(skill); skill != "" {
+				filter.Skills = append(filter.Skills, skill)
+			}
+		}
+	}
+	if args.Has("min_experience") {
+		years, err := strconv.Atoi(c.Query("min_experience"))
+		if err != nil || years < 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "min_experience must be a non-negative number"})
+		}
+		filter.MinExperience = years
+	}
+	if args.Has("location") {
+		filter.Location = strings.TrimSpace(c.Query("location"))
+	}
+
+	geo, err := parseGeoFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	err = geo.resolve(c.Context(), h.queries)
+	if errors.Is(err, errUnknownPlace) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Unknown place for near: " + geo.Near})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to look up location"})
+	}
+
+	page := c.QueryInt("page", 1)
+	pageSize := c.QueryInt("page_size", defaultCandidatePageSize)
+	if page < 1 || pageSize < 1 || pageSize > maxCandidatePageSize {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("page must be at least 1 and page_size between 1 and %d", maxCandidatePageSize)})
+	}
+
+	patterns := make([]string, 0, len(filter.Skills))
+	for _, skill := range filter.Skills {
+		patterns = append(patterns, services.SkillPattern(skill))
+	}
+	rows, err := h.queries.FilterCandidates(c.Context(), db.FilterCandidatesParams{
+		Terms:         filter.SearchTerms(),
+		JobRole:       pgtype.Text{String: filter.Role, Valid: filter.Role != ""},
+		SkillPatterns: patterns,
+		MinExperience: int32(filter.MinExperience),
+		Location:      pgtype.Text{String: filter.Location, Valid: filter.Location != ""},
+		CenterLat:     geo.CenterLat,
+		CenterLng:     geo.CenterLng,
+		RadiusKm:      geo.RadiusKm,
+		PageLimit:     int32(pageSize),
+		PageOffset:    int32((page - 1) * pageSize),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to search candidates"})
+	}
+
+	total := int64(0)
+	if len(rows) > 0 {
+		total = rows[0].Total
+	}
+	results := interface{}(rows)
+	if rows == nil {
+		results = []interface{}{}
+	}
+	return c.JSON(fiber.Map{
+		"query":     c.Query("q"),
+		"filter":    filter,
+		"near":      geo.Place,
+		"results":   results,
+		"page":      page,
+		"page_size": pageSize,
+		"total":     total,
+	})
+}
+
 // --- UPDATE USER ---
 type Project struct {
 	Title   string `json:"title"`
@@ -161,6 +282,7 @@ type UpdateUserRequest struct {
 	OrganizationName  string    `json:"organization_name"`
 	OrganizationLocation string `json:"organization_location"`
 	OrganizationBio   string    `json:"organization_bio"`
+	Location          string    `json:"location"`
 }
 
 func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
@@ -199,6 +321,7 @@ func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update user: " + err.Error()})
 		}
+		h.markUserWritten(c.Context(), uuid, req)
 		return c.JSON(updatedUser)
 	} else {
 		// --- UPDATE SEEKER ---
@@ -213,11 +336,43 @@ func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
 			Education:         pgtype.Text{String: req.Education, Valid: req.Education != ""},
 			JobRole:           pgtype.Text{String: req.JobRole, Valid: req.JobRole != ""},
 			ProfessionalEmail: pgtype.Text{String: req.ProfessionalEmail, Valid: req.ProfessionalEmail != ""}, // <-- NEW
+			Location:          pgtype.Text{String: req.Location, Valid: req.Location != ""},
 		}
 		updatedUser, err := h.queries.UpdateSeekerProfile(c.Context(), arg)
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update user: " + err.Error()})
 		}
+		h.markUserWritten(c.Context(), uuid, req)
 		return c.JSON(updatedUser)
 	}
-}
\ No newline at end of file
+}
+
+// markUserWritten records the fields sent to UpdateUser as user-written, so
+// a later resume merge can show which values the user chose themselves.
+// Failures are logged; the profile update itself already succeeded.
+func (h *UserHandler) markUserWritten(ctx context.Context, userID pgtype.UUID, req UpdateUserRequest) {
+	fields := map[string]string{
+		services.ProfileFieldFullName:          req.FullName,
+		services.ProfileFieldProfessionalEmail: req.ProfessionalEmail,
+		services.ProfileFieldJobRole:           req.JobRole,
+		services.ProfileFieldBio:               req.Bio,
+		services.ProfileFieldSkills:            req.Skills,
+		services.ProfileFieldExperience:        req.Experience,
+		services.ProfileFieldEducation:         req.Education,
+		services.ProfileFieldPhone:             req.Phone,
+		"organization_name":                    req.OrganizationName,
+		"organization_location":                req.OrganizationLocation,
+		"organization_bio":                     req.OrganizationBio,
+	}
+	if req.Projects != nil {
+		fields[services.ProfileFieldProjects] = "set"
+	}
+	for field, value := range fields {
+		if value == "" {
+			continue
+		}
+		if err := markFieldSource(ctx, h.queries, userID, field, services.FieldSourceUser, pgtype.UUID{}); err != nil {
+			fmt.Printf("Warning: could not record source of %s: %v\n", field, err)
+		}
+	}
+}
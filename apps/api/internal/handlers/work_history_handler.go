@@ -0,0 +1,559 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/aswinbala005/rizeos/api/internal/services"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// WorkHistoryHandler manages a user's structured work positions and
+// education entries. Every change also rewrites the users.experience and
+// users.education summaries in the same transaction, so older clients that
+// only read those strings stay in step.
+type WorkHistoryHandler struct {
+	queries  *db.Queries
+	pool     *pgxpool.Pool
+	validate *validator.Validate
+}
+
+func NewWorkHistoryHandler(queries *db.Queries, pool *pgxpool.Pool) *WorkHistoryHandler {
+	return &WorkHistoryHandler{
+		queries:  queries,
+		pool:     pool,
+		validate: validator.New(),
+	}
+}
+
+var errInvalidDateRange = errors.New("end_date must not be before start_date")
+
+type WorkPositionRequest struct {
+	Company     string `json:"company" validate:"required,max=200"`
+	Title       string `json:"title" validate:"required,max=200"`
+	StartDate   string `json:"start_date"` // "YYYY-MM" or "YYYY-MM-DD"
+	EndDate     string `json:"end_date"`   // empty for a current position
+	Description string `json:"description" validate:"max=5000"`
+}
+
+type EducationEntryRequest struct {
+	Institution    string `json:"institution" validate:"required,max=200"`
+	Degree         string `json:"degree" validate:"max=200"`
+	FieldOfStudy   string `json:"field_of_study" validate:"max=200"`
+	GraduationYear int32  `json:"graduation_year" validate:"omitempty,min=1950,max=2100"`
+}
+
+// historyDate converts an optional request date to a DATE value
+func historyDate(s string) (pgtype.Date, error) {
+	if s == "" {
+		return pgtype.Date{}, nil
+	}
+	t, ok := services.ParseHistoryDate(s)
+	if !ok {
+		return pgtype.Date{}, errors.New("dates must be YYYY-MM or YYYY-MM-DD")
+	}
+	return pgtype.Date{Time: t, Valid: true}, nil
+}
+
+func (r WorkPositionRequest) dates() (start, end pgtype.Date, err error) {
+	if start, err = historyDate(r.StartDate); err != nil {
+		return
+	}
+	if end, err = historyDate(r.EndDate); err != nil {
+		return
+	}
+	if start.Valid && end.Valid && end.Time.Before(start.Time) {
+		err = errInvalidDateRange
+	}
+	return
+}
+
+// WorkPositionsFromRows converts stored positions to the resume schema
+func WorkPositionsFromRows(rows []db.WorkPosition) []services.WorkPosition {
+	positions := make([]services.WorkPosition, 0, len(rows))
+	for _, row := range rows {
+		p := services.WorkPosition{Company: row.Company, Title: row.Title, Description: row.Description.String}
+		if row.StartDate.Valid {
+			p.Start = services.FormatHistoryDate(row.StartDate.Time)
+		}
+		if row.EndDate.Valid {
+			p.End = services.FormatHistoryDate(row.EndDate.Time)
+		}
+		positions = append(positions, p)
+	}
+	return positions
+}
+
+// EducationEntriesFromRows converts stored entries to the resume schema
+func EducationEntriesFromRows(rows []db.EducationEntry) []services.EducationEntry {
+	entries := make([]services.EducationEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, services.EducationEntry{
+			Institution:    row.Institution,
+			Degree:         row.Degree.String,
+			FieldOfStudy:   row.FieldOfStudy.String,
+			GraduationYear: int(row.GraduationYear.Int32),
+		})
+	}
+	return entries
+}
+
+// inTx runs fn in a transaction and commits if it returns nil
+func inTx(ctx context.Context, pool *pgxpool.Pool, queries *db.Queries, fn func(q *db.Queries) error) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(queries.WithTx(tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// markFieldSource records where a profile field's current value came from;
+// parseJobID is only set for values accepted from a resume parse
+func markFieldSource(ctx context.Context, q *db.Queries, userID pgtype.UUID, field, source string, parseJobID pgtype.UUID) error {
+	return q.UpsertUserFieldSource(ctx, db.UpsertUserFieldSourceParams{
+		UserID:     userID,
+		Field:      field,
+		Source:     source,
+		ParseJobID: parseJobID,
+	})
+}
+
+// replaceWorkPositions swaps all of a user's positions for params and
+// refreshes the experience summary
+func replaceWorkPositions(ctx context.Context, q *db.Queries, userID pgtype.UUID, params []db.CreateWorkPositionParams) ([]db.WorkPosition, error) {
+	if err := q.DeleteWorkPositionsByUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	positions := []db.WorkPosition{}
+	for _, arg := range params {
+		position, err := q.CreateWorkPosition(ctx, arg)
+		if err != nil {
+			return nil, err
+		}
+		positions = append(positions, position)
+	}
+	return positions, syncExperienceSummary(ctx, q, userID)
+}
+
+// replaceEducationEntries swaps all of a user's education entries for
+// params and refreshes the education summary
+func replaceEducationEntries(ctx context.Context, q *db.Queries, userID pgtype.UUID, params []db.CreateEducationEntryParams) ([]db.EducationEntry, error) {
+	if err := q.DeleteEducationEntriesByUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	entries := []db.EducationEntry{}
+	for _, arg := range params {
+		entry, err := q.CreateEducationEntry(ctx, arg)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, syncEducationSummary(ctx, q, userID)
+}
+
+// userEditedWorkHistory refreshes the summary after a manual edit and
+// records the history as user-written
+func userEditedWorkHistory(ctx context.Context, q *db.Queries, userID pgtype.UUID) error {
+	if err := syncExperienceSummary(ctx, q, userID); err != nil {
+		return err
+	}
+	return markFieldSource(ctx, q, userID, services.ProfileFieldWorkHistory, services.FieldSourceUser, pgtype.UUID{})
+}
+
+// userEditedEducation is userEditedWorkHistory for education entries
+func userEditedEducation(ctx context.Context, q *db.Queries, userID pgtype.UUID) error {
+	if err := syncEducationSummary(ctx, q, userID); err != nil {
+		return err
+	}
+	return markFieldSource(ctx, q, userID, services.ProfileFieldEducationHistory, services.FieldSourceUser, pgtype.UUID{})
+}
+
+// syncExperienceSummary rewrites users.experience from the user's positions
+func syncExperienceSummary(ctx context.Context, q *db.Queries, userID pgtype.UUID) error {
+	rows, err := q.ListWorkPositionsByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	summary := services.SummarizeExperience(WorkPositionsFromRows(rows), time.Now())
+	return q.SetUserExperienceSummary(ctx, db.SetUserExperienceSummaryParams{
+		ID:         userID,
+		Experience: pgtype.Text{String: summary, Valid: summary != ""},
+	})
+}
+
+// syncEducationSummary rewrites users.education from the user's entries
+func syncEducationSummary(ctx context.Context, q *db.Queries, userID pgtype.UUID) error {
+	rows, err := q.ListEducationEntriesByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	summary := services.SummarizeEducation(EducationEntriesFromRows(rows))
+	return q.SetUserEducationSummary(ctx, db.SetUserEducationSummaryParams{
+		ID:        userID,
+		Education: pgtype.Text{String: summary, Valid: summary != ""},
+	})
+}
+
+// --- WORK POSITIONS ---
+
+func (h *WorkHistoryHandler) ListWorkPositions(c *fiber.Ctx) error {
+	var userID pgtype.UUID
+	if err := userID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+	}
+
+	positions, err := h.queries.ListWorkPositionsByUser(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch work positions"})
+	}
+	if positions == nil {
+		return c.JSON([]interface{}{})
+	}
+	return c.JSON(positions)
+}
+
+func (h *WorkHistoryHandler) CreateWorkPosition(c *fiber.Ctx) error {
+	var userID pgtype.UUID
+	if err := userID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+	}
+	if _, err := h.queries.GetUserByID(c.Context(), userID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+	}
+
+	var req WorkPositionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	start, end, err := req.dates()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var position db.WorkPosition
+	err = inTx(c.Context(), h.pool, h.queries, func(q *db.Queries) error {
+		var err error
+		position, err = q.CreateWorkPosition(c.Context(), db.CreateWorkPositionParams{
+			UserID:      userID,
+			Company:     req.Company,
+			Title:       req.Title,
+			StartDate:   start,
+			EndDate:     end,
+			Description: pgtype.Text{String: req.Description, Valid: req.Description != ""},
+		})
+		if err != nil {
+			return err
+		}
+		return userEditedWorkHistory(c.Context(), q, userID)
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create work position: " + err.Error()})
+	}
+	return c.Status(fiber.StatusCreated).JSON(position)
+}
+
+// ReplaceWorkPositions swaps the user's whole work history for the list in
+// the body, e.g. when saving the result of a resume parse
+func (h *WorkHistoryHandler) ReplaceWorkPositions(c *fiber.Ctx) error {
+	var userID pgtype.UUID
+	if err := userID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+	}
+	if _, err := h.queries.GetUserByID(c.Context(), userID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+	}
+
+	var req []WorkPositionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	params := make([]db.CreateWorkPositionParams, 0, len(req))
+	for _, r := range req {
+		if err := h.validate.Struct(r); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		start, end, err := r.dates()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		params = append(params, db.CreateWorkPositionParams{
+			UserID:      userID,
+			Company:     r.Company,
+			Title:       r.Title,
+			StartDate:   start,
+			EndDate:     end,
+			Description: pgtype.Text{String: r.Description, Valid: r.Description != ""},
+		})
+	}
+
+	var positions []db.WorkPosition
+	err := inTx(c.Context(), h.pool, h.queries, func(q *db.Queries) error {
+		var err error
+		if positions, err = replaceWorkPositions(c.Context(), q, userID, params); err != nil {
+			return err
+		}
+		return markFieldSource(c.Context(), q, userID, services.ProfileFieldWorkHistory, services.FieldSourceUser, pgtype.UUID{})
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to save work positions: " + err.Error()})
+	}
+	return c.JSON(positions)
+}
+
+func (h *WorkHistoryHandler) UpdateWorkPosition(c *fiber.Ctx) error {
+	var userID, positionID pgtype.UUID
+	if err := userID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+	}
+	if err := positionID.Scan(c.Params("positionId")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Position ID"})
+	}
+
+	var req WorkPositionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	start, end, err := req.dates()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var position db.WorkPosition
+	err = inTx(c.Context(), h.pool, h.queries, func(q *db.Queries) error {
+		var err error
+		position, err = q.UpdateWorkPosition(c.Context(), db.UpdateWorkPositionParams{
+			ID:          positionID,
+			UserID:      userID,
+			Company:     req.Company,
+			Title:       req.Title,
+			StartDate:   start,
+			EndDate:     end,
+			Description: pgtype.Text{String: req.Description, Valid: req.Description != ""},
+		})
+		if err != nil {
+			return err
+		}
+		return userEditedWorkHistory(c.Context(), q, userID)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Work position not found"})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update work position: " + err.Error()})
+	}
+	return c.JSON(position)
+}
+
+func (h *WorkHistoryHandler) DeleteWorkPosition(c *fiber.Ctx) error {
+	var userID, positionID pgtype.UUID
+	if err := userID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+	}
+	if err := positionID.Scan(c.Params("positionId")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Position ID"})
+	}
+
+	err := inTx(c.Context(), h.pool, h.queries, func(q *db.Queries) error {
+		deleted, err := q.DeleteWorkPosition(c.Context(), db.DeleteWorkPositionParams{ID: positionID, UserID: userID})
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return pgx.ErrNoRows
+		}
+		return userEditedWorkHistory(c.Context(), q, userID)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Work position not found"})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete work position"})
+	}
+	return c.JSON(fiber.Map{"message": "Work position deleted"})
+}
+
+// --- EDUCATION ENTRIES ---
+
+func (h *WorkHistoryHandler) ListEducationEntries(c *fiber.Ctx) error {
+	var userID pgtype.UUID
+	if err := userID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+	}
+
+	entries, err := h.queries.ListEducationEntriesByUser(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch education entries"})
+	}
+	if entries == nil {
+		return c.JSON([]interface{}{})
+	}
+	return c.JSON(entries)
+}
+
+func educationParams(userID pgtype.UUID, req EducationEntryRequest) db.CreateEducationEntryParams {
+	return db.CreateEducationEntryParams{
+		UserID:         userID,
+		Institution:    req.Institution,
+		Degree:         pgtype.Text{String: req.Degree, Valid: req.Degree != ""},
+		FieldOfStudy:   pgtype.Text{String: req.FieldOfStudy, Valid: req.FieldOfStudy != ""},
+		GraduationYear: pgtype.Int4{Int32: req.GraduationYear, Valid: req.GraduationYear != 0},
+	}
+}
+
+func (h *WorkHistoryHandler) CreateEducationEntry(c *fiber.Ctx) error {
+	var userID pgtype.UUID
+	if err := userID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+	}
+	if _, err := h.queries.GetUserByID(c.Context(), userID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+	}
+
+	var req EducationEntryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var entry db.EducationEntry
+	err := inTx(c.Context(), h.pool, h.queries, func(q *db.Queries) error {
+		var err error
+		entry, err = q.CreateEducationEntry(c.Context(), educationParams(userID, req))
+		if err != nil {
+			return err
+		}
+		return userEditedEducation(c.Context(), q, userID)
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create education entry: " + err.Error()})
+	}
+	return c.Status(fiber.StatusCreated).JSON(entry)
+}
+
+// ReplaceEducationEntries swaps all of the user's education entries for the
+// list in the body
+func (h *WorkHistoryHandler) ReplaceEducationEntries(c *fiber.Ctx) error {
+	var userID pgtype.UUID
+	if err := userID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+	}
+	if _, err := h.queries.GetUserByID(c.Context(), userID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+	}
+
+	var req []EducationEntryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	params := make([]db.CreateEducationEntryParams, 0, len(req))
+	for _, r := range req {
+		if err := h.validate.Struct(r); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		params = append(params, educationParams(userID, r))
+	}
+
+	var entries []db.EducationEntry
+	err := inTx(c.Context(), h.pool, h.queries, func(q *db.Queries) error {
+		var err error
+		if entries, err = replaceEducationEntries(c.Context(), q, userID, params); err != nil {
+			return err
+		}
+		return markFieldSource(c.Context(), q, userID, services.ProfileFieldEducationHistory, services.FieldSourceUser, pgtype.UUID{})
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to save education entries: " + err.Error()})
+	}
+	return c.JSON(entries)
+}
+
+func (h *WorkHistoryHandler) UpdateEducationEntry(c *fiber.Ctx) error {
+	var userID, entryID pgtype.UUID
+	if err := userID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+	}
+	if err := entryID.Scan(c.Params("entryId")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Entry ID"})
+	}
+
+	var req EducationEntryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var entry db.EducationEntry
+	err := inTx(c.Context(), h.pool, h.queries, func(q *db.Queries) error {
+		arg := educationParams(userID, req)
+		var err error
+		entry, err = q.UpdateEducationEntry(c.Context(), db.UpdateEducationEntryParams{
+			ID:             entryID,
+			UserID:         userID,
+			Institution:    arg.Institution,
+			Degree:         arg.Degree,
+			FieldOfStudy:   arg.FieldOfStudy,
+			GraduationYear: arg.GraduationYear,
+		})
+		if err != nil {
+			return err
+		}
+		return userEditedEducation(c.Context(), q, userID)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Education entry not found"})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update education entry: " + err.Error()})
+	}
+	return c.JSON(entry)
+}
+
+func (h *WorkHistoryHandler) DeleteEducationEntry(c *fiber.Ctx) error {
+	var userID, entryID pgtype.UUID
+	if err := userID.Scan(c.Params("id")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid User ID"})
+	}
+	if err := entryID.Scan(c.Params("entryId")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Entry ID"})
+	}
+
+	err := inTx(c.Context(), h.pool, h.queries, func(q *db.Queries) error {
+		deleted, err := q.DeleteEducationEntry(c.Context(), db.DeleteEducationEntryParams{ID: entryID, UserID: userID})
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return pgx.ErrNoRows
+		}
+		return userEditedEducation(c.Context(), q, userID)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Education entry not found"})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete education entry"})
+	}
+	return c.JSON(fiber.Map{"message": "Education entry deleted"})
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Overall scorecard recommendations, as stored in
+// application_scorecards.recommendation
+const (
+	RecommendationStrongNo  = "STRONG_NO"
+	RecommendationNo        = "NO"
+	RecommendationYes       = "YES"
+	RecommendationStrongYes = "STRONG_YES"
+)
+
+// NotificationApplicationMention is the kind of notification sent to
+// recruiters mentioned in an application note
+const NotificationApplicationMention = "APPLICATION_MENTION"
+
+// MaxApplicationTags and MaxScorecardCriteria bound what a recruiter can
+// attach to one application or job
+const (
+	MaxApplicationTags   = 20
+	MaxScorecardCriteria = 10
+)
+
+// NormalizeTags lowercases and trims tags, collapses inner whitespace to
+// a single hyphen, and drops blanks and repeats. Tags longer than 40
+// characters are an error.
+func NormalizeTags(tags []string) ([]string, error) {
+	out := make([]string, 0, len(tags))
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		tag = strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > 40 {
+			return nil, fmt.Errorf("tag %q is longer than 40 characters", tag)
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	if len(out) > MaxApplicationTags {
+		return nil, fmt.Errorf("an application can have at most %d tags", MaxApplicationTags)
+	}
+	return out, nil
+}
+
+// ApplicationMention tells a recruiter a colleague mentioned them in a
+// note on candidate's application to jobTitle
+func ApplicationMention(applicationID, noteID, author, candidate, jobTitle, body string) Notice {
+	if author == "" {
+		author = "A colleague"
+	}
+	if candidate == "" {
+		candidate = "a candidate"
+	}
+	preview := body
+	if utf8.RuneCountInString(preview) > 200 {
+		preview = string([]rune(preview)[:200]) + "…"
+	}
+	data, _ := json.Marshal(map[string]string{"application_id": applicationID, "note_id": noteID})
+	return Notice{
+		Kind:  NotificationApplicationMention,
+		Title: fmt.Sprintf("%s mentioned you on %s's application", author, candidate),
+		Body:  fmt.Sprintf("On %q: %s", jobTitle, preview),
+		Data:  data,
+	}
+}
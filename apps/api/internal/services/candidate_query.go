@@ -0,0 +1,229 @@
+package services
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CandidateFilter is a recruiter's search compiled to the fields Tracer can
+// filter on. It is echoed back with the results so the UI can show what was
+// understood and let the recruiter correct it.
+type CandidateFilter struct {
+	Role          string   `json:"role"`
+	Skills        []string `json:"skills"`
+	MinExperience int      `json:"min_experience"`
+	Location      string   `json:"location"`
+	Seniority     string   `json:"seniority"`
+	Keywords      []string `json:"keywords"`
+}
+
+// Years implied by a seniority word when the query gives no number
+var seniorityYears = map[string]int{
+	"intern":    0,
+	"junior":    0,
+	"mid":       2,
+	"senior":    5,
+	"lead":      7,
+	"staff":     7,
+	"principal": 8,
+}
+
+var seniorityAliases = map[string]string{
+	"internship": "intern",
+	"jr":         "junior",
+	"entry":      "junior",
+	"fresher":    "junior",
+	"mid-level":  "mid",
+	"midlevel":   "mid",
+	"sr":         "senior",
+}
+
+// roleNouns end a role phrase; roleQualifiers may precede them
+var (
+	roleNouns = map[string]bool{
+		"engineer": true, "developer": true, "designer": true, "scientist": true, "analyst": true,
+		"manager": true, "architect": true, "consultant": true, "administrator": true, "tester": true,
+		"programmer": true, "researcher": true, "writer": true, "marketer": true, "recruiter": true,
+		"sre": true, "devops": true,
+	}
+	roleQualifiers = map[string]bool{
+		"software": true, "frontend": true, "backend": true, "fullstack": true, "full-stack": true,
+		"full": true, "stack": true, "web": true, "mobile": true, "data": true, "ml": true, "ai": true,
+		"cloud": true, "platform": true, "qa": true, "test": true, "security": true, "product": true,
+		"project": true, "ui": true, "ux": true, "ui/ux": true, "graphic": true, "research": true,
+		"machine": true, "learning": true, "systems": true, "site": true, "reliability": true,
+		"embedded": true, "game": true, "blockchain": true, "smart": true, "contract": true,
+	}
+	queryStopwords = map[string]bool{
+		"a": true, "an": true, "the": true, "and": true, "or": true, "with": true, "who": true, "has": true,
+		"having": true, "have": true, "of": true, "for": true, "in": true, "at": true, "from": true,
+		"near": true, "based": true, "located": true, "experience": true, "experienced": true, "exp": true,
+		"candidate": true, "candidates": true, "someone": true, "people": true, "person": true, "looking": true,
+		"find": true, "me": true, "need": true, "want": true, "skills": true, "skilled": true, "knows": true,
+		"knowing": true, "good": true, "strong": true, "plus": true, "least": true, "minimum": true, "min": true,
+		"more": true, "than": true, "over": true, "to": true, "is": true, "are": true, "that": true,
+	}
+)
+
+var (
+	// "3+ years", "at least 5 yrs", "4 yoe"
+	queryYearsRe = regexp.MustCompile(`(?i)\b(\d{1,2})\s*\+?\s*(?:years?|yrs?|yoe)\b`)
+	// "in Bangalore", "based in New York"; the place runs to the next
+	// connective, punctuation or the end
+	queryLocationRe = regexp.MustCompile(`(?i)\b(?:based in|located in|living in|in|from|near)\s+([a-z][a-z .'-]*?)\s*(?:$|[,;]|\b(?:with|who|having|and|that|knowing)\b)`)
+	queryTokenRe    = regexp.MustCompile(`[A-Za-z0-9+#./-]+`)
+)
+
+// ParseCandidateQuery turns a recruiter's free text ("senior golang engineer
+// in Bangalore with 3+ years") into a CandidateFilter. Skills come from the
+// resume parser's dictionary so aliases like "golang" resolve to "Go";
+// whatever is not understood is kept as keywords for ranking.
+func ParseCandidateQuery(q string) CandidateFilter {
+	f := CandidateFilter{Skills: []string{}, Keywords: []string{}}
+	text := " " + collapseSpace(q) + " "
+
+	if m := queryYearsRe.FindStringSubmatchIndex(text); m != nil {
+		f.MinExperience, _ = strconv.Atoi(text[m[2]:m[3]])
+		text = text[:m[0]] + " " + text[m[1]:]
+	}
+
+	f.Skills = append(f.Skills, MatchSkills(text)...)
+	skillWords := map[string]bool{}
+	for _, skill := range f.Skills {
+		for _, w := range skillTokens(skill) {
+			skillWords[w] = true
+		}
+	}
+
+	for _, m := range queryLocationRe.FindAllStringSubmatchIndex(text, -1) {
+		place := strings.TrimSpace(text[m[2]:m[3]])
+		if place != "" && !isQueryTerm(place, skillWords) {
+			f.Location = place
+			text = text[:m[0]] + " " + text[m[1]:]
+			break
+		}
+	}
+
+	var tokens []string
+	for _, t := range queryTokenRe.FindAllString(text, -1) {
+		tokens = append(tokens, strings.ToLower(t))
+	}
+
+	// Role: the last role noun with the qualifiers directly before it
+	roleEnd := -1
+	for i, t := range tokens {
+		if roleNouns[t] || roleNouns[strings.TrimSuffix(t, "s")] {
+			roleEnd = i
+		}
+	}
+	used := make([]bool, len(tokens))
+	if roleEnd >= 0 {
+		start := roleEnd
+		for start > 0 && roleQualifiers[tokens[start-1]] && !skillWords[tokens[start-1]] {
+			start--
+		}
+		words := append([]string(nil), tokens[start:roleEnd+1]...)
+		words[len(words)-1] = strings.TrimSuffix(words[len(words)-1], "s")
+		f.Role = strings.Join(words, " ")
+		for i := start; i <= roleEnd; i++ {
+			used[i] = true
+		}
+	}
+
+	for i, t := range tokens {
+		if used[i] {
+			continue
+		}
+		if s, ok := seniorityAliases[t]; ok {
+			t = s
+		}
+		if _, ok := seniorityYears[t]; ok {
+			if f.Seniority == "" {
+				f.Seniority = t
+			}
+			continue
+		}
+		if skillWords[t] || queryStopwords[t] || len(t) < 2 {
+			continue
+		}
+		if _, err := strconv.Atoi(t); err == nil {
+			continue
+		}
+		f.Keywords = append(f.Keywords, t)
+	}
+
+	if f.MinExperience == 0 && f.Seniority != "" {
+		f.MinExperience = seniorityYears[f.Seniority]
+	}
+	return f
+}
+
+// isQueryTerm reports whether a captured "location" is really a skill, role
+// or seniority word, as in "experience in React"
+func isQueryTerm(place string, skillWords map[string]bool) bool {
+	if len(MatchSkills(place)) > 0 {
+		return true
+	}
+	for _, w := range strings.Fields(strings.ToLower(place)) {
+		if skillWords[w] || roleNouns[w] || roleQualifiers[w] || queryStopwords[w] {
+			return true
+		}
+		if _, ok := seniorityYears[w]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// skillTokens lists the lower-case words a skill can be written as, so they
+// are not mistaken for keywords
+func skillTokens(name string) []string {
+	var words []string
+	for _, s := range knownSkills {
+		if s.Name != name {
+			continue
+		}
+		for _, alias := range append(append([]string{s.Name}, s.Aliases...), s.Exact...) {
+			words = append(words, strings.Fields(strings.ToLower(alias))...)
+		}
+	}
+	return words
+}
+
+// SkillPattern is a case-insensitive POSIX regex matching a skill by name or
+// alias as a whole word, for filtering free-text skill lists in Postgres.
+// "Go" matches "golang, postgres" but not "MongoDB".
+func SkillPattern(name string) string {
+	names := []string{name}
+	for _, s := range knownSkills {
+		if strings.EqualFold(s.Name, name) {
+			names = append(append(names, s.Aliases...), s.Exact...)
+		}
+	}
+	var alts []string
+	seen := map[string]bool{}
+	for _, n := range names {
+		n = strings.ToLower(n)
+		if !seen[n] {
+			seen[n] = true
+			alts = append(alts, regexp.QuoteMeta(n))
+		}
+	}
+	return `(^|[^a-z0-9+#])(` + strings.Join(alts, "|") + `)($|[^a-z0-9+#])`
+}
+
+// SearchTerms is the websearch_to_tsquery input used to rank matches: any
+// of the role, skills or keywords
+func (f CandidateFilter) SearchTerms() string {
+	var terms []string
+	if f.Role != "" {
+		terms = append(terms, f.Role)
+	}
+	terms = append(terms, f.Skills...)
+	terms = append(terms, f.Keywords...)
+	for i, t := range terms {
+		terms[i] = strings.NewReplacer(`"`, "", "-", " ").Replace(t)
+	}
+	return strings.Join(terms, " or ")
+}
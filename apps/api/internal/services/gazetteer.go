@@ -0,0 +1,138 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GazetteerPlace is one gazetteer entry
+type GazetteerPlace struct {
+	Name        string
+	CountryCode string
+	Admin1      string // state or province
+	Latitude    float64
+	Longitude   float64
+	Population  int64
+	Aliases     []string // other names the place goes by
+}
+
+// The header of our own gazetteer format
+var gazetteerHeader = []string{"name", "country_code", "admin1", "latitude", "longitude", "population", "aliases"}
+
+// GeoNames dumps have no header and 19 columns
+// https://download.geonames.org/export/dump/readme.txt
+const (
+	geonamesColumns        = 19
+	geonamesName           = 1
+	geonamesASCIIName      = 2
+	geonamesAlternateNames = 3
+	geonamesLatitude       = 4
+	geonamesLongitude      = 5
+	geonamesFeatureClass   = 6
+	geonamesCountryCode    = 8
+	geonamesAdmin1         = 10
+	geonamesPopulation     = 14
+)
+
+var countryCode = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// ParseGazetteer reads a tab-separated gazetteer. Files starting with our
+// header (name, country_code, admin1, latitude, longitude, population,
+// aliases, with comma-separated aliases) are read as such; anything else
+// is read as a GeoNames dump such as cities15000.txt, keeping populated
+// places only. Blank lines and lines starting with # are skipped.
+func ParseGazetteer(r io.Reader) ([]GazetteerPlace, error) {
+	scanner := bufio.NewScanner(r)
+	// GeoNames alternate names run to tens of kilobytes for big cities
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	var places []GazetteerPlace
+	geonames, line := true, 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, "\t")
+		if len(places) == 0 && geonames && strings.EqualFold(fields[0], gazetteerHeader[0]) {
+			if strings.ToLower(strings.Join(fields, "\t")) != strings.Join(gazetteerHeader, "\t") {
+				return nil, fmt.Errorf("line %d: header must be %s", line, strings.Join(gazetteerHeader, ", "))
+			}
+			geonames = false
+			continue
+		}
+
+		var place GazetteerPlace
+		var err error
+		if geonames {
+			if len(fields) != geonamesColumns {
+				return nil, fmt.Errorf("line %d: expected %d GeoNames columns, got %d", line, geonamesColumns, len(fields))
+			}
+			if fields[geonamesFeatureClass] != "P" {
+				continue
+			}
+			place, err = newPlace(fields[geonamesName], fields[geonamesCountryCode], fields[geonamesAdmin1],
+				fields[geonamesLatitude], fields[geonamesLongitude], fields[geonamesPopulation],
+				append([]string{fields[geonamesASCIIName]}, strings.Split(fields[geonamesAlternateNames], ",")...))
+		} else {
+			if len(fields) != len(gazetteerHeader) {
+				return nil, fmt.Errorf("line %d: expected %d columns, got %d", line, len(gazetteerHeader), len(fields))
+			}
+			place, err = newPlace(fields[0], fields[1], fields[2], fields[3], fields[4], fields[5], strings.Split(fields[6], ","))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		places = append(places, place)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("invalid gazetteer file: %w", err)
+	}
+	return places, nil
+}
+
+func newPlace(name, country, admin1, lat, lng, population string, aliases []string) (GazetteerPlace, error) {
+	p := GazetteerPlace{
+		Name:        strings.TrimSpace(name),
+		CountryCode: strings.ToUpper(strings.TrimSpace(country)),
+		Admin1:      strings.TrimSpace(admin1),
+	}
+	if p.Name == "" {
+		return p, fmt.Errorf("name is required")
+	}
+	if !countryCode.MatchString(p.CountryCode) {
+		return p, fmt.Errorf("%q is not an ISO 3166 country code", country)
+	}
+	var err error
+	if p.Latitude, err = strconv.ParseFloat(strings.TrimSpace(lat), 64); err != nil || p.Latitude < -90 || p.Latitude > 90 {
+		return p, fmt.Errorf("latitude must be between -90 and 90, got %q", lat)
+	}
+	if p.Longitude, err = strconv.ParseFloat(strings.TrimSpace(lng), 64); err != nil || p.Longitude < -180 || p.Longitude > 180 {
+		return p, fmt.Errorf("longitude must be between -180 and 180, got %q", lng)
+	}
+	if population = strings.TrimSpace(population); population != "" {
+		if p.Population, err = strconv.ParseInt(population, 10, 64); err != nil || p.Population < 0 {
+			return p, fmt.Errorf("population must be a non-negative number, got %q", population)
+		}
+	}
+
+	// GeoNames alternate names include postcodes and links; keep the ones
+	// with letters in them that are not URLs
+	seen := map[string]bool{strings.ToLower(p.Name): true}
+	for _, alias := range aliases {
+		alias = strings.TrimSpace(alias)
+		key := strings.ToLower(alias)
+		if seen[key] || !strings.ContainsFunc(alias, unicode.IsLetter) || strings.Contains(key, "://") {
+			continue
+		}
+		seen[key] = true
+		p.Aliases = append(p.Aliases, alias)
+	}
+	return p, nil
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// iCalendar methods (RFC 5546) used for interview invites
+const (
+	CalendarRequest = "REQUEST"
+	CalendarCancel  = "CANCEL"
+)
+
+const calendarProdID = "-//GrindLink//Interviews//EN"
+
+// CalendarPerson is an organizer or attendee of a CalendarEvent
+type CalendarPerson struct {
+	Name  string
+	Email string
+}
+
+// CalendarEvent is one event of an iCalendar invite. UID must stay the
+// same for the life of the event and Sequence must go up whenever a
+// changed event or a cancellation is sent again, or calendars will treat
+// the update as stale.
+type CalendarEvent struct {
+	UID         string
+	Sequence    int
+	Method      string // CalendarRequest or CalendarCancel
+	Start       time.Time
+	Duration    time.Duration
+	TimeZone    string // IANA name, e.g. "Asia/Kolkata"
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Organizer   CalendarPerson
+	Attendees   []CalendarPerson
+	Stamp       time.Time // when the invite was generated
+}
+
+// ICS renders the event as an RFC 5545 calendar object. The start is
+// given in the event's time zone, which is described by a VTIMEZONE built
+// from the Go time zone database.
+func (e CalendarEvent) ICS() (string, error) {
+	loc, err := time.LoadLocation(e.TimeZone)
+	if err != nil {
+		return "", fmt.Errorf("unknown time zone %q", e.TimeZone)
+	}
+	method := e.Method
+	if method == "" {
+		method = CalendarRequest
+	}
+	status := "CONFIRMED"
+	if method == CalendarCancel {
+		status = "CANCELLED"
+	}
+
+	var b calendarWriter
+	b.line("BEGIN:VCALENDAR")
+	b.line("PRODID:" + calendarProdID)
+	b.line("VERSION:2.0")
+	b.line("CALSCALE:GREGORIAN")
+	b.line("METHOD:" + method)
+	if loc != time.UTC {
+		writeTimeZone(&b, loc, e.Start)
+	}
+
+	b.line("BEGIN:VEVENT")
+	b.line("UID:" + escapeText(e.UID))
+	b.line("SEQUENCE:" + fmt.Sprint(e.Sequence))
+	b.line("DTSTAMP:" + e.Stamp.UTC().Format("20060102T150405Z"))
+	if loc == time.UTC {
+		b.line("DTSTART:" + e.Start.UTC().Format("20060102T150405Z"))
+	} else {
+		b.line("DTSTART;TZID=" + loc.String() + ":" + e.Start.In(loc).Format("20060102T150405"))
+	}
+	b.line("DURATION:" + icsDuration(e.Duration))
+	b.line("SUMMARY:" + escapeText(e.Summary))
+	if e.Description != "" {
+		b.line("DESCRIPTION:" + escapeText(e.Description))
+	}
+	if e.Location != "" {
+		b.line("LOCATION:" + escapeText(e.Location))
+	}
+	if e.URL != "" {
+		b.line("URL:" + e.URL)
+	}
+	b.line("STATUS:" + status)
+	b.line("ORGANIZER" + personParams(e.Organizer) + ":mailto:" + e.Organizer.Email)
+	for _, a := range e.Attendees {
+		b.line("ATTENDEE" + personParams(a) + ";ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:" + a.Email)
+	}
+	b.line("END:VEVENT")
+	b.line("END:VCALENDAR")
+	return b.String(), nil
+}
+
+// writeTimeZone describes loc around the event: the offset in force a year
+// before it and every transition up to a year after, each as its own
+// STANDARD or DAYLIGHT observance. Zones without transitions get a single
+// STANDARD observance.
+func writeTimeZone(b *calendarWriter, loc *time.Location, around time.Time) {
+	from := around.AddDate(-1, 0, 0).In(loc)
+	until := around.AddDate(1, 0, 0)
+
+	b.line("BEGIN:VTIMEZONE")
+	b.line("TZID:" + loc.String())
+	name, offset := from.Zone()
+	observance(b, from.IsDST(), from, name, offset, offset)
+	for t := from; t.Before(until); {
+		next := nextTransition(t, until, loc)
+		if next.IsZero() {
+			break
+		}
+		_, before := next.Add(-time.Second).Zone()
+		name, after := next.Zone()
+		// DTSTART is the wall clock time the transition happens at, read
+		// with the offset it replaces
+		observance(b, next.IsDST(), next.In(time.FixedZone("", before)), name, before, after)
+		t = next
+	}
+	b.line("END:VTIMEZONE")
+}
+
+func observance(b *calendarWriter, dst bool, start time.Time, name string, from, to int) {
+	kind := "STANDARD"
+	if dst {
+		kind = "DAYLIGHT"
+	}
+	b.line("BEGIN:" + kind)
+	b.line("DTSTART:" + start.Format("20060102T150405"))
+	b.line("TZOFFSETFROM:" + icsOffset(from))
+	b.line("TZOFFSETTO:" + icsOffset(to))
+	if name != "" && !strings.HasPrefix(name, "+") && !strings.HasPrefix(name, "-") {
+		b.line("TZNAME:" + name)
+	}
+	b.line("END:" + kind)
+}
+
+// nextTransition finds the first instant after t, before until, at which
+// loc's offset changes, to the second. It steps a day at a time and then
+// bisects, which is plenty for the one or two transitions a year has.
+func nextTransition(t, until time.Time, loc *time.Location) time.Time {
+	_, offset := t.In(loc).Zone()
+	lo := t
+	for lo.Before(until) {
+		hi := lo.Add(24 * time.Hour)
+		if _, o := hi.In(loc).Zone(); o != offset {
+			for hi.Sub(lo) > time.Second {
+				mid := lo.Add(hi.Sub(lo) / 2)
+				if _, o := mid.In(loc).Zone(); o == offset {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			return hi.Truncate(time.Second).In(loc)
+		}
+		lo = hi
+	}
+	return time.Time{}
+}
+
+func icsOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds%3600/60)
+}
+
+func icsDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	if minutes%60 == 0 {
+		return fmt.Sprintf("PT%dH", minutes/60)
+	}
+	if minutes > 60 {
+		return fmt.Sprintf("PT%dH%dM", minutes/60, minutes%60)
+	}
+	return fmt.Sprintf("PT%dM", minutes)
+}
+
+func personParams(p CalendarPerson) string {
+	if p.Name == "" {
+		return ""
+	}
+	return `;CN="` + strings.NewReplacer(`"`, "'", "\r", "", "\n", " ").Replace(p.Name) + `"`
+}
+
+// escapeText escapes a TEXT value (RFC 5545 section 3.3.11)
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(s)
+}
+
+// calendarWriter writes content lines with CRLF endings, folded at 75
+// octets without splitting a UTF-8 character
+type calendarWriter struct {
+	strings.Builder
+}
+
+func (w *calendarWriter) line(s string) {
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n ")
+		s = s[cut:]
+		limit = 74 // continuation lines start with a space
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Interview statuses, as stored in interviews.status
+const (
+	InterviewProposed  = "PROPOSED"
+	InterviewScheduled = "SCHEDULED"
+	InterviewCancelled = "CANCELLED"
+)
+
+// Kinds of notification and email sent about an interview
+const (
+	NotificationInterviewProposed  = "INTERVIEW_PROPOSED"
+	NotificationInterviewScheduled = "INTERVIEW_SCHEDULED"
+	NotificationInterviewUpdated   = "INTERVIEW_UPDATED"
+	NotificationInterviewCancelled = "INTERVIEW_CANCELLED"
+)
+
+// MaxInterviewSlots bounds how many times a recruiter can offer at once
+const MaxInterviewSlots = 10
+
+// InterviewUpdate is one change to an interview, told to its participants
+// by email and to the candidate as a notification. Event is the interview
+// as it now stands; Slots are the offered times of a proposal.
+type InterviewUpdate struct {
+	Kind          string
+	InterviewID   string
+	ApplicationID string
+	JobTitle      string
+	Event         CalendarEvent
+	Slots         []time.Time
+	Reason        string
+}
+
+// Subject is the email subject, which doubles as the notification title
+func (u InterviewUpdate) Subject() string {
+	switch u.Kind {
+	case NotificationInterviewProposed:
+		return fmt.Sprintf("Pick a time for your interview: %s", u.JobTitle)
+	case NotificationInterviewScheduled:
+		return fmt.Sprintf("Interview scheduled: %s, %s", u.JobTitle, u.when(u.Event.Start))
+	case NotificationInterviewCancelled:
+		return fmt.Sprintf("Interview cancelled: %s", u.JobTitle)
+	default:
+		return fmt.Sprintf("Interview updated: %s, %s", u.JobTitle, u.when(u.Event.Start))
+	}
+}
+
+// Body is the plain text of the email
+func (u InterviewUpdate) Body() string {
+	var b strings.Builder
+	switch u.Kind {
+	case NotificationInterviewProposed:
+		fmt.Fprintf(&b, "You have been invited to %q for %s. Pick one of these times:\n\n", u.Event.Summary, u.JobTitle)
+		for _, slot := range u.Slots {
+			fmt.Fprintf(&b, "  - %s\n", u.when(slot))
+		}
+		b.WriteString("\n")
+	case NotificationInterviewCancelled:
+		fmt.Fprintf(&b, "%q for %s has been cancelled.\n", u.Event.Summary, u.JobTitle)
+		if u.Reason != "" {
+			fmt.Fprintf(&b, "Reason: %s\n", u.Reason)
+		}
+		return b.String()
+	default:
+		fmt.Fprintf(&b, "%s\n\nWhen: %s (%s)\n", u.Event.Summary, u.when(u.Event.Start), durationText(u.Event.Duration))
+	}
+	if u.Event.Location != "" && u.Event.Location != u.Event.URL {
+		fmt.Fprintf(&b, "Where: %s\n", u.Event.Location)
+	}
+	if u.Event.URL != "" {
+		fmt.Fprintf(&b, "Join: %s\n", u.Event.URL)
+	}
+	return b.String()
+}
+
+// Notice is the candidate's in-app notification
+func (u InterviewUpdate) Notice() Notice {
+	body := fmt.Sprintf("%q for %s", u.Event.Summary, u.JobTitle)
+	switch u.Kind {
+	case NotificationInterviewProposed:
+		body = "Pick a time for " + body
+	case NotificationInterviewCancelled:
+		if u.Reason != "" {
+			body += ": " + u.Reason
+		}
+	default:
+		body += " on " + u.when(u.Event.Start)
+	}
+	data, _ := json.Marshal(map[string]string{"application_id": u.ApplicationID, "interview_id": u.InterviewID})
+	return Notice{
+		Kind:  u.Kind,
+		Title: u.Subject(),
+		Body:  body,
+		Data:  data,
+	}
+}
+
+// when formats t on the interview's wall clock
+func (u InterviewUpdate) when(t time.Time) string {
+	if loc, err := time.LoadLocation(u.Event.TimeZone); err == nil {
+		t = t.In(loc)
+	}
+	return t.Format("Mon 2 Jan 2006, 15:04 MST")
+}
+
+func durationText(d time.Duration) string {
+	minutes := int(d / time.Minute)
+	switch {
+	case minutes%60 == 0:
+		return fmt.Sprintf("%d h", minutes/60)
+	case minutes > 60:
+		return fmt.Sprintf("%d h %d min", minutes/60, minutes%60)
+	default:
+		return fmt.Sprintf("%d min", minutes)
+	}
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Notification kinds for job alerts
+const (
+	NotificationSavedSearchAlert = "SAVED_SEARCH_ALERT"
+	NotificationJobDigest        = "JOB_DIGEST"
+)
+
+// AlertJob is a job listed in an alert
+type AlertJob struct {
+	ID           string
+	Title        string
+	Organization string
+	City         string
+}
+
+// SavedSearchAlert tells a candidate about new jobs for one of their saved
+// searches; more says there were others besides jobs
+func SavedSearchAlert(searchID, name string, jobs []AlertJob, more bool) Notice {
+	return Notice{
+		Kind:  NotificationSavedSearchAlert,
+		Title: fmt.Sprintf("%s for %q", newJobsCount(len(jobs), more), name),
+		Body:  alertBody(jobs, more),
+		Data:  alertData(map[string]interface{}{"saved_search_id": searchID}, jobs),
+	}
+}
+
+// PreferenceDigest tells a candidate about new jobs that fit their job
+// preferences
+func PreferenceDigest(jobs []AlertJob, more bool) Notice {
+	return Notice{
+		Kind:  NotificationJobDigest,
+		Title: newJobsCount(len(jobs), more) + " that fit your preferences",
+		Body:  alertBody(jobs, more),
+		Data:  alertData(map[string]interface{}{}, jobs),
+	}
+}
+
+func newJobsCount(n int, more bool) string {
+	switch {
+	case more:
+		return fmt.Sprintf("%d+ new jobs", n)
+	case n == 1:
+		return "1 new job"
+	}
+	return fmt.Sprintf("%d new jobs", n)
+}
+
+// alertBody lists the jobs, e.g. "Go Developer at Acme (Bengaluru); ..."
+func alertBody(jobs []AlertJob, more bool) string {
+	lines := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		line := job.Title
+		if job.Organization != "" {
+			line += " at " + job.Organization
+		}
+		if job.City != "" {
+			line += " (" + job.City + ")"
+		}
+		lines = append(lines, line)
+	}
+	body := strings.Join(lines, "; ")
+	if more {
+		body += ", and more"
+	}
+	return body
+}
+
+func alertData(data map[string]interface{}, jobs []AlertJob) []byte {
+	ids := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		ids = append(ids, job.ID)
+	}
+	data["job_ids"] = ids
+	out, _ := json.Marshal(data)
+	return out
+}
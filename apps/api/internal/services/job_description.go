@@ -0,0 +1,259 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrNoJobJSON means the model's reply did not contain a JSON object
+var ErrNoJobJSON = errors.New("AI response did not contain a JSON object")
+
+// jobDescriptionPromptTemplate is sent with {{JOB_DESCRIPTION}} replaced
+const jobDescriptionPromptTemplate = `
+    You are an expert technical recruiter. Extract a structured job posting from the job description below.
+    Your response must be ONLY a single, valid JSON object. Do not add any markdown formatting.
+
+    **Fields:**
+    - **title:** The job title, without company name or location.
+    - **job_summary:** A 2-3 sentence summary of the role.
+    - **skills_requirements:** A comma-separated string of the required technical skills.
+    - **education_requirements:** The required degree or qualification, or "".
+    - **experience_min:** Minimum years of experience as a number, 0 if not stated.
+    - **experience_max:** Maximum years of experience as a number, 0 if not stated.
+    - **salary_min:** Minimum annual salary as a plain number in the stated currency (convert "12 LPA" to 1200000, "$120k" to 120000), 0 if not stated.
+    - **salary_max:** Maximum annual salary in the same way, 0 if not stated.
+    - **currency:** The ISO 4217 currency code of the salary (e.g. "INR", "USD"), or "".
+    - **is_unpaid:** true only if the role is explicitly unpaid.
+    - **job_type:** One of "Full-time", "Part-time", "Contract", "Internship", "Freelance".
+    - **location_type:** One of "Remote", "Hybrid", "In-Office".
+    - **location_city:** The city for Hybrid or In-Office roles, or "".
+
+    Job Description:
+    ---
+    {{JOB_DESCRIPTION}}
+    ---
+    JSON Output:`
+
+// JobDraft is a job posting extracted from free text, shaped like
+// CreateJobRequest so the form can be pre-filled with it. Warnings lists
+// values that were dropped or corrected and need a recruiter's eye.
+type JobDraft struct {
+	Title                 string   `json:"title"`
+	JobSummary            string   `json:"job_summary"`
+	SkillsRequirements    string   `json:"skills_requirements"`
+	EducationRequirements string   `json:"education_requirements"`
+	ExperienceMin         int32    `json:"experience_min"`
+	ExperienceMax         int32    `json:"experience_max"`
+	SalaryMin             int32    `json:"salary_min"`
+	SalaryMax             int32    `json:"salary_max"`
+	Currency              string   `json:"currency"`
+	IsUnpaid              bool     `json:"is_unpaid"`
+	JobType               string   `json:"job_type"`
+	LocationType          string   `json:"location_type"`
+	LocationCity          string   `json:"location_city"`
+	Warnings              []string `json:"warnings"`
+}
+
+// flexInt accepts numbers, numeric strings ("5", "1,20,000") and null,
+// since models are not consistent about quoting numbers
+type flexInt int64
+
+func (n *flexInt) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(strings.TrimSpace(string(b)), `"`)
+	s = strings.NewReplacer(",", "", "_", "", " ", "").Replace(s)
+	if s == "" || s == "null" {
+		*n = 0
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		*n = 0 // unreadable numbers are treated as missing
+		return nil
+	}
+	*n = flexInt(f)
+	return nil
+}
+
+// rawJobDraft is what the model is asked to return
+type rawJobDraft struct {
+	Title                 string  `json:"title"`
+	JobSummary            string  `json:"job_summary"`
+	SkillsRequirements    string  `json:"skills_requirements"`
+	EducationRequirements string  `json:"education_requirements"`
+	ExperienceMin         flexInt `json:"experience_min"`
+	ExperienceMax         flexInt `json:"experience_max"`
+	SalaryMin             flexInt `json:"salary_min"`
+	SalaryMax             flexInt `json:"salary_max"`
+	Currency              string  `json:"currency"`
+	IsUnpaid              bool    `json:"is_unpaid"`
+	JobType               string  `json:"job_type"`
+	LocationType          string  `json:"location_type"`
+	LocationCity          string  `json:"location_city"`
+}
+
+// JobDescriptionParser extracts JobDrafts with an LLM. A nil Client means
+// "build a Cerebras client from the environment on each call".
+type JobDescriptionParser struct {
+	Client LLMClient
+}
+
+func (p JobDescriptionParser) Parse(ctx context.Context, description string) (*JobDraft, error) {
+	client := p.Client
+	if client == nil {
+		c, err := NewCerebrasClient()
+		if err != nil {
+			return nil, err
+		}
+		client = c
+	}
+
+	prompt := strings.Replace(jobDescriptionPromptTemplate, "{{JOB_DESCRIPTION}}", description, 1)
+	content, err := client.Complete(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	body := extractJSONObject(content)
+	if body == "" {
+		return nil, ErrNoJobJSON
+	}
+	var raw rawJobDraft
+	if err := json.Unmarshal([]byte(body), &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse AI JSON: %w", err)
+	}
+	return normalizeJobDraft(raw), nil
+}
+
+// extractJSONObject returns the outermost {...} in s, tolerating markdown
+// fences or chatter around it
+func extractJSONObject(s string) string {
+	start := strings.Index(s, "{")
+	end := strings.LastIndex(s, "}")
+	if start < 0 || end < start {
+		return ""
+	}
+	return s[start : end+1]
+}
+
+var currencyRe = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// normalizeJobDraft validates model output: enums are mapped onto the
+// form's values, ranges are made consistent and anything unusable is
+// cleared, each with a warning
+func normalizeJobDraft(raw rawJobDraft) *JobDraft {
+	d := &JobDraft{
+		Title:                 collapseSpace(raw.Title),
+		JobSummary:            strings.TrimSpace(raw.JobSummary),
+		SkillsRequirements:    normalizeSkillList(raw.SkillsRequirements),
+		EducationRequirements: strings.TrimSpace(raw.EducationRequirements),
+		IsUnpaid:              raw.IsUnpaid,
+		LocationCity:          collapseSpace(raw.LocationCity),
+		Warnings:              []string{},
+	}
+	warn := func(format string, args ...interface{}) {
+		d.Warnings = append(d.Warnings, fmt.Sprintf(format, args...))
+	}
+
+	if d.Title == "" {
+		warn("no job title found")
+	} else if utf8.RuneCountInString(d.Title) > 120 {
+		d.Title = string([]rune(d.Title)[:120])
+		warn("title was truncated to 120 characters")
+	}
+
+	d.ExperienceMin, d.ExperienceMax = normalizeRange("experience", int64(raw.ExperienceMin), int64(raw.ExperienceMax), 50, warn)
+	if !d.IsUnpaid {
+		d.SalaryMin, d.SalaryMax = normalizeRange("salary", int64(raw.SalaryMin), int64(raw.SalaryMax), 1<<31-1, warn)
+	}
+
+	d.Currency = strings.ToUpper(strings.TrimSpace(raw.Currency))
+	if d.Currency != "" && !currencyRe.MatchString(d.Currency) {
+		warn("currency %q is not an ISO code and was cleared", raw.Currency)
+		d.Currency = ""
+	}
+	if d.SalaryMax > 0 && d.Currency == "" {
+		warn("salary has no currency")
+	}
+
+	if d.JobType = normalizeJobType(raw.JobType); d.JobType == "" && strings.TrimSpace(raw.JobType) != "" {
+		warn("job type %q was not recognised", raw.JobType)
+	}
+	if d.LocationType = normalizeLocationType(raw.LocationType); d.LocationType == "" && strings.TrimSpace(raw.LocationType) != "" {
+		warn("location type %q was not recognised", raw.LocationType)
+	}
+	if d.LocationType == "Remote" {
+		d.LocationCity = "Remote" // what the post-job form stores for remote roles
+	} else if d.LocationType != "" && d.LocationCity == "" {
+		warn("no city found for a %s role", d.LocationType)
+	}
+	return d
+}
+
+// normalizeRange clamps a min/max pair to [0, limit] and swaps it if it
+// is reversed. A max of 0 means "not stated", so a lone min is kept.
+func normalizeRange(name string, min, max, limit int64, warn func(string, ...interface{})) (int32, int32) {
+	if min < 0 || min > limit {
+		warn("%s_min %d is out of range and was cleared", name, min)
+		min = 0
+	}
+	if max < 0 || max > limit {
+		warn("%s_max %d is out of range and was cleared", name, max)
+		max = 0
+	}
+	if max > 0 && min > max {
+		warn("%s range was reversed and has been swapped", name)
+		min, max = max, min
+	}
+	return int32(min), int32(max)
+}
+
+func normalizeJobType(s string) string {
+	s = strings.ToLower(s)
+	switch {
+	case strings.Contains(s, "intern"):
+		return "Internship"
+	case strings.Contains(s, "part"):
+		return "Part-time"
+	case strings.Contains(s, "full") || strings.Contains(s, "permanent"):
+		return "Full-time"
+	case strings.Contains(s, "contract") || strings.Contains(s, "temporary"):
+		return "Contract"
+	case strings.Contains(s, "freelance"):
+		return "Freelance"
+	}
+	return ""
+}
+
+func normalizeLocationType(s string) string {
+	s = strings.ToLower(s)
+	switch {
+	case strings.Contains(s, "hybrid"):
+		return "Hybrid"
+	case strings.Contains(s, "remote"):
+		return "Remote"
+	case strings.Contains(s, "office") || strings.Contains(s, "site") || strings.Contains(s, "onsite"):
+		return "In-Office"
+	}
+	return ""
+}
+
+// normalizeSkillList trims and de-duplicates a comma-separated skill list
+func normalizeSkillList(s string) string {
+	var skills []string
+	seen := map[string]bool{}
+	for _, skill := range strings.Split(s, ",") {
+		skill = strings.TrimSpace(skill)
+		if skill == "" || seen[strings.ToLower(skill)] {
+			continue
+		}
+		seen[strings.ToLower(skill)] = true
+		skills = append(skills, skill)
+	}
+	return strings.Join(skills, ", ")
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestJobDescriptionParserNormalizesDraft(t *testing.T) {
+	tests := []struct {
+		name     string
+		reply    string
+		check    func(t *testing.T, d *JobDraft)
+		warnings []string
+	}{
+		{
+			name:  "remote role",
+			reply: `{"title": "  Backend   Engineer ", "location_type": "Fully remote", "location_city": "Pune", "job_type": "contract"}`,
+			check: func(t *testing.T, d *JobDraft) {
+				if d.Title != "Backend Engineer" || d.LocationType != "Remote" || d.LocationCity != "Remote" || d.JobType != "Contract" {
+					t.Errorf("draft = %+v", d)
+				}
+			},
+		},
+		{
+			name:  "unrecognised values are cleared",
+			reply: `{"title": "Designer", "job_type": "gig", "location_type": "moon base", "currency": "rupees", "salary_max": 900000}`,
+			check: func(t *testing.T, d *JobDraft) {
+				if d.JobType != "" || d.LocationType != "" || d.Currency != "" || d.SalaryMax != 900000 {
+					t.Errorf("draft = %+v", d)
+				}
+			},
+			warnings: []string{`currency "rupees"`, "salary has no currency", `job type "gig"`, `location type "moon base"`},
+		},
+		{
+			name:  "out of range numbers",
+			reply: `{"title": "Intern", "job_type": "Internship", "experience_min": -1, "experience_max": 99, "salary_min": 1e12, "currency": "USD"}`,
+			check: func(t *testing.T, d *JobDraft) {
+				if d.ExperienceMin != 0 || d.ExperienceMax != 0 || d.SalaryMin != 0 {
+					t.Errorf("draft = %+v", d)
+				}
+			},
+			warnings: []string{"experience_min -1", "experience_max 99", "salary_min 1000000000000"},
+		},
+		{
+			name:  "unpaid roles have no salary",
+			reply: `{"title": "Volunteer Mentor", "is_unpaid": true, "salary_min": 100, "salary_max": 200}`,
+			check: func(t *testing.T, d *JobDraft) {
+				if !d.IsUnpaid || d.SalaryMin != 0 || d.SalaryMax != 0 {
+					t.Errorf("draft = %+v", d)
+				}
+			},
+		},
+		{
+			name:  "null and unreadable numbers are missing",
+			reply: `{"title": "Analyst", "experience_min": null, "experience_max": "a few", "salary_min": "", "salary_max": "12_00_000", "currency": "INR"}`,
+			check: func(t *testing.T, d *JobDraft) {
+				if d.ExperienceMin != 0 || d.ExperienceMax != 0 || d.SalaryMin != 0 || d.SalaryMax != 1200000 {
+					t.Errorf("draft = %+v", d)
+				}
+			},
+		},
+		{
+			name:  "office role without a city",
+			reply: `Here you go: {"title": "Support Lead", "location_type": "On-site"} Hope that helps!`,
+			check: func(t *testing.T, d *JobDraft) {
+				if d.LocationType != "In-Office" || d.LocationCity != "" {
+					t.Errorf("draft = %+v", d)
+				}
+			},
+			warnings: []string{"no city found for a In-Office role"},
+		},
+		{
+			name:     "no title",
+			reply:    `{"job_summary": "Something"}`,
+			check:    func(t *testing.T, d *JobDraft) {},
+			warnings: []string{"no job title found"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			draft, err := JobDescriptionParser{Client: &FakeLLMClient{Reply: tt.reply}}.Parse(context.Background(), "description")
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			tt.check(t, draft)
+			if len(draft.Warnings) != len(tt.warnings) {
+				t.Fatalf("warnings = %q, want %d", draft.Warnings, len(tt.warnings))
+			}
+			for i, want := range tt.warnings {
+				if !strings.Contains(draft.Warnings[i], want) {
+					t.Errorf("warning %d = %q, want it to mention %q", i, draft.Warnings[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestJobDescriptionParserErrors(t *testing.T) {
+	upstream := errors.New("connection reset")
+	tests := []struct {
+		name string
+		llm  *FakeLLMClient
+		want error
+	}{
+		{"model error", &FakeLLMClient{Err: upstream}, upstream},
+		{"no JSON", &FakeLLMClient{Reply: "I cannot parse this."}, ErrNoJobJSON},
+		{"braces the wrong way round", &FakeLLMClient{Reply: "} nothing here {"}, ErrNoJobJSON},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := JobDescriptionParser{Client: tt.llm}.Parse(context.Background(), "description")
+			if !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	_, err := JobDescriptionParser{Client: &FakeLLMClient{Reply: `{"title": "Go Engineer",}`}}.Parse(context.Background(), "description")
+	if err == nil || errors.Is(err, ErrNoJobJSON) {
+		t.Errorf("malformed JSON: err = %v, want a decoding error", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (JobDescriptionParser{Client: &FakeLLMClient{Reply: `{}`}}).Parse(ctx, "description"); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled context: err = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,294 @@
+package services
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aswinbala005/rizeos/api/internal/db"
+)
+
+// FeedPublisher names us in the feeds
+const FeedPublisher = "GrindLink"
+
+// FeedInfo describes a feed as a whole
+type FeedInfo struct {
+	Title   string
+	SiteURL string    // the site the jobs are listed on
+	SelfURL string    // the feed's own URL
+	Updated time.Time // when any job in it last changed
+}
+
+// JobURL returns the public page of the job with the given ID
+type JobURL func(id string) string
+
+const indeedTimeFormat = "Mon, 02 Jan 2006 15:04:05 GMT"
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Self          atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+	Category    string  `xml:"category,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RSSFeed renders jobs as an RSS 2.0 feed
+func RSSFeed(info FeedInfo, jobs []db.ListFeedJobsRow, jobURL JobURL) ([]byte, error) {
+	feed := rssFeed{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         xmlText(info.Title),
+			Link:          info.SiteURL,
+			Description:   xmlText("Open jobs on " + FeedPublisher),
+			LastBuildDate: info.Updated.UTC().Format(time.RFC1123Z),
+			Self:          atomLink{Href: info.SelfURL, Rel: "self", Type: "application/rss+xml"},
+			Items:         make([]rssItem, 0, len(jobs)),
+		},
+	}
+	for _, job := range jobs {
+		url := jobURL(job.ID.String())
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:       xmlText(feedTitle(job)),
+			Link:        url,
+			GUID:        rssGUID{IsPermaLink: true, Value: url},
+			PubDate:     job.PostedAt.Time.UTC().Format(time.RFC1123Z),
+			Description: xmlText(postingDescription(job.JobSummary.String, job.Description)),
+			Category:    xmlText(job.JobType.String),
+		})
+	}
+	return marshalFeed(feed)
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	Title     string      `xml:"title"`
+	ID        string      `xml:"id"`
+	Link      atomLink    `xml:"link"`
+	Published string      `xml:"published"`
+	Updated   string      `xml:"updated"`
+	Author    *atomAuthor `xml:"author,omitempty"`
+	Summary   *atomText   `xml:"summary,omitempty"`
+	Content   atomText    `xml:"content"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// AtomFeed renders jobs as an Atom 1.0 feed
+func AtomFeed(info FeedInfo, jobs []db.ListFeedJobsRow, jobURL JobURL) ([]byte, error) {
+	feed := atomFeed{
+		Title:   xmlText(info.Title),
+		ID:      info.SelfURL,
+		Updated: info.Updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: info.SelfURL, Rel: "self", Type: "application/atom+xml"},
+			{Href: info.SiteURL, Rel: "alternate", Type: "text/html"},
+		},
+		Author:  atomAuthor{Name: FeedPublisher},
+		Entries: make([]atomEntry, 0, len(jobs)),
+	}
+	for _, job := range jobs {
+		entry := atomEntry{
+			Title:     xmlText(feedTitle(job)),
+			ID:        "urn:uuid:" + job.ID.String(),
+			Link:      atomLink{Href: jobURL(job.ID.String()), Rel: "alternate", Type: "text/html"},
+			Published: job.PostedAt.Time.UTC().Format(time.RFC3339),
+			Updated:   job.UpdatedAt.Time.UTC().Format(time.RFC3339),
+			Content:   atomText{Type: "html", Value: xmlText(postingDescription("", job.Description))},
+		}
+		if org := hiringOrganization(job.OrganizationName.String, job.RecruiterName.String); org != "" {
+			entry.Author = &atomAuthor{Name: xmlText(org)}
+		}
+		if summary := strings.TrimSpace(job.JobSummary.String); summary != "" {
+			entry.Summary = &atomText{Type: "text", Value: xmlText(summary)}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	return marshalFeed(feed)
+}
+
+type indeedSource struct {
+	XMLName       xml.Name    `xml:"source"`
+	Publisher     string      `xml:"publisher"`
+	PublisherURL  string      `xml:"publisherurl"`
+	LastBuildDate string      `xml:"lastBuildDate"`
+	Jobs          []indeedJob `xml:"job"`
+}
+
+// Indeed's examples wrap every value in CDATA; encoding/xml splits any
+// "]]>" inside a value so it cannot end the section early
+type cdata struct {
+	Value string `xml:",cdata"`
+}
+
+type indeedJob struct {
+	Title           cdata  `xml:"title"`
+	Date            cdata  `xml:"date"`
+	ReferenceNumber cdata  `xml:"referencenumber"`
+	URL             cdata  `xml:"url"`
+	Company         cdata  `xml:"company"`
+	City            *cdata `xml:"city,omitempty"`
+	Description     cdata  `xml:"description"`
+	Salary          *cdata `xml:"salary,omitempty"`
+	JobType         *cdata `xml:"jobtype,omitempty"`
+	RemoteType      *cdata `xml:"remotetype,omitempty"`
+	Experience      *cdata `xml:"experience,omitempty"`
+	ExpirationDate  *cdata `xml:"expirationdate,omitempty"`
+}
+
+var indeedJobTypes = map[string]string{
+	"Full-time":  "fulltime",
+	"Part-time":  "parttime",
+	"Contract":   "contract",
+	"Freelance":  "contract",
+	"Internship": "internship",
+}
+
+var indeedRemoteTypes = map[string]string{
+	"Remote": "Fully remote",
+	"Hybrid": "Hybrid remote",
+}
+
+// IndeedFeed renders jobs in the XML format Indeed and most other job
+// aggregators crawl
+func IndeedFeed(info FeedInfo, jobs []db.ListFeedJobsRow, jobURL JobURL) ([]byte, error) {
+	source := indeedSource{
+		Publisher:     FeedPublisher,
+		PublisherURL:  info.SiteURL,
+		LastBuildDate: info.Updated.UTC().Format(indeedTimeFormat),
+		Jobs:          make([]indeedJob, 0, len(jobs)),
+	}
+	for _, job := range jobs {
+		j := indeedJob{
+			Title:           cdataOf(job.Title),
+			Date:            cdataOf(job.PostedAt.Time.UTC().Format(indeedTimeFormat)),
+			ReferenceNumber: cdataOf(job.ID.String()),
+			URL:             cdataOf(jobURL(job.ID.String())),
+			Company:         cdataOf(hiringOrganization(job.OrganizationName.String, job.RecruiterName.String)),
+			Description:     cdataOf(postingDescription(job.JobSummary.String, job.Description)),
+			City:            optionalCDATA(job.LocationCity.String),
+			JobType:         optionalCDATA(indeedJobTypes[job.JobType.String]),
+			RemoteType:      optionalCDATA(indeedRemoteTypes[job.LocationType.String]),
+		}
+		if j.City != nil && j.City.Value == "Remote" {
+			j.City = nil
+		}
+		if !job.IsUnpaid.Bool && job.Currency.String != "" && (job.SalaryMin.Int32 > 0 || job.SalaryMax.Int32 > 0) {
+			j.Salary = optionalCDATA(feedSalary(job.Currency.String, job.SalaryMin.Int32, job.SalaryMax.Int32, job.PayPeriod))
+		}
+		if job.ExperienceMin.Int32 > 0 {
+			j.Experience = optionalCDATA(fmt.Sprintf("%d+ years", job.ExperienceMin.Int32))
+		}
+		if job.ExpiresAt.Valid {
+			j.ExpirationDate = optionalCDATA(job.ExpiresAt.Time.UTC().Format(indeedTimeFormat))
+		}
+		source.Jobs = append(source.Jobs, j)
+	}
+	return marshalFeed(source)
+}
+
+// feedTitle is "Title at Company" when we know the company
+func feedTitle(job db.ListFeedJobsRow) string {
+	if org := hiringOrganization(job.OrganizationName.String, job.RecruiterName.String); org != "" {
+		return job.Title + " at " + org
+	}
+	return job.Title
+}
+
+// feedSalary formats a salary range, e.g. "INR 1200000 - 1500000 per year"
+func feedSalary(currency string, min, max int32, period string) string {
+	per := "per " + strings.ToLower(period)
+	switch {
+	case min > 0 && max > 0 && min != max:
+		return fmt.Sprintf("%s %d - %d %s", currency, min, max, per)
+	case max > 0:
+		return fmt.Sprintf("%s %d %s", currency, max, per)
+	}
+	return fmt.Sprintf("%s %d %s", currency, min, per)
+}
+
+func cdataOf(s string) cdata {
+	return cdata{Value: xmlText(s)}
+}
+
+func optionalCDATA(s string) *cdata {
+	if s == "" {
+		return nil
+	}
+	c := cdataOf(s)
+	return &c
+}
+
+// xmlText drops characters XML 1.0 does not allow, such as the stray
+// control characters pasted job descriptions sometimes contain. Text
+// content escapes them anyway, but CDATA sections would pass them through
+// and make the whole feed unparseable.
+func xmlText(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\t' || r == '\n' || r == '\r':
+			return r
+		case r < 0x20, r >= 0xD800 && r <= 0xDFFF, r == 0xFFFE, r == 0xFFFF:
+			return -1
+		}
+		return r
+	}, s)
+}
+
+func marshalFeed(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
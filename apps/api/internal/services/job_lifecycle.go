@@ -0,0 +1,77 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Why a job was closed, as stored in jobs.close_reason
+const (
+	CloseReasonManual   = "MANUAL"
+	CloseReasonExpired  = "EXPIRED"  // its expires_at passed
+	CloseReasonMaxAge   = "MAX_AGE"  // open longer than JOB_MAX_AGE_DAYS
+	CloseReasonInactive = "INACTIVE" // no activity for JOB_INACTIVITY_DAYS
+)
+
+// Lifecycle events, as stored in job_events.kind
+const (
+	JobEventPublished    = "PUBLISHED"
+	JobEventClosed       = "CLOSED"
+	JobEventReopened     = "REOPENED"
+	JobEventExtended     = "EXTENDED"
+	JobEventStaleWarning = "STALE_WARNING"
+	JobEventAutoClosed   = "AUTO_CLOSED"
+)
+
+// Notification kinds, as stored in notifications.kind
+const (
+	NotificationJobClosingSoon = "JOB_CLOSING_SOON"
+	NotificationJobAutoClosed  = "JOB_AUTO_CLOSED"
+)
+
+// Notice is a notification ready to be stored
+type Notice struct {
+	Kind  string
+	Title string
+	Body  string
+	Data  []byte
+}
+
+// StaleJobWarning tells a recruiter their job will be closed at deadline
+// unless they extend it
+func StaleJobWarning(jobID, title, reason string, deadline time.Time) Notice {
+	why := "it will have been open for the maximum time"
+	if reason == CloseReasonInactive {
+		why = "it has had no edits or applications for a while"
+	}
+	return Notice{
+		Kind:  NotificationJobClosingSoon,
+		Title: fmt.Sprintf("%q closes on %s", title, deadline.Format("Jan 2")),
+		Body:  fmt.Sprintf("%q will be closed automatically on %s because %s. Extend the posting to keep it open.", title, deadline.Format("Jan 2, 2006"), why),
+		Data:  noticeData(jobID, reason, deadline),
+	}
+}
+
+// StaleJobClosed tells a recruiter their job was closed by the sweeper
+func StaleJobClosed(jobID, title, reason string, deadline time.Time) Notice {
+	why := "it reached the maximum time a job can stay open"
+	if reason == CloseReasonInactive {
+		why = "it had no edits or applications for too long"
+	}
+	return Notice{
+		Kind:  NotificationJobAutoClosed,
+		Title: fmt.Sprintf("%q was closed", title),
+		Body:  fmt.Sprintf("%q was closed automatically because %s. Reopen it to start accepting applications again.", title, why),
+		Data:  noticeData(jobID, reason, deadline),
+	}
+}
+
+func noticeData(jobID, reason string, deadline time.Time) []byte {
+	data, _ := json.Marshal(map[string]string{
+		"job_id":   jobID,
+		"reason":   reason,
+		"deadline": deadline.UTC().Format(time.RFC3339),
+	})
+	return data
+}
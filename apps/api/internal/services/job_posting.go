@@ -0,0 +1,183 @@
+package services
+
+import (
+	"html"
+	"strings"
+	"time"
+
+	"github.com/aswinbala005/rizeos/api/internal/db"
+)
+
+// JobPosting is a job as schema.org JSON-LD, the structured data search
+// engines read to list a page as a job
+// https://developers.google.com/search/docs/appearance/structured-data/job-posting
+type JobPosting struct {
+	Context                string                  `json:"@context"`
+	Type                   string                  `json:"@type"`
+	Title                  string                  `json:"title"`
+	Description            string                  `json:"description"`
+	Identifier             *PropertyValue          `json:"identifier,omitempty"`
+	URL                    string                  `json:"url,omitempty"`
+	DatePosted             string                  `json:"datePosted"`
+	ValidThrough           string                  `json:"validThrough,omitempty"`
+	EmploymentType         string                  `json:"employmentType,omitempty"`
+	HiringOrganization     *Organization           `json:"hiringOrganization,omitempty"`
+	JobLocation            *Place                  `json:"jobLocation,omitempty"`
+	JobLocationType        string                  `json:"jobLocationType,omitempty"`
+	BaseSalary             *MonetaryAmount         `json:"baseSalary,omitempty"`
+	Skills                 string                  `json:"skills,omitempty"`
+	EducationRequirements  string                  `json:"educationRequirements,omitempty"`
+	ExperienceRequirements *ExperienceRequirements `json:"experienceRequirements,omitempty"`
+}
+
+type PropertyValue struct {
+	Type  string `json:"@type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type Organization struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+}
+
+type Place struct {
+	Type    string          `json:"@type"`
+	Address PostalAddress   `json:"address"`
+	Geo     *GeoCoordinates `json:"geo,omitempty"`
+}
+
+type PostalAddress struct {
+	Type            string `json:"@type"`
+	AddressLocality string `json:"addressLocality"`
+	AddressRegion   string `json:"addressRegion,omitempty"`
+	AddressCountry  string `json:"addressCountry,omitempty"`
+}
+
+type GeoCoordinates struct {
+	Type      string  `json:"@type"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+type MonetaryAmount struct {
+	Type     string            `json:"@type"`
+	Currency string            `json:"currency"`
+	Value    QuantitativeValue `json:"value"`
+}
+
+type QuantitativeValue struct {
+	Type     string `json:"@type"`
+	MinValue int32  `json:"minValue,omitempty"`
+	MaxValue int32  `json:"maxValue,omitempty"`
+	UnitText string `json:"unitText"`
+}
+
+type ExperienceRequirements struct {
+	Type               string `json:"@type"`
+	MonthsOfExperience int32  `json:"monthsOfExperience"`
+}
+
+// schema.org employmentType values for our job types
+var employmentTypes = map[string]string{
+	"Full-time":  "FULL_TIME",
+	"Part-time":  "PART_TIME",
+	"Contract":   "CONTRACTOR",
+	"Freelance":  "CONTRACTOR",
+	"Internship": "INTERN",
+}
+
+// NewJobPosting builds the JSON-LD for an open job; url is its public page.
+// Fields we don't know are left out rather than guessed.
+func NewJobPosting(job db.GetPublicJobRow, url string) JobPosting {
+	posted := job.CreatedAt.Time
+	if job.PublishAt.Valid {
+		posted = job.PublishAt.Time
+	}
+	p := JobPosting{
+		Context:               "https://schema.org/",
+		Type:                  "JobPosting",
+		Title:                 job.Title,
+		Description:           postingDescription(job.JobSummary.String, job.Description),
+		URL:                   url,
+		DatePosted:            posted.UTC().Format(time.RFC3339),
+		EmploymentType:        employmentTypes[job.JobType.String],
+		Skills:                job.SkillsRequirements.String,
+		EducationRequirements: job.EducationRequirements.String,
+	}
+	if job.ExpiresAt.Valid {
+		p.ValidThrough = job.ExpiresAt.Time.UTC().Format(time.RFC3339)
+	}
+
+	if org := hiringOrganization(job.OrganizationName.String, job.RecruiterName.String); org != "" {
+		p.HiringOrganization = &Organization{Type: "Organization", Name: org}
+		p.Identifier = &PropertyValue{Type: "PropertyValue", Name: org, Value: job.ID.String()}
+	}
+
+	// Hybrid jobs are partly on site, so they keep their city. Cities the
+	// gazetteer knows also get their region, country and coordinates.
+	if job.LocationType.String == "Remote" {
+		p.JobLocationType = "TELECOMMUTE"
+	} else if city := job.LocationCity.String; city != "" && city != "Remote" {
+		p.JobLocation = &Place{Type: "Place", Address: PostalAddress{
+			Type:            "PostalAddress",
+			AddressLocality: city,
+			AddressRegion:   job.LocationRegion.String,
+			AddressCountry:  job.LocationCountry.String,
+		}}
+		if job.Latitude.Valid && job.Longitude.Valid {
+			p.JobLocation.Geo = &GeoCoordinates{Type: "GeoCoordinates", Latitude: job.Latitude.Float64, Longitude: job.Longitude.Float64}
+		}
+	}
+
+	if !job.IsUnpaid.Bool && job.Currency.String != "" && (job.SalaryMin.Int32 > 0 || job.SalaryMax.Int32 > 0) {
+		p.BaseSalary = &MonetaryAmount{
+			Type:     "MonetaryAmount",
+			Currency: job.Currency.String,
+			Value: QuantitativeValue{
+				Type:     "QuantitativeValue",
+				MinValue: job.SalaryMin.Int32,
+				MaxValue: job.SalaryMax.Int32,
+				UnitText: job.PayPeriod, // our periods are schema.org's unitText values
+			},
+		}
+	}
+	if job.ExperienceMin.Int32 > 0 {
+		p.ExperienceRequirements = &ExperienceRequirements{
+			Type:               "OccupationalExperienceRequirements",
+			MonthsOfExperience: job.ExperienceMin.Int32 * 12,
+		}
+	}
+	return p
+}
+
+// hiringOrganization is the company a job is shown under: the recruiter's
+// organization, or the recruiter when they have not set one
+func hiringOrganization(organization, recruiter string) string {
+	if organization != "" {
+		return organization
+	}
+	return recruiter
+}
+
+// postingDescription turns the plain-text summary and description into
+// the HTML that JobPosting.description expects: blank lines separate
+// paragraphs and single newlines become line breaks
+func postingDescription(summary, description string) string {
+	var b strings.Builder
+	for _, text := range []string{summary, description} {
+		text = strings.ReplaceAll(strings.TrimSpace(text), "\r\n", "\n")
+		if text == "" {
+			continue
+		}
+		for _, para := range strings.Split(text, "\n\n") {
+			if para = strings.TrimSpace(para); para == "" {
+				continue
+			}
+			b.WriteString("<p>")
+			b.WriteString(strings.ReplaceAll(html.EscapeString(para), "\n", "<br>"))
+			b.WriteString("</p>")
+		}
+	}
+	return b.String()
+}
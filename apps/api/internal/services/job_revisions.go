@@ -0,0 +1,73 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// FieldChange is one field that differs between two job revisions. From
+// and To are the raw JSON values; null means the field was unset.
+type FieldChange struct {
+	Field string          `json:"field"`
+	From  json.RawMessage `json:"from"`
+	To    json.RawMessage `json:"to"`
+}
+
+var jsonNull = json.RawMessage("null")
+
+// DiffJobSnapshots lists the fields that differ between two job_revisions
+// snapshots, sorted by field name. A nil before is the empty job, so the
+// first revision shows every field that was set.
+func DiffJobSnapshots(before, after []byte) ([]FieldChange, error) {
+	from, err := decodeSnapshot(before)
+	if err != nil {
+		return nil, err
+	}
+	to, err := decodeSnapshot(after)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]bool{}
+	for f := range from {
+		fields[f] = true
+	}
+	for f := range to {
+		fields[f] = true
+	}
+
+	changes := []FieldChange{}
+	for f := range fields {
+		a, b := valueOrNull(from[f]), valueOrNull(to[f])
+		if !bytes.Equal(a, b) {
+			changes = append(changes, FieldChange{Field: f, From: a, To: b})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes, nil
+}
+
+func decodeSnapshot(snapshot []byte) (map[string]json.RawMessage, error) {
+	fields := map[string]json.RawMessage{}
+	if len(snapshot) == 0 {
+		return fields, nil
+	}
+	if err := json.Unmarshal(snapshot, &fields); err != nil {
+		return nil, fmt.Errorf("invalid job snapshot: %w", err)
+	}
+	return fields, nil
+}
+
+// valueOrNull compacts a value so formatting never counts as a change
+func valueOrNull(v json.RawMessage) json.RawMessage {
+	if len(v) == 0 {
+		return jsonNull
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, v); err != nil {
+		return v
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// LLMClient sends a single prompt to a chat model and returns the raw text
+// of its reply. Every AI feature goes through this interface so the
+// provider can be swapped or faked in one place.
+type LLMClient interface {
+	Complete(ctx context.Context, prompt string) (string, error)
+}
+
+// ErrLLMNotConfigured is returned when no model provider credentials are set
+var ErrLLMNotConfigured = errors.New("CEREBRAS_API_KEY not set")
+
+const cerebrasURL = "https://api.cerebras.ai/v1/chat/completions"
+
+// CerebrasClient talks to the Cerebras chat completions API
+type CerebrasClient struct {
+	APIKey      string
+	Model       string
+	Temperature float64
+}
+
+// NewCerebrasClient builds a client from CEREBRAS_API_KEY
+func NewCerebrasClient() (*CerebrasClient, error) {
+	apiKey := os.Getenv("CEREBRAS_API_KEY")
+	if apiKey == "" {
+		return nil, ErrLLMNotConfigured
+	}
+	return &CerebrasClient{APIKey: apiKey, Model: resumeModel, Temperature: 0.1}, nil
+}
+
+func (c *CerebrasClient) Complete(ctx context.Context, prompt string) (string, error) {
+	requestBody, _ := json.Marshal(map[string]interface{}{
+		"model": c.Model,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+		"temperature": c.Temperature,
+	})
+
+	req, _ := http.NewRequestWithContext(ctx, "POST", cerebrasURL, bytes.NewBuffer(requestBody))
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request to cerebras: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("cerebras API returned non-200 status: %d, body: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode cerebras response: %w", err)
+	}
+
+	choices, ok := result["choices"].([]interface{})
+	if !ok || len(choices) == 0 {
+		return "", fmt.Errorf("no choices found in AI response")
+	}
+
+	message, ok := choices[0].(map[string]interface{})["message"].(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("invalid message format in AI response")
+	}
+
+	content, ok := message["content"].(string)
+	if !ok {
+		return "", fmt.Errorf("no content found in AI message")
+	}
+	return content, nil
+}
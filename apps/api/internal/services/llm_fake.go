@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"sync"
+)
+
+// FakeLLMClient is a deterministic LLMClient for tests and offline work.
+// It answers every prompt with Reply, or with Respond when that is set, and
+// records the prompts it was sent.
+type FakeLLMClient struct {
+	Reply   string
+	Err     error
+	Respond func(prompt string) (string, error)
+
+	mu      sync.Mutex
+	prompts []string
+}
+
+func (f *FakeLLMClient) Complete(ctx context.Context, prompt string) (string, error) {
+	f.mu.Lock()
+	f.prompts = append(f.prompts, prompt)
+	f.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if f.Respond != nil {
+		return f.Respond(prompt)
+	}
+	return f.Reply, f.Err
+}
+
+// Prompts returns a copy of every prompt received so far
+func (f *FakeLLMClient) Prompts() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.prompts...)
+}
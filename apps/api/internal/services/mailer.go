@@ -0,0 +1,181 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strings"
+	"time"
+)
+
+// Email is one message to one recipient. Calendar, if set, is an
+// iCalendar object sent as a text/calendar part with CalendarMethod, so
+// mail clients show it as an invite.
+type Email struct {
+	To             string
+	Subject        string
+	Body           string
+	Calendar       string
+	CalendarMethod string
+}
+
+// Mailer sends email. Everything that emails goes through this interface
+// so the transport can be swapped or faked in one place.
+type Mailer interface {
+	Send(ctx context.Context, e Email) error
+}
+
+// NewMailer returns an SMTPMailer configured from SMTP_HOST, SMTP_PORT
+// (default 587), SMTP_USERNAME, SMTP_PASSWORD and MAIL_FROM, or a
+// LogMailer when SMTP_HOST is unset
+func NewMailer() Mailer {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		log.Println("SMTP_HOST not set, emails will be logged instead of sent")
+		return LogMailer{}
+	}
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = "no-reply@" + host
+	}
+	return &SMTPMailer{
+		Addr:     net.JoinHostPort(host, port),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     from,
+	}
+}
+
+// SMTPMailer sends through an SMTP server, using STARTTLS when the server
+// offers it
+type SMTPMailer struct {
+	Addr     string
+	Username string
+	Password string
+	From     string
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, e Email) error {
+	msg, err := e.Message(m.From, time.Now())
+	if err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if m.Username != "" {
+		host, _, _ := net.SplitHostPort(m.Addr)
+		auth = smtp.PlainAuth("", m.Username, m.Password, host)
+	}
+
+	// net/smtp has no context support; give up waiting when ctx ends
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(m.Addr, auth, bareAddress(m.From), []string{e.To}, msg)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("failed to send email to %s: %w", e.To, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// LogMailer logs emails instead of sending them, for development
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, e Email) error {
+	log.Printf("mailer: would send %q to %s (calendar: %t)", e.Subject, e.To, e.Calendar != "")
+	return nil
+}
+
+// Message renders the email as a MIME message from from. With a calendar
+// it is multipart/alternative with the body as text/plain and the invite
+// as text/calendar, which is what calendar clients expect.
+func (e Email) Message(from string, date time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	oneLine := strings.NewReplacer("\r", "", "\n", " ")
+	header := func(k, v string) { fmt.Fprintf(&buf, "%s: %s\r\n", k, oneLine.Replace(v)) }
+	header("From", from)
+	header("To", e.To)
+	header("Subject", mime.QEncoding.Encode("utf-8", e.Subject))
+	header("Date", date.Format(time.RFC1123Z))
+	header("Message-ID", messageID(from))
+	header("MIME-Version", "1.0")
+
+	if e.Calendar == "" {
+		header("Content-Type", "text/plain; charset=utf-8")
+		header("Content-Transfer-Encoding", "base64")
+		buf.WriteString("\r\n")
+		writeBase64(&buf, e.Body)
+		return buf.Bytes(), nil
+	}
+
+	mw := multipart.NewWriter(&buf)
+	header("Content-Type", `multipart/alternative; boundary="`+mw.Boundary()+`"`)
+	buf.WriteString("\r\n")
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", e.Body},
+		{"text/calendar; charset=utf-8; method=" + e.CalendarMethod, e.Calendar},
+	} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		var b bytes.Buffer
+		writeBase64(&b, part.content)
+		if _, err := w.Write(b.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeBase64 writes s base64 encoded in 76-character lines
+func writeBase64(buf *bytes.Buffer, s string) {
+	encoded := base64.StdEncoding.EncodeToString([]byte(s))
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+}
+
+func messageID(from string) string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	domain := "localhost"
+	if at := strings.LastIndex(bareAddress(from), "@"); at >= 0 {
+		domain = bareAddress(from)[at+1:]
+	}
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
+
+// bareAddress strips a display name: "GrindLink <hi@x.com>" is "hi@x.com"
+func bareAddress(addr string) string {
+	if i := strings.LastIndex(addr, "<"); i >= 0 {
+		return strings.TrimSuffix(addr[i+1:], ">")
+	}
+	return addr
+}
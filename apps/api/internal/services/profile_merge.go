@@ -0,0 +1,153 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Values of user_field_sources.source
+const (
+	FieldSourceAI   = "AI"
+	FieldSourceUser = "USER"
+)
+
+// Profile fields a parsed resume can be merged into, named as in the users
+// JSON. work_history and education_history replace the structured entries.
+const (
+	ProfileFieldFullName          = "full_name"
+	ProfileFieldJobRole           = "job_role"
+	ProfileFieldBio               = "bio"
+	ProfileFieldSkills            = "skills"
+	ProfileFieldExperience        = "experience"
+	ProfileFieldEducation         = "education"
+	ProfileFieldProjects          = "projects"
+	ProfileFieldPhone             = "phone"
+	ProfileFieldProfessionalEmail = "professional_email"
+	ProfileFieldWorkHistory       = "work_history"
+	ProfileFieldEducationHistory  = "education_history"
+)
+
+// MergeableProfileFields lists the fields in the order they are diffed
+var MergeableProfileFields = []string{
+	ProfileFieldFullName,
+	ProfileFieldJobRole,
+	ProfileFieldBio,
+	ProfileFieldSkills,
+	ProfileFieldExperience,
+	ProfileFieldEducation,
+	ProfileFieldProjects,
+	ProfileFieldPhone,
+	ProfileFieldProfessionalEmail,
+	ProfileFieldWorkHistory,
+	ProfileFieldEducationHistory,
+}
+
+// IsMergeableProfileField reports whether name is in MergeableProfileFields
+func IsMergeableProfileField(name string) bool {
+	for _, f := range MergeableProfileFields {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ProfileValues holds the mergeable part of a profile, either as stored or
+// as proposed by a parsed resume
+type ProfileValues struct {
+	FullName          string           `json:"full_name"`
+	JobRole           string           `json:"job_role"`
+	Bio               string           `json:"bio"`
+	Skills            string           `json:"skills"`
+	Experience        string           `json:"experience"`
+	Education         string           `json:"education"`
+	Projects          []Project        `json:"projects"`
+	Phone             string           `json:"phone"`
+	ProfessionalEmail string           `json:"professional_email"`
+	WorkHistory       []WorkPosition   `json:"work_history"`
+	EducationHistory  []EducationEntry `json:"education_history"`
+}
+
+// ProfileValuesFromResume maps parse output onto profile fields. The
+// resume's email is a contact address, so it proposes professional_email
+// and never the login email.
+func ProfileValuesFromResume(data *ResumeData) ProfileValues {
+	return ProfileValues{
+		FullName:          strings.TrimSpace(data.FullName),
+		JobRole:           strings.TrimSpace(data.JobRole),
+		Bio:               strings.TrimSpace(data.Bio),
+		Skills:            strings.TrimSpace(data.Skills),
+		Experience:        strings.TrimSpace(data.Experience),
+		Education:         strings.TrimSpace(data.Education),
+		Projects:          data.Projects,
+		Phone:             strings.TrimSpace(data.Phone),
+		ProfessionalEmail: strings.TrimSpace(data.Email),
+		WorkHistory:       data.WorkHistory,
+		EducationHistory:  data.EducationHistory,
+	}
+}
+
+// Field returns the value of a mergeable field by name
+func (v ProfileValues) Field(name string) interface{} {
+	switch name {
+	case ProfileFieldFullName:
+		return v.FullName
+	case ProfileFieldJobRole:
+		return v.JobRole
+	case ProfileFieldBio:
+		return v.Bio
+	case ProfileFieldSkills:
+		return v.Skills
+	case ProfileFieldExperience:
+		return v.Experience
+	case ProfileFieldEducation:
+		return v.Education
+	case ProfileFieldProjects:
+		return nonNil(v.Projects)
+	case ProfileFieldPhone:
+		return v.Phone
+	case ProfileFieldProfessionalEmail:
+		return v.ProfessionalEmail
+	case ProfileFieldWorkHistory:
+		return nonNil(v.WorkHistory)
+	case ProfileFieldEducationHistory:
+		return nonNil(v.EducationHistory)
+	}
+	return nil
+}
+
+// nonNil turns a nil slice into an empty one so that "no projects" compares
+// and serialises the same however it was produced
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+// FieldDiff compares one field of the stored profile with a parsed resume
+type FieldDiff struct {
+	Field   string      `json:"field"`
+	Current interface{} `json:"current"`
+	Parsed  interface{} `json:"parsed"`
+	Changed bool        `json:"changed"`
+	// Where the current value came from: "AI", "USER" or "" if unknown
+	CurrentSource string `json:"current_source"`
+}
+
+// DiffProfiles compares every mergeable field. sources maps field names to
+// their recorded FieldSource.
+func DiffProfiles(current, parsed ProfileValues, sources map[string]string) []FieldDiff {
+	diffs := make([]FieldDiff, 0, len(MergeableProfileFields))
+	for _, field := range MergeableProfileFields {
+		cur, next := current.Field(field), parsed.Field(field)
+		diffs = append(diffs, FieldDiff{
+			Field:         field,
+			Current:       cur,
+			Parsed:        next,
+			Changed:       !reflect.DeepEqual(cur, next),
+			CurrentSource: sources[field],
+		})
+	}
+	return diffs
+}
@@ -0,0 +1,30 @@
+package services
+
+import "time"
+
+// Application statuses that end an application
+const (
+	ApplicationRejected  = "REJECTED"
+	ApplicationWithdrawn = "WITHDRAWN"
+)
+
+// ReapplyPolicy says how long a candidate waits before applying to a job
+// again after their application to it was rejected or withdrawn. A zero
+// wait lets them re-apply straight away.
+type ReapplyPolicy struct {
+	AfterRejection  time.Duration
+	AfterWithdrawal time.Duration
+}
+
+// ReapplyAt returns when a candidate may apply again to a job whose
+// application has status and was closed at closedAt. ok is false if the
+// application is still in progress, so they cannot re-apply at all.
+func (p ReapplyPolicy) ReapplyAt(status string, closedAt time.Time) (at time.Time, ok bool) {
+	switch status {
+	case ApplicationRejected:
+		return closedAt.Add(p.AfterRejection), true
+	case ApplicationWithdrawn:
+		return closedAt.Add(p.AfterWithdrawal), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ResumeCache stores extracted text and AI parse results keyed by the
+// SHA-256 of the PDF bytes. Parse results are additionally keyed by
+// ResumePromptVersion, so editing the prompt or switching models
+// invalidates them without a manual flush.
+type ResumeCache struct {
+	queries *db.Queries
+	ttl     time.Duration
+
+	hits       atomic.Int64
+	misses     atomic.Int64
+	textHits   atomic.Int64
+	textMisses atomic.Int64
+}
+
+// ResumeCacheStats is a snapshot of the cache counters since startup
+type ResumeCacheStats struct {
+	PromptVersion string  `json:"prompt_version"`
+	TTLSeconds    int64   `json:"ttl_seconds"`
+	Hits          int64   `json:"hits"`
+	Misses        int64   `json:"misses"`
+	HitRate       float64 `json:"hit_rate"`
+	TextHits      int64   `json:"text_hits"`
+	TextMisses    int64   `json:"text_misses"`
+}
+
+func NewResumeCache(queries *db.Queries, ttl time.Duration) *ResumeCache {
+	return &ResumeCache{queries: queries, ttl: ttl}
+}
+
+// ContentHash returns the hex SHA-256 of a file's bytes
+func ContentHash(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// GetParsed returns the cached parse result for a file under the current
+// prompt version
+func (c *ResumeCache) GetParsed(ctx context.Context, contentHash string) (*ResumeData, bool) {
+	entry, err := c.queries.GetResumeParseCacheEntry(ctx, db.GetResumeParseCacheEntryParams{
+		ContentHash:   contentHash,
+		PromptVersion: ResumePromptVersion,
+	})
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			log.Printf("resume cache: lookup of %s failed: %v", contentHash, err)
+		}
+		c.misses.Add(1)
+		return nil, false
+	}
+
+	var data ResumeData
+	if err := json.Unmarshal(entry.Result, &data); err != nil {
+		c.misses.Add(1)
+		return nil, false
+	}
+	c.hits.Add(1)
+	return &data, true
+}
+
+// GetText returns previously extracted text for a file, which stays valid
+// across prompt versions
+func (c *ResumeCache) GetText(ctx context.Context, contentHash string) (string, bool) {
+	text, err := c.queries.GetCachedResumeText(ctx, contentHash)
+	if err != nil {
+		c.textMisses.Add(1)
+		return "", false
+	}
+	c.textHits.Add(1)
+	return text, true
+}
+
+// Put stores the extracted text and parse result for a file
+func (c *ResumeCache) Put(ctx context.Context, contentHash, text string, data *ResumeData) error {
+	result, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode parse result: %w", err)
+	}
+	return c.queries.UpsertResumeParseCacheEntry(ctx, db.UpsertResumeParseCacheEntryParams{
+		ContentHash:   contentHash,
+		PromptVersion: ResumePromptVersion,
+		ExtractedText: text,
+		Result:        result,
+		ExpiresAt:     pgtype.Timestamptz{Time: time.Now().Add(c.ttl), Valid: true},
+	})
+}
+
+// Purge deletes expired entries and returns how many were removed
+func (c *ResumeCache) Purge(ctx context.Context) (int64, error) {
+	return c.queries.DeleteExpiredResumeParseCache(ctx)
+}
+
+func (c *ResumeCache) Stats() ResumeCacheStats {
+	stats := ResumeCacheStats{
+		PromptVersion: ResumePromptVersion,
+		TTLSeconds:    int64(c.ttl.Seconds()),
+		Hits:          c.hits.Load(),
+		Misses:        c.misses.Load(),
+		TextHits:      c.textHits.Load(),
+		TextMisses:    c.textMisses.Load(),
+	}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total)
+	}
+	return stats
+}
@@ -0,0 +1,330 @@
+package services
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Resume section kinds recognised by SplitResumeSections
+const (
+	SectionHeader     = "header" // text before the first heading: name, contact details
+	SectionSummary    = "summary"
+	SectionExperience = "experience"
+	SectionEducation  = "education"
+	SectionProjects   = "projects"
+	SectionSkills     = "skills"
+	SectionOther      = "other"
+)
+
+// maxResumeChunkRunes keeps each prompt comfortably inside the model's context
+const maxResumeChunkRunes = 6000
+
+// ResumeSection is one headed block of a resume
+type ResumeSection struct {
+	Kind    string
+	Heading string
+	Body    string
+}
+
+var sectionHeadings = map[string][]string{
+	SectionSummary:    {"summary", "professional summary", "profile", "professional profile", "about me", "objective", "career objective"},
+	SectionExperience: {"experience", "work experience", "professional experience", "employment", "employment history", "work history", "internships", "internship experience"},
+	SectionEducation:  {"education", "academic background", "academics", "qualifications", "educational qualifications"},
+	SectionProjects:   {"projects", "personal projects", "academic projects", "key projects"},
+	SectionSkills:     {"skills", "technical skills", "core competencies", "technologies", "tech stack"},
+	SectionOther:      {"certifications", "achievements", "awards", "publications", "languages", "interests", "hobbies", "activities", "extracurricular activities", "volunteering", "references"},
+}
+
+var (
+	headingKinds = map[string]string{}
+
+	// A heading on a line of its own, in any case: "Work Experience", "SKILLS:"
+	lineHeadingRe *regexp.Regexp
+	// An all-caps heading run into surrounding text, which is what PDF
+	// extraction produces when it loses line breaks
+	inlineHeadingRe *regexp.Regexp
+)
+
+func init() {
+	var names []string
+	for kind, list := range sectionHeadings {
+		for _, name := range list {
+			headingKinds[name] = kind
+			names = append(names, name)
+		}
+	}
+	// Longest first so "work experience" wins over "experience"
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+
+	lower := make([]string, len(names))
+	upper := make([]string, len(names))
+	for i, name := range names {
+		lower[i] = strings.ReplaceAll(regexp.QuoteMeta(name), " ", `\s+`)
+		upper[i] = strings.ReplaceAll(regexp.QuoteMeta(strings.ToUpper(name)), " ", `\s+`)
+	}
+	lineHeadingRe = regexp.MustCompile(`(?im)^[ \t]*(?:#+[ \t]*)?(` + strings.Join(lower, "|") + `)[ \t]*:?[ \t]*$`)
+	inlineHeadingRe = regexp.MustCompile(`\b(` + strings.Join(upper, "|") + `)\b:?`)
+}
+
+func headingKind(heading string) string {
+	key := strings.ToLower(strings.Join(strings.Fields(heading), " "))
+	if kind, ok := headingKinds[key]; ok {
+		return kind
+	}
+	return SectionOther
+}
+
+// SplitResumeSections breaks resume text into headed sections. Text before
+// the first recognised heading is returned as a SectionHeader block.
+func SplitResumeSections(text string) []ResumeSection {
+	type mark struct {
+		start, end int
+		heading    string
+	}
+
+	var marks []mark
+	for _, m := range lineHeadingRe.FindAllStringSubmatchIndex(text, -1) {
+		marks = append(marks, mark{start: m[0], end: m[1], heading: text[m[2]:m[3]]})
+	}
+	for _, m := range inlineHeadingRe.FindAllStringSubmatchIndex(text, -1) {
+		marks = append(marks, mark{start: m[0], end: m[1], heading: text[m[2]:m[3]]})
+	}
+	sort.Slice(marks, func(i, j int) bool { return marks[i].start < marks[j].start })
+
+	// The two patterns can find the same heading; keep the first of any overlap
+	var headings []mark
+	for _, m := range marks {
+		if len(headings) > 0 && m.start < headings[len(headings)-1].end {
+			continue
+		}
+		headings = append(headings, m)
+	}
+
+	var sections []ResumeSection
+	prev := 0
+	kind, heading := SectionHeader, ""
+	for _, m := range headings {
+		if body := strings.TrimSpace(text[prev:m.start]); body != "" || kind != SectionHeader {
+			sections = append(sections, ResumeSection{Kind: kind, Heading: heading, Body: body})
+		}
+		prev = m.end
+		kind, heading = headingKind(m.heading), strings.TrimSpace(m.heading)
+	}
+	if body := strings.TrimSpace(text[prev:]); body != "" || kind != SectionHeader {
+		sections = append(sections, ResumeSection{Kind: kind, Heading: heading, Body: body})
+	}
+	return sections
+}
+
+// ChunkResume packs whole sections into chunks of at most maxRunes runes.
+// A section that is too large on its own is split on line boundaries, and
+// a single overlong line on the last space before the limit. Splits always
+// fall on rune boundaries.
+func ChunkResume(text string, maxRunes int) []string {
+	var pieces []string
+	for _, section := range SplitResumeSections(text) {
+		rendered := section.Body
+		if section.Heading != "" {
+			rendered = strings.ToUpper(section.Heading) + "\n" + section.Body
+		}
+		if utf8.RuneCountInString(rendered) <= maxRunes {
+			pieces = append(pieces, rendered)
+			continue
+		}
+
+		prefix := ""
+		if section.Heading != "" {
+			prefix = strings.ToUpper(section.Heading) + " (continued)\n"
+		}
+		budget := maxRunes - utf8.RuneCountInString(prefix)
+		for i, part := range splitLines(section.Body, budget) {
+			if i == 0 && section.Heading != "" {
+				part = strings.ToUpper(section.Heading) + "\n" + part
+			} else {
+				part = prefix + part
+			}
+			pieces = append(pieces, part)
+		}
+	}
+
+	var chunks []string
+	var current strings.Builder
+	currentRunes := 0
+	for _, piece := range pieces {
+		n := utf8.RuneCountInString(piece)
+		if currentRunes > 0 && currentRunes+2+n > maxRunes {
+			chunks = append(chunks, current.String())
+			current.Reset()
+			currentRunes = 0
+		}
+		if currentRunes > 0 {
+			current.WriteString("\n\n")
+			currentRunes += 2
+		}
+		current.WriteString(piece)
+		currentRunes += n
+	}
+	if currentRunes > 0 {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
+
+// splitLines groups whole lines into parts of at most maxRunes runes
+func splitLines(text string, maxRunes int) []string {
+	var parts []string
+	var current []rune
+	for _, line := range strings.Split(text, "\n") {
+		runes := []rune(line)
+		for len(runes) > maxRunes {
+			cut := maxRunes
+			if i := lastSpace(runes[:maxRunes]); i > maxRunes/2 {
+				cut = i
+			}
+			if len(current) > 0 {
+				parts = append(parts, string(current))
+				current = nil
+			}
+			parts = append(parts, strings.TrimSpace(string(runes[:cut])))
+			runes = []rune(strings.TrimLeftFunc(string(runes[cut:]), unicode.IsSpace))
+		}
+		if len(current) > 0 && len(current)+1+len(runes) > maxRunes {
+			parts = append(parts, string(current))
+			current = nil
+		}
+		if len(current) > 0 {
+			current = append(current, '\n')
+		}
+		current = append(current, runes...)
+	}
+	if len(current) > 0 {
+		parts = append(parts, string(current))
+	}
+	return parts
+}
+
+func lastSpace(runes []rune) int {
+	for i := len(runes) - 1; i >= 0; i-- {
+		if unicode.IsSpace(runes[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// MergeResumeData combines per-chunk results into one ResumeData. Scalar
+// fields take the first non-empty value in chunk order, skills, projects,
+// positions and education entries are de-duplicated case-insensitively, and
+// distinct education summaries are joined. Summaries the model left empty
+// are derived from the structured history.
+func MergeResumeData(parts []*ResumeData) *ResumeData {
+	merged := &ResumeData{}
+	var skills []string
+	seenSkills := map[string]bool{}
+	projectIndex := map[string]int{}
+	var educations []string
+	seenEducation := map[string]bool{}
+	seenPositions := map[string]bool{}
+	seenEntries := map[string]bool{}
+
+	for _, part := range parts {
+		if part == nil {
+			continue
+		}
+		firstNonEmpty(&merged.FullName, part.FullName)
+		firstNonEmpty(&merged.Email, part.Email)
+		firstNonEmpty(&merged.JobRole, part.JobRole)
+		firstNonEmpty(&merged.Bio, part.Bio)
+		firstNonEmpty(&merged.Experience, part.Experience)
+
+		for _, skill := range strings.Split(part.Skills, ",") {
+			skill = strings.TrimSpace(skill)
+			key := strings.ToLower(skill)
+			if skill == "" || seenSkills[key] {
+				continue
+			}
+			seenSkills[key] = true
+			skills = append(skills, skill)
+		}
+
+		if edu := strings.TrimSpace(part.Education); edu != "" && !seenEducation[strings.ToLower(edu)] {
+			seenEducation[strings.ToLower(edu)] = true
+			educations = append(educations, edu)
+		}
+
+		for _, project := range part.Projects {
+			key := normalizeKey(project.Title)
+			if key == "" {
+				continue
+			}
+			if i, ok := projectIndex[key]; ok {
+				// Same project seen in two chunks: keep the richer summary
+				if len(project.Summary) > len(merged.Projects[i].Summary) {
+					merged.Projects[i].Summary = project.Summary
+				}
+				continue
+			}
+			projectIndex[key] = len(merged.Projects)
+			merged.Projects = append(merged.Projects, project)
+		}
+
+		for _, position := range part.WorkHistory {
+			key := normalizeKey(position.Company) + "|" + normalizeKey(position.Title)
+			if key == "|" || seenPositions[key] {
+				continue
+			}
+			seenPositions[key] = true
+			merged.WorkHistory = append(merged.WorkHistory, position)
+		}
+
+		for _, entry := range part.EducationHistory {
+			key := normalizeKey(entry.Institution) + "|" + normalizeKey(entry.Degree)
+			if key == "|" || seenEntries[key] {
+				continue
+			}
+			seenEntries[key] = true
+			merged.EducationHistory = append(merged.EducationHistory, entry)
+		}
+	}
+
+	merged.Skills = strings.Join(skills, ", ")
+	merged.Education = strings.Join(educations, "; ")
+	if merged.Projects == nil {
+		merged.Projects = []Project{}
+	}
+	if merged.WorkHistory == nil {
+		merged.WorkHistory = []WorkPosition{}
+	}
+	if merged.EducationHistory == nil {
+		merged.EducationHistory = []EducationEntry{}
+	}
+	if merged.Experience == "" {
+		merged.Experience = SummarizeExperience(merged.WorkHistory, time.Now())
+	}
+	if merged.Education == "" {
+		merged.Education = SummarizeEducation(merged.EducationHistory)
+	}
+	return merged
+}
+
+func firstNonEmpty(dst *string, value string) {
+	if *dst == "" {
+		*dst = strings.TrimSpace(value)
+	}
+}
+
+// normalizeKey lowercases s and drops everything but letters and digits,
+// so "Chat-App " and "chat app" compare equal
+func normalizeKey(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
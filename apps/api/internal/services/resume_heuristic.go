@@ -0,0 +1,600 @@
+package services
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// knownSkills maps a canonical skill name to the spellings that count as a
+// mention of it. Aliases match case-insensitively on token boundaries, so
+// "golang" matches "GoLang" but "c" does not match "C++". Skills whose name
+// is also an ordinary word ("Go", "React", "Rust") only match their
+// capitalised forms, listed in Exact.
+var knownSkills = []struct {
+	Name    string
+	Aliases []string
+	Exact   []string
+}{
+	{"Go", []string{"golang"}, []string{"Go", "GO"}},
+	{"Python", []string{"python"}, nil},
+	{"Java", []string{"java"}, nil},
+	{"JavaScript", []string{"javascript", "js", "es6"}, nil},
+	{"TypeScript", []string{"typescript"}, nil},
+	{"C", nil, []string{"C"}},
+	{"C++", []string{"c++", "cpp"}, nil},
+	{"C#", []string{"c#", "csharp"}, nil},
+	{"Rust", nil, []string{"Rust"}},
+	{"Ruby", []string{"ruby"}, nil},
+	{"PHP", []string{"php"}, nil},
+	{"Kotlin", []string{"kotlin"}, nil},
+	{"Swift", nil, []string{"Swift", "SwiftUI"}},
+	{"Dart", []string{"dart"}, nil},
+	{"Scala", []string{"scala"}, nil},
+	{"R", nil, []string{"R"}},
+	{"Solidity", []string{"solidity"}, nil},
+	{"SQL", []string{"sql"}, nil},
+	{"HTML", []string{"html", "html5"}, nil},
+	{"CSS", []string{"css", "css3"}, nil},
+	{"React", []string{"react.js", "reactjs"}, []string{"React"}},
+	{"React Native", []string{"react native"}, nil},
+	{"Next.js", []string{"next.js", "nextjs"}, nil},
+	{"Vue", []string{"vue", "vue.js", "vuejs"}, nil},
+	{"Angular", []string{"angular", "angularjs"}, nil},
+	{"Svelte", []string{"svelte"}, nil},
+	{"Tailwind CSS", []string{"tailwind", "tailwindcss", "tailwind css"}, nil},
+	{"Redux", []string{"redux"}, nil},
+	{"Node.js", []string{"node.js", "nodejs"}, []string{"Node"}},
+	{"Express", []string{"express.js", "expressjs"}, []string{"Express"}},
+	{"Django", []string{"django"}, nil},
+	{"Flask", []string{"flask"}, nil},
+	{"FastAPI", []string{"fastapi"}, nil},
+	{"Spring", []string{"spring boot", "springboot"}, []string{"Spring"}},
+	{"Rails", []string{"ruby on rails"}, []string{"Rails"}},
+	{"Laravel", []string{"laravel"}, nil},
+	{".NET", []string{".net", "dotnet", "asp.net"}, nil},
+	{"Fiber", []string{"gofiber"}, []string{"Fiber"}},
+	{"GraphQL", []string{"graphql"}, nil},
+	{"REST", []string{"rest api", "rest apis", "restful"}, []string{"REST"}},
+	{"gRPC", []string{"grpc"}, nil},
+	{"PostgreSQL", []string{"postgres", "postgresql"}, nil},
+	{"MySQL", []string{"mysql"}, nil},
+	{"MongoDB", []string{"mongodb", "mongo"}, nil},
+	{"Redis", []string{"redis"}, nil},
+	{"SQLite", []string{"sqlite"}, nil},
+	{"Elasticsearch", []string{"elasticsearch", "elastic search"}, nil},
+	{"Kafka", []string{"kafka"}, nil},
+	{"RabbitMQ", []string{"rabbitmq"}, nil},
+	{"Docker", []string{"docker"}, nil},
+	{"Kubernetes", []string{"kubernetes", "k8s"}, nil},
+	{"Terraform", []string{"terraform"}, nil},
+	{"Ansible", []string{"ansible"}, nil},
+	{"AWS", []string{"aws", "amazon web services"}, nil},
+	{"GCP", []string{"gcp", "google cloud"}, nil},
+	{"Azure", []string{"azure"}, nil},
+	{"Linux", []string{"linux"}, nil},
+	{"Git", []string{"git"}, nil},
+	{"CI/CD", []string{"ci/cd", "cicd", "github actions", "jenkins"}, nil},
+	{"Machine Learning", []string{"machine learning", "ml"}, nil},
+	{"Deep Learning", []string{"deep learning"}, nil},
+	{"NLP", []string{"nlp", "natural language processing"}, nil},
+	{"Computer Vision", []string{"computer vision", "opencv"}, nil},
+	{"LLM", []string{"llm", "llms", "large language models"}, nil},
+	{"PyTorch", []string{"pytorch"}, nil},
+	{"TensorFlow", []string{"tensorflow"}, nil},
+	{"scikit-learn", []string{"scikit-learn", "sklearn"}, nil},
+	{"Pandas", []string{"pandas"}, nil},
+	{"NumPy", []string{"numpy"}, nil},
+	{"Spark", []string{"pyspark", "apache spark"}, []string{"Spark"}},
+	{"Hadoop", []string{"hadoop"}, nil},
+	{"Airflow", []string{"airflow"}, nil},
+	{"Power BI", []string{"power bi", "powerbi"}, nil},
+	{"Tableau", []string{"tableau"}, nil},
+	{"Excel", nil, []string{"Excel", "MS Excel"}},
+	{"Figma", []string{"figma"}, nil},
+	{"Flutter", []string{"flutter"}, nil},
+	{"Android", []string{"android"}, nil},
+	{"iOS", []string{"ios"}, nil},
+	{"Ethereum", []string{"ethereum"}, nil},
+	{"Web3", []string{"web3", "web3.js", "ethers.js"}, nil},
+	{"Blockchain", []string{"blockchain"}, nil},
+}
+
+// skillPatterns is knownSkills compiled once, in the same order
+var skillPatterns = func() []*regexp.Regexp {
+	patterns := make([]*regexp.Regexp, len(knownSkills))
+	for i, skill := range knownSkills {
+		var alts []string
+		for _, alias := range skill.Aliases {
+			alts = append(alts, "(?i:"+regexp.QuoteMeta(alias)+")")
+		}
+		for _, exact := range skill.Exact {
+			alts = append(alts, regexp.QuoteMeta(exact))
+		}
+		// Skill names contain + # . / so \b is not enough
+		patterns[i] = regexp.MustCompile(`(?:^|[^A-Za-z0-9+#./])(?:` + strings.Join(alts, "|") + `)(?:$|[^A-Za-z0-9+#/]|\.(?:$|[^A-Za-z0-9]))`)
+	}
+	return patterns
+}()
+
+// MatchSkills returns the canonical names of every known skill mentioned in
+// text, in dictionary order
+func MatchSkills(text string) []string {
+	var found []string
+	for i, re := range skillPatterns {
+		if re.MatchString(text) {
+			found = append(found, knownSkills[i].Name)
+		}
+	}
+	return found
+}
+
+// monthPattern is an English month name or its abbreviation
+const monthPattern = `\b(?:jan(?:uary)?|feb(?:ruary)?|mar(?:ch)?|apr(?:il)?|may|june?|july?|aug(?:ust)?|sept?(?:ember)?|oct(?:ober)?|nov(?:ember)?|dec(?:ember)?)`
+
+var (
+	emailRe = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	phoneRe = regexp.MustCompile(`(?:\+\d{1,3}[\s-]?)?(?:\(?\d{2,5}\)?[\s-]?)?\d{3,5}[\s-]?\d{4,5}`)
+	urlRe   = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s,;()<>]+|\b(?:linkedin\.com/in|github\.com|gitlab\.com)/[^\s,;()<>]+`)
+
+	// Abbreviations are matched case-sensitively so "Bangalore" is not a B.A.
+	degreeRe = regexp.MustCompile(`(?:^|[^A-Za-z])((?:B\.?\s?Tech|B\.E|B\.?Sc|B\.?Com|B\.A|BCA|BBA|M\.?\s?Tech|M\.E|M\.?Sc|MCA|MBA|M\.S|Ph\.?\s?D)\.?(?:\s+(?:in|of)\s+[A-Za-z&,. ]+)?|(?i:bachelor(?:'s)?\s+of\s+[A-Za-z ]+|master(?:'s)?\s+of\s+[A-Za-z ]+|diploma\s+in\s+[A-Za-z ]+))(?:$|[^A-Za-z])`)
+
+	yearsRe     = regexp.MustCompile(`(?i)(\d{1,2})\+?\s*(?:years?|yrs?)\b`)
+	dateRangeRe = regexp.MustCompile(`(?i)((?:19|20)\d{2})\s*(?:-|–|—|to)\s*((?:19|20)\d{2}|present|current|now|date)`)
+	internRe    = regexp.MustCompile(`(?i)\bintern(?:ship)?\b`)
+
+	roleRe = regexp.MustCompile(`(?i)\b(?:(?:senior|junior|lead|principal|staff|associate)\s+)?(?:(?:software|backend|back-end|frontend|front-end|full[\s-]?stack|web|mobile|android|ios|data|machine learning|ml|ai|devops|cloud|site reliability|qa|test|blockchain|security|platform)\s+)+(?:engineer|developer|scientist|analyst|architect|intern)\b`)
+
+	bulletRe = regexp.MustCompile(`^\s*(?:[-•*▪◦●]|\d+[.)])\s*`)
+
+	// "Jan 2020 - Present", "2019 – 2021", "03/2020 to 06/2022". Only month
+	// names count as months, so "Engineer 2019 - 2020" keeps its title.
+	positionDatesRe = regexp.MustCompile(`(?i)((?:` + monthPattern + `\.?\s+|\d{1,2}/)?(?:19|20)\d{2})\s*(?:-|–|—|to)\s*((?:` + monthPattern + `\.?\s+|\d{1,2}/)?(?:19|20)\d{2}|present|current|now|date)`)
+	titleWordRe     = regexp.MustCompile(`(?i)\b(?:engineer|developer|intern|manager|analyst|scientist|architect|designer|consultant|lead|director|specialist|administrator|associate|trainee)\b`)
+	institutionRe   = regexp.MustCompile(`(?i)\b(?:university|college|institute|school|academy|polytechnic|IIT|NIT|IIIT|BITS)\b`)
+	yearRe          = regexp.MustCompile(`\b(?:19|20)\d{2}\b`)
+)
+
+// ParseResumeHeuristically extracts what it can from resume text without
+// an AI call. Fields it cannot find are left empty; Confidence records how
+// much each populated field should be trusted.
+func ParseResumeHeuristically(text string) *ResumeData {
+	data := &ResumeData{
+		Projects:   []Project{},
+		Source:     ResumeSourceHeuristic,
+		Confidence: map[string]float64{},
+	}
+
+	sections := SplitResumeSections(text)
+	byKind := map[string]string{}
+	for _, s := range sections {
+		if byKind[s.Kind] == "" {
+			byKind[s.Kind] = s.Body
+		} else {
+			byKind[s.Kind] += "\n" + s.Body
+		}
+	}
+
+	// Contact details
+	if email := emailRe.FindString(text); email != "" {
+		data.Email = email
+		data.Confidence["email"] = 0.95
+	}
+	for _, candidate := range phoneRe.FindAllString(text, -1) {
+		digits := countDigits(candidate)
+		if digits >= 10 && digits <= 13 && !looksLikeYearRange(candidate) {
+			data.Phone = strings.TrimSpace(candidate)
+			data.Confidence["phone"] = 0.8
+			break
+		}
+	}
+	for _, link := range urlRe.FindAllString(text, -1) {
+		data.Links = appendUnique(data.Links, strings.TrimRight(link, "."))
+	}
+	if len(data.Links) > 0 {
+		data.Confidence["links"] = 0.9
+	}
+
+	if name := guessName(byKind[SectionHeader], text); name != "" {
+		data.FullName = name
+		data.Confidence["full_name"] = 0.6
+	}
+
+	// Summary -> bio
+	if summary := strings.TrimSpace(byKind[SectionSummary]); summary != "" {
+		data.Bio = firstSentences(collapseSpace(summary), 3)
+		data.Confidence["bio"] = 0.8
+	}
+
+	// Skills: prefer the skills section, fall back to the whole document
+	if skillsText := byKind[SectionSkills]; skillsText != "" {
+		data.Skills = strings.Join(MatchSkills(skillsText), ", ")
+		data.Confidence["skills"] = 0.9
+	}
+	if data.Skills == "" {
+		if skills := MatchSkills(text); len(skills) > 0 {
+			data.Skills = strings.Join(skills, ", ")
+			data.Confidence["skills"] = 0.6
+		}
+	}
+
+	// Education
+	eduText := byKind[SectionEducation]
+	eduConfidence := 0.75
+	if eduText == "" {
+		eduText, eduConfidence = text, 0.5
+	}
+	if m := degreeRe.FindStringSubmatch(eduText); m != nil {
+		data.Education = lineContaining(eduText, strings.TrimSpace(m[1]))
+		data.Confidence["education"] = eduConfidence
+	}
+	data.EducationHistory = extractEducationEntries(eduText)
+	if len(data.EducationHistory) > 0 {
+		data.Confidence["education_history"] = eduConfidence - 0.2
+	}
+
+	// Experience
+	expText := byKind[SectionExperience]
+	if m := yearsRe.FindStringSubmatch(byKind[SectionSummary] + "\n" + expText); m != nil {
+		data.Experience = m[1] + " Years"
+		data.Confidence["experience"] = 0.7
+	} else if years := sumDateRanges(expText); years > 0 {
+		data.Experience = strconv.Itoa(years) + " Years"
+		data.Confidence["experience"] = 0.5
+	} else if internRe.MatchString(expText) {
+		data.Experience = "Intern"
+		data.Confidence["experience"] = 0.5
+	}
+
+	data.WorkHistory = extractWorkPositions(expText)
+	if len(data.WorkHistory) > 0 {
+		data.Confidence["work_history"] = 0.5
+		if data.Experience == "" {
+			data.Experience = SummarizeExperience(data.WorkHistory, time.Now())
+			data.Confidence["experience"] = 0.5
+		}
+	}
+
+	// Job role: a recognisable title in the summary, experience or header
+	for _, source := range []string{byKind[SectionSummary], expText, byKind[SectionHeader]} {
+		if role := roleRe.FindString(source); role != "" {
+			data.JobRole = titleCase(role)
+			data.Confidence["job_role"] = 0.4
+			break
+		}
+	}
+
+	// Projects
+	data.Projects = splitProjects(byKind[SectionProjects])
+	if len(data.Projects) > 0 {
+		data.Confidence["projects"] = 0.5
+	}
+
+	return data
+}
+
+// guessName picks the first short line of letters at the top of the resume
+func guessName(header, text string) string {
+	source := header
+	if source == "" {
+		source = text
+	}
+	lines := strings.Split(source, "\n")
+	for i, line := range lines {
+		if i >= 5 {
+			break
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || emailRe.MatchString(line) || urlRe.MatchString(line) {
+			continue
+		}
+		words := strings.Fields(line)
+		if len(words) < 2 || len(words) > 4 {
+			continue
+		}
+		ok := true
+		for _, w := range words {
+			for _, r := range w {
+				if !unicode.IsLetter(r) && r != '.' && r != '-' && r != '\'' {
+					ok = false
+				}
+			}
+		}
+		if ok {
+			return titleCase(line)
+		}
+	}
+	return ""
+}
+
+// splitProjects treats each short non-bullet line as a project title and
+// the lines under it as its description
+func splitProjects(section string) []Project {
+	projects := []Project{}
+	var current *Project
+	for _, raw := range strings.Split(section, "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			continue
+		}
+		isBullet := bulletRe.MatchString(line)
+		line = strings.TrimSpace(bulletRe.ReplaceAllString(line, ""))
+
+		if !isBullet && len(line) <= 80 && !strings.HasSuffix(line, ".") {
+			title := line
+			if i := strings.IndexAny(title, "|:–—"); i > 0 {
+				title = strings.TrimSpace(title[:i])
+			}
+			projects = append(projects, Project{Title: title})
+			current = &projects[len(projects)-1]
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if current.Summary != "" {
+			current.Summary += " "
+		}
+		current.Summary += line
+	}
+	for i := range projects {
+		projects[i].Summary = firstSentences(projects[i].Summary, 4)
+	}
+	return projects
+}
+
+// extractWorkPositions finds positions by their date ranges. The rest of
+// a dated line (or the line above it, when the dates stand alone) gives the
+// title and company, and the lines up to the next dated line the description.
+func extractWorkPositions(section string) []WorkPosition {
+	positions := []WorkPosition{}
+	var current *WorkPosition
+	addDescription := func(line string) {
+		if current == nil || line == "" {
+			return
+		}
+		if current.Description != "" {
+			current.Description += " "
+		}
+		current.Description += strings.TrimSpace(bulletRe.ReplaceAllString(line, ""))
+	}
+
+	// A plain line may be the heading of a position whose dates follow on
+	// their own line, so it is held back until the next line decides
+	pending := ""
+	for _, raw := range strings.Split(section, "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			continue
+		}
+		m := positionDatesRe.FindStringSubmatchIndex(line)
+		if m == nil {
+			if bulletRe.MatchString(line) {
+				addDescription(pending)
+				pending = ""
+				addDescription(line)
+			} else {
+				addDescription(pending)
+				pending = line
+			}
+			continue
+		}
+
+		heading := strings.Trim(line[:m[0]]+" "+line[m[1]:], " \t|,–—-()")
+		if heading == "" {
+			heading = pending
+		} else {
+			addDescription(pending)
+		}
+		pending = ""
+		title, company := splitTitleCompany(heading)
+		if title == "" && company == "" {
+			continue
+		}
+		positions = append(positions, WorkPosition{
+			Company: company,
+			Title:   title,
+			Start:   normalizeHistoryDate(line[m[2]:m[3]]),
+			End:     normalizeHistoryDate(line[m[4]:m[5]]),
+		})
+		current = &positions[len(positions)-1]
+	}
+	addDescription(pending)
+	for i := range positions {
+		positions[i].Description = firstSentences(positions[i].Description, 2)
+	}
+	return positions
+}
+
+// splitTitleCompany separates "Backend Engineer at Acme", "Acme | Backend
+// Engineer" and similar. The half that reads like a job title is the title.
+func splitTitleCompany(s string) (title, company string) {
+	s = collapseSpace(s)
+	if i := strings.Index(strings.ToLower(s), " at "); i > 0 {
+		return strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+4:])
+	}
+	for _, sep := range []string{" | ", " – ", " — ", " - ", ", "} {
+		if i := strings.Index(s, sep); i > 0 {
+			first, second := strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+len(sep):])
+			if titleWordRe.MatchString(second) && !titleWordRe.MatchString(first) {
+				return second, first
+			}
+			return first, second
+		}
+	}
+	if titleWordRe.MatchString(s) {
+		return s, ""
+	}
+	return "", s
+}
+
+// normalizeHistoryDate converts a resume date to WorkPosition's format;
+// "present" and anything unparsable become ""
+func normalizeHistoryDate(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, "/"); i > 0 && len(s[:i]) == 1 {
+		s = "0" + s
+	}
+	t, ok := ParseHistoryDate(s)
+	if !ok {
+		return ""
+	}
+	if len(s) == 4 {
+		return s
+	}
+	return FormatHistoryDate(t)
+}
+
+// extractEducationEntries builds an entry for each degree mentioned,
+// taking the institution and year from the same line or its neighbours
+func extractEducationEntries(section string) []EducationEntry {
+	entries := []EducationEntry{}
+	var lines []string
+	for _, raw := range strings.Split(section, "\n") {
+		if line := strings.TrimSpace(raw); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	for i, line := range lines {
+		m := degreeRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		entry := EducationEntry{Degree: strings.Trim(strings.TrimSpace(m[1]), ",.")}
+		for _, sep := range []string{" in ", " of "} {
+			if j := strings.Index(entry.Degree, sep); j > 0 {
+				entry.FieldOfStudy = strings.Trim(strings.TrimSpace(entry.Degree[j+len(sep):]), ",.")
+				if sep == " in " {
+					entry.Degree = strings.TrimSpace(entry.Degree[:j])
+				}
+				break
+			}
+		}
+
+		// Same line first, then the line above and below
+		for _, k := range []int{i, i - 1, i + 1} {
+			if k < 0 || k >= len(lines) {
+				continue
+			}
+			if entry.Institution == "" && institutionRe.MatchString(lines[k]) {
+				entry.Institution = institutionName(lines[k])
+			}
+			if entry.GraduationYear == 0 {
+				if years := yearRe.FindAllString(lines[k], -1); len(years) > 0 {
+					entry.GraduationYear, _ = strconv.Atoi(years[len(years)-1])
+				}
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// institutionName trims a line down to the part that names the institution
+func institutionName(line string) string {
+	for _, part := range strings.FieldsFunc(line, func(r rune) bool { return strings.ContainsRune("|,–—()", r) }) {
+		if institutionRe.MatchString(part) {
+			return strings.TrimSpace(part)
+		}
+	}
+	return strings.TrimSpace(line)
+}
+
+// sumDateRanges adds up "2019 - 2022" style ranges, counting open ranges
+// up to the current year
+func sumDateRanges(text string) int {
+	total := 0
+	now := time.Now().Year()
+	for _, m := range dateRangeRe.FindAllStringSubmatch(text, -1) {
+		start, _ := strconv.Atoi(m[1])
+		end, err := strconv.Atoi(m[2])
+		if err != nil {
+			end = now
+		}
+		if end >= start {
+			total += end - start
+		}
+	}
+	return total
+}
+
+func looksLikeYearRange(s string) bool {
+	return dateRangeRe.MatchString(s)
+}
+
+func countDigits(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			n++
+		}
+	}
+	return n
+}
+
+// lineContaining returns the trimmed line of text that contains needle
+func lineContaining(text, needle string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if strings.Contains(line, needle) {
+			line = collapseSpace(line)
+			if len(line) > 200 {
+				// Cut at the last space, or at a rune boundary when a long
+				// run of text has none
+				cut := strings.LastIndex(line[:200], " ")
+				if cut <= 0 {
+					cut = 200
+					for cut > 0 && !utf8.RuneStart(line[cut]) {
+						cut--
+					}
+				}
+				line = line[:cut]
+			}
+			return line
+		}
+	}
+	return collapseSpace(needle)
+}
+
+func firstSentences(text string, n int) string {
+	count := 0
+	for i, r := range text {
+		if r == '.' || r == '!' || r == '?' {
+			if i+1 == len(text) || text[i+1] == ' ' {
+				count++
+				if count == n {
+					return text[:i+1]
+				}
+			}
+		}
+	}
+	return text
+}
+
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func titleCase(s string) string {
+	words := strings.Fields(s)
+	for i, w := range words {
+		runes := []rune(strings.ToLower(w))
+		if len(runes) > 0 {
+			runes[0] = unicode.ToUpper(runes[0])
+		}
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, v := range list {
+		if strings.EqualFold(v, value) {
+			return list
+		}
+	}
+	return append(list, value)
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// A resume with the usual headings, as text extraction gives it
+const sectionedResume = `Priya Sharma
+priya.sharma@example.com | +91 98765 43210 | linkedin.com/in/priyasharma | https://github.com/priya-s
+
+SUMMARY
+Backend engineer with 4+ years of experience building payment systems. I enjoy distributed systems. Based in Bangalore. Open to relocation.
+
+Work Experience:
+Senior Backend Engineer at Acme Payments   Jan 2021 - Present
+- Built a ledger service in Go and PostgreSQL.
+Globex | Software Engineer   2019 - 2020
+- Maintained REST APIs.
+
+## Education
+B.Tech in Computer Science
+Indian Institute of Technology Delhi, 2019
+
+Technical Skills
+Go, PostgreSQL, Docker, Kubernetes, React, C++, AWS
+
+PROJECTS
+Ledger CLI
+A command line tool for double-entry bookkeeping. Written in Go.
+`
+
+// The same kind of resume with its headings lost, so everything is found
+// in the text as a whole
+const unsectionedResume = `Rahul Verma
+rahul@example.in, 9876543210
+Studied Bachelor of Science in Mathematics at Delhi University, graduated 2021.
+Worked with Python, pandas and SQL on reporting dashboards.`
+
+func TestSplitResumeSections(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		kinds []string
+	}{
+		{"line headings in any case", sectionedResume,
+			[]string{SectionHeader, SectionSummary, SectionExperience, SectionEducation, SectionSkills, SectionProjects}},
+		{"all-caps headings run into the text", "Asha Rao SKILLS Go, Rust EDUCATION B.E, 2018 CERTIFICATIONS AWS",
+			[]string{SectionHeader, SectionSkills, SectionEducation, SectionOther}},
+		{"longest heading wins", "Professional Experience\nAcme\nCareer Objective\nGrow",
+			[]string{SectionExperience, SectionSummary}},
+		{"a heading word inside a sentence is not a heading", "I have experience with skills such as Go.",
+			[]string{SectionHeader}},
+		{"no headings", unsectionedResume, []string{SectionHeader}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var kinds []string
+			for _, s := range SplitResumeSections(tt.text) {
+				kinds = append(kinds, s.Kind)
+			}
+			if !reflect.DeepEqual(kinds, tt.kinds) {
+				t.Errorf("kinds = %q, want %q", kinds, tt.kinds)
+			}
+		})
+	}
+
+	sections := SplitResumeSections(sectionedResume)
+	if got := sections[4].Body; got != "Go, PostgreSQL, Docker, Kubernetes, React, C++, AWS" {
+		t.Errorf("skills body = %q", got)
+	}
+	if got := sections[2].Heading; got != "Work Experience" {
+		t.Errorf("experience heading = %q", got)
+	}
+}
+
+func TestParseResumeHeuristicallyContactDetails(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		email string
+		phone string
+		links []string
+	}{
+		{"sectioned resume", sectionedResume, "priya.sharma@example.com", "+91 98765 43210",
+			[]string{"linkedin.com/in/priyasharma", "https://github.com/priya-s"}},
+		{"bare ten-digit phone", unsectionedResume, "rahul@example.in", "9876543210", nil},
+		{"phone with area code", "Call (022) 2345-6789 or mail a.b+jobs@mail.co.uk", "a.b+jobs@mail.co.uk", "(022) 2345-6789", nil},
+		{"year ranges and short numbers are not phones", "Acme 2019 - 2021\nEmployee no. 123456", "", "", nil},
+		{"links are trimmed and de-duplicated", "See www.priya.dev. Also WWW.PRIYA.DEV and github.com/priya-s, (gitlab.com/p)",
+			"", "", []string{"www.priya.dev", "github.com/priya-s", "gitlab.com/p"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := ParseResumeHeuristically(tt.text)
+			if data.Email != tt.email {
+				t.Errorf("email = %q, want %q", data.Email, tt.email)
+			}
+			if data.Phone != tt.phone {
+				t.Errorf("phone = %q, want %q", data.Phone, tt.phone)
+			}
+			if !reflect.DeepEqual(data.Links, tt.links) {
+				t.Errorf("links = %q, want %q", data.Links, tt.links)
+			}
+		})
+	}
+}
+
+func TestMatchSkills(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Golang, GO and Go", []string{"Go"}},
+		{"I go to the gym and rust my bike", nil},
+		{"C++, C# and embedded C", []string{"C", "C++", "C#"}},
+		{"cpp and csharp", []string{"C++", "C#"}},
+		{"React Native apps", []string{"React", "React Native"}},
+		{"reactjs, Next.js and node.js.", []string{"React", "Next.js", "Node.js"}},
+		{"Deployed on k8s with GitHub Actions", []string{"Kubernetes", "CI/CD"}},
+		{"ML, NLP and LLMs", []string{"Machine Learning", "NLP", "LLM"}},
+		{"postgres, MONGO, Redis", []string{"PostgreSQL", "MongoDB", "Redis"}},
+		{"javascripting", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := MatchSkills(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MatchSkills(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractEducationEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		section string
+		want    []EducationEntry
+	}{
+		{"degree with field, institution below", "B.Tech in Computer Science\nIndian Institute of Technology Delhi, 2019",
+			[]EducationEntry{{Degree: "B.Tech", FieldOfStudy: "Computer Science", Institution: "Indian Institute of Technology Delhi", GraduationYear: 2019}}},
+		{"spelled-out degree", "Bachelor of Engineering, Anna University (2016 - 2020)",
+			[]EducationEntry{{Degree: "Bachelor of Engineering", FieldOfStudy: "Engineering", Institution: "Anna University", GraduationYear: 2020}}},
+		{"abbreviations without dots", "MBA | XYZ School of Business | 2022\nBSc Physics, 2018",
+			[]EducationEntry{
+				{Degree: "MBA", Institution: "XYZ School of Business", GraduationYear: 2022},
+				{Degree: "BSc", Institution: "XYZ School of Business", GraduationYear: 2018},
+			}},
+		{"doctorate", "Ph.D in Machine Learning, IISc 2023",
+			[]EducationEntry{{Degree: "Ph.D", FieldOfStudy: "Machine Learning, IISc", GraduationYear: 2023}}},
+		{"place names are not degrees", "Bangalore, Mumbai and Bhubaneswar", []EducationEntry{}},
+		{"lowercase abbreviations are not degrees", "a ba in art", []EducationEntry{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractEducationEntries(tt.section); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("entries = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseResumeHeuristicallyFields(t *testing.T) {
+	data := ParseResumeHeuristically(sectionedResume)
+	if data.Source != ResumeSourceHeuristic {
+		t.Errorf("source = %q", data.Source)
+	}
+	checks := []struct{ field, got, want string }{
+		{"full_name", data.FullName, "Priya Sharma"},
+		{"bio", data.Bio, "Backend engineer with 4+ years of experience building payment systems. I enjoy distributed systems. Based in Bangalore."},
+		{"skills", data.Skills, "Go, C++, React, PostgreSQL, Docker, Kubernetes, AWS"},
+		{"education", data.Education, "B.Tech in Computer Science"},
+		{"experience", data.Experience, "4 Years"},
+		{"job_role", data.JobRole, "Backend Engineer"},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %q, want %q", c.field, c.got, c.want)
+		}
+	}
+
+	wantPositions := []WorkPosition{
+		{Company: "Acme Payments", Title: "Senior Backend Engineer", Start: "2021-01", End: "", Description: "Built a ledger service in Go and PostgreSQL."},
+		{Company: "Globex", Title: "Software Engineer", Start: "2019", End: "2020", Description: "Maintained REST APIs."},
+	}
+	if !reflect.DeepEqual(data.WorkHistory, wantPositions) {
+		t.Errorf("work history = %+v, want %+v", data.WorkHistory, wantPositions)
+	}
+	wantProjects := []Project{{Title: "Ledger CLI", Summary: "A command line tool for double-entry bookkeeping. Written in Go."}}
+	if !reflect.DeepEqual(data.Projects, wantProjects) {
+		t.Errorf("projects = %+v, want %+v", data.Projects, wantProjects)
+	}
+}
+
+func TestParseResumeHeuristicallyConfidence(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want map[string]float64
+	}{
+		{"sections found", sectionedResume, map[string]float64{
+			"email": 0.95, "phone": 0.8, "links": 0.9, "full_name": 0.6, "bio": 0.8,
+			"skills": 0.9, "education": 0.75, "education_history": 0.55,
+			"experience": 0.7, "work_history": 0.5, "job_role": 0.4, "projects": 0.5,
+		}},
+		{"whole-text fallbacks are trusted less", unsectionedResume, map[string]float64{
+			"email": 0.95, "phone": 0.8, "full_name": 0.6,
+			"skills": 0.6, "education": 0.5, "education_history": 0.3,
+		}},
+		{"experience from date ranges", "EXPERIENCE\nAcme, Engineer 2016 - 2019\nGlobex, Engineer 2019 - 2021", map[string]float64{
+			"experience": 0.5, "work_history": 0.5,
+		}},
+		{"nothing recognisable", "lorem ipsum dolor sit amet", map[string]float64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseResumeHeuristically(tt.text).Confidence
+			if len(got) != len(tt.want) {
+				t.Errorf("confidence = %v, want %v", got, tt.want)
+			}
+			for field, want := range tt.want {
+				if math.Abs(got[field]-want) > 1e-9 {
+					t.Errorf("confidence[%s] = %v, want %v", field, got[field], want)
+				}
+			}
+			for field, c := range got {
+				if c <= 0 || c > 1 {
+					t.Errorf("confidence[%s] = %v, want it in (0, 1]", field, c)
+				}
+			}
+		})
+	}
+
+	data := ParseResumeHeuristically("EXPERIENCE\nAcme, Engineer 2016 - 2019\nGlobex, Engineer 2019 - 2021")
+	if data.Experience != "5 Years" {
+		t.Errorf("experience from date ranges = %q, want 5 Years", data.Experience)
+	}
+	if data := ParseResumeHeuristically("EXPERIENCE\nSummer internship at Acme, building dashboards."); data.Experience != "Intern" || !strings.Contains(data.Experience, "Intern") {
+		t.Errorf("internship experience = %q, want Intern", data.Experience)
+	}
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"fmt"
+)
+
+// Resume parser modes, selectable per request or via RESUME_PARSER
+const (
+	ResumeParserAuto      = "auto"      // AI, falling back to heuristics
+	ResumeParserLLM       = "llm"       // AI only
+	ResumeParserHeuristic = "heuristic" // offline rules only
+)
+
+// Values of ResumeData.Source
+const (
+	ResumeSourceAI        = "ai"
+	ResumeSourceHeuristic = "heuristic"
+)
+
+// ResumeTextParser turns extracted resume text into ResumeData
+type ResumeTextParser interface {
+	Parse(ctx context.Context, text string) (*ResumeData, error)
+}
+
+// IsValidResumeParser reports whether mode names a known parser
+func IsValidResumeParser(mode string) bool {
+	switch mode {
+	case ResumeParserAuto, ResumeParserLLM, ResumeParserHeuristic:
+		return true
+	}
+	return false
+}
+
+// NewResumeTextParser returns the parser for a mode; unknown modes get auto
+func NewResumeTextParser(mode string) ResumeTextParser {
+	switch mode {
+	case ResumeParserHeuristic:
+		return HeuristicResumeParser{}
+	case ResumeParserLLM:
+		return LLMResumeParser{}
+	default:
+		return FallbackResumeParser{Primary: LLMResumeParser{}, Fallback: HeuristicResumeParser{}}
+	}
+}
+
+// LLMResumeParser parses with the model. A nil Client means "build a
+// Cerebras client from the environment on each call".
+type LLMResumeParser struct {
+	Client LLMClient
+}
+
+func (p LLMResumeParser) Parse(ctx context.Context, text string) (*ResumeData, error) {
+	client := p.Client
+	if client == nil {
+		c, err := NewCerebrasClient()
+		if err != nil {
+			return nil, err
+		}
+		client = c
+	}
+
+	data, err := ParseResumeText(ctx, client, text)
+	if err != nil {
+		return nil, err
+	}
+	data.Source = ResumeSourceAI
+	return data, nil
+}
+
+// HeuristicResumeParser wraps ParseResumeHeuristically
+type HeuristicResumeParser struct{}
+
+func (HeuristicResumeParser) Parse(_ context.Context, text string) (*ResumeData, error) {
+	if text == "" {
+		return nil, fmt.Errorf("resume text is empty")
+	}
+	return ParseResumeHeuristically(text), nil
+}
+
+// FallbackResumeParser tries Primary and uses Fallback if it fails, so a
+// missing API key or a provider outage degrades results instead of
+// failing onboarding
+type FallbackResumeParser struct {
+	Primary  ResumeTextParser
+	Fallback ResumeTextParser
+}
+
+func (p FallbackResumeParser) Parse(ctx context.Context, text string) (*ResumeData, error) {
+	data, err := p.Primary.Parse(ctx, text)
+	if err == nil {
+		return data, nil
+	}
+	fmt.Printf("Warning: primary resume parser failed, using fallback: %v\n", err)
+	return p.Fallback.Parse(ctx, text)
+}
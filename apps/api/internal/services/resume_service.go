@@ -3,16 +3,23 @@ package services
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"strings"
+	"time"
 
 	"github.com/ledongthuc/pdf"
 )
 
+// httpClient bounds every outbound call so a stuck download or a slow model
+// cannot hold a resume parsing worker forever
+var httpClient = &http.Client{Timeout: 90 * time.Second}
+
 // --- THE FIX IS HERE ---
 // Define the Project struct
 type Project struct {
@@ -30,22 +37,113 @@ type ResumeData struct {
 	Experience string    `json:"experience"`
 	Education  string    `json:"education"`
 	Projects   []Project `json:"projects"` // <-- Changed to a slice of Project
+
+	// Structured history; Experience and Education are summaries of these
+	WorkHistory      []WorkPosition   `json:"work_history"`
+	EducationHistory []EducationEntry `json:"education_history"`
+
+	// Filled by the heuristic parser, which cannot generate prose but can
+	// pick contact details out reliably
+	Phone string   `json:"phone,omitempty"`
+	Links []string `json:"links,omitempty"`
+
+	// Source is "ai" or "heuristic"; Confidence maps JSON field names to a
+	// 0-1 score for heuristic results
+	Source     string             `json:"source,omitempty"`
+	Confidence map[string]float64 `json:"confidence,omitempty"`
 }
 // --- END OF FIX ---
 
+// resumeModel is the Cerebras model used for resume parsing
+const resumeModel = "llama-3.3-70b"
+
+// resumePromptTemplate is the instruction sent to the model; {{RESUME_TEXT}} is
+// replaced with the extracted resume. Any edit here changes ResumePromptVersion,
+// which invalidates previously cached parse results.
+const resumePromptTemplate = `
+    You are an expert Career Analyst and Resume Parser. Your task is to extract and synthesize information from the provided resume text into a clean JSON object.
+    Your response must be ONLY a single, valid JSON object. Do not add any markdown formatting.
+
+    **Extraction & Generation Rules:**
+    - **full_name:** The candidate's full name. (Extract)
+    - **email:** The candidate's primary email address. (Extract)
+    - **job_role:** Analyze the entire resume (experience, projects, skills) and GENERATE a concise, professional job title that best represents this person's expertise. For example, if they have ML projects and Python skills, "Machine Learning Engineer" is a good title, even if their last role was "Intern".
+    - **bio:** A professional summary or objective (max 3 sentences). If no summary exists, GENERATE one based on the content.
+    - **skills:** A comma-separated string of all technical skills. (Extract)
+    - **experience:** A short summary of total experience (e.g., "5 Years" or "Intern"). (Extract)
+    - **education:** A summary of their degree and university. (Extract)
+    - **projects:** An array of JSON objects. For each project found, create an object with:
+    - "title": The exact project title.
+    - "summary": A detailed summary of the project's description and achievements (3-3.5 sentences).
+    - **work_history:** An array of JSON objects, one per position (including internships), most recent first, each with:
+    - "company": The employer name. (Extract)
+    - "title": The position title. (Extract)
+    - "start": Start date as "YYYY-MM", or "YYYY" if the month is not given.
+    - "end": End date in the same format, or "" if the position is current.
+    - "description": What they did in the role (1-2 sentences).
+    - **education_history:** An array of JSON objects, one per degree or qualification, each with:
+    - "institution": The university, college or school. (Extract)
+    - "degree": The degree, e.g. "B.Tech" or "Master of Science". (Extract)
+    - "field_of_study": The major or specialisation, or "".
+    - "graduation_year": The (expected) graduation year as a number, or 0 if unknown.
+
+    {{CHUNK_NOTE}}
+    Resume Text:
+    ---
+    {{RESUME_TEXT}}
+    ---
+    JSON Output:`
+
+// chunkNoteTemplate is inserted when a long resume is parsed in several calls
+const chunkNoteTemplate = `**Note:** This is part %d of %d of a longer resume. Only fill fields supported by this part; use "" or [] for anything it does not contain.`
+
+// ResumePromptVersion identifies the prompt template, model and chunking
+// combination that produced a parse result
+var ResumePromptVersion = func() string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\n%s\n%s\n%d", resumeModel, resumePromptTemplate, chunkNoteTemplate, maxResumeChunkRunes)))
+	return hex.EncodeToString(sum[:8])
+}()
+
 // 1. Extract Text from a PDF file located at a URL
 func ExtractTextFromPDF(pdfUrl string) (string, error) {
-	resp, err := http.Get(pdfUrl)
+	bodyBytes, err := DownloadPDF(pdfUrl)
+	if err != nil {
+		return "", err
+	}
+	return ExtractTextFromPDFBytes(bodyBytes)
+}
+
+// ErrPDFUnavailable means the PDF's URL answered with a client error
+// (missing, forbidden, expired link); fetching it again will not help
+var ErrPDFUnavailable = errors.New("pdf is not available")
+
+// DownloadPDF fetches the raw bytes of a PDF from a public URL. Any status
+// other than 2xx is an error; client errors other than 408 and 429 wrap
+// ErrPDFUnavailable.
+func DownloadPDF(pdfUrl string) ([]byte, error) {
+	resp, err := httpClient.Get(pdfUrl)
 	if err != nil {
-		return "", fmt.Errorf("failed to download pdf: %v", err)
+		return nil, fmt.Errorf("failed to download pdf: %v", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+			resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+			return nil, fmt.Errorf("failed to download pdf: %w (status %d)", ErrPDFUnavailable, resp.StatusCode)
+		}
+		return nil, fmt.Errorf("failed to download pdf: status %d", resp.StatusCode)
+	}
+
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read pdf body: %v", err)
+		return nil, fmt.Errorf("failed to read pdf body: %v", err)
 	}
+	return bodyBytes, nil
+}
 
+// ExtractTextFromPDFBytes converts an in-memory PDF into plain text
+func ExtractTextFromPDFBytes(bodyBytes []byte) (string, error) {
 	r, err := pdf.NewReader(bytes.NewReader(bodyBytes), int64(len(bodyBytes)))
 	if err != nil {
 		return "", fmt.Errorf("failed to create pdf reader: %v", err)
@@ -70,87 +168,41 @@ func ExtractTextFromPDF(pdfUrl string) (string, error) {
 
 // 2. Call Cerebras AI to Parse Text to JSON
 func ParseResumeWithAI(text string) (*ResumeData, error) {
-	apiKey := os.Getenv("CEREBRAS_API_KEY")
-	if apiKey == "" {
-		return nil, fmt.Errorf("CEREBRAS_API_KEY not set")
-	}
-	
-	url := "https://api.cerebras.ai/v1/chat/completions"
-
-	if len(text) > 7000 {
-		text = text[:7000]
-	}
-
-prompt := `
-    You are an expert Career Analyst and Resume Parser. Your task is to extract and synthesize information from the provided resume text into a clean JSON object.
-    Your response must be ONLY a single, valid JSON object. Do not add any markdown formatting.
-
-    **Extraction & Generation Rules:**
-    - **full_name:** The candidate's full name. (Extract)
-    - **email:** The candidate's primary email address. (Extract)
-    - **job_role:** Analyze the entire resume (experience, projects, skills) and GENERATE a concise, professional job title that best represents this person's expertise. For example, if they have ML projects and Python skills, "Machine Learning Engineer" is a good title, even if their last role was "Intern".
-    - **bio:** A professional summary or objective (max 3 sentences). If no summary exists, GENERATE one based on the content.
-    - **skills:** A comma-separated string of all technical skills. (Extract)
-    - **experience:** A short summary of total experience (e.g., "5 Years" or "Intern"). (Extract)
-    - **education:** A summary of their degree and university. (Extract)
-    - **projects:** An array of JSON objects. For each project found, create an object with:
-    - "title": The exact project title.
-    - "summary": A detailed summary of the project's description and achievements (3-3.5 sentences).
-
-    Resume Text:
-    ---
-    ` + text + `
-    ---
-    JSON Output:`
-
-	requestBody, _ := json.Marshal(map[string]interface{}{
-		"model": "llama-3.3-70b",
-		"messages": []map[string]string{
-			{"role": "user", "content": prompt},
-		},
-		"temperature": 0.1,
-	})
-
-	req, _ := http.NewRequestWithContext(context.Background(), "POST", url, bytes.NewBuffer(requestBody))
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	client, err := NewCerebrasClient()
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request to cerebras: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("cerebras API returned non-200 status: %d, body: %s", resp.StatusCode, string(bodyBytes))
+		return nil, err
 	}
+	return ParseResumeText(context.Background(), client, text)
+}
 
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode cerebras response: %w", err)
+// ParseResumeText splits the resume into section-aligned chunks, extracts
+// each one with the model and merges the partial results. Short resumes fit
+// in a single chunk and cost a single call.
+func ParseResumeText(ctx context.Context, client LLMClient, text string) (*ResumeData, error) {
+	chunks := ChunkResume(text, maxResumeChunkRunes)
+	if len(chunks) == 0 {
+		return nil, fmt.Errorf("resume text is empty")
 	}
 
-	choices, ok := result["choices"].([]interface{})
-	if !ok || len(choices) == 0 {
-		return nil, fmt.Errorf("no choices found in AI response")
-	}
-	
-	message, ok := choices[0].(map[string]interface{})["message"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("invalid message format in AI response")
-	}
+	parts := make([]*ResumeData, 0, len(chunks))
+	for i, chunk := range chunks {
+		note := ""
+		if len(chunks) > 1 {
+			note = fmt.Sprintf(chunkNoteTemplate, i+1, len(chunks))
+		}
+		prompt := strings.NewReplacer("{{CHUNK_NOTE}}", note, "{{RESUME_TEXT}}", chunk).Replace(resumePromptTemplate)
 
-	content, ok := message["content"].(string)
-	if !ok {
-		return nil, fmt.Errorf("no content found in AI message")
-	}
+		content, err := client.Complete(ctx, prompt)
+		if err != nil {
+			return nil, err
+		}
 
-	var data ResumeData
-	if err := json.Unmarshal([]byte(content), &data); err != nil {
-		return nil, fmt.Errorf("failed to parse AI JSON: %w. Raw content: %s", err, content)
+		var data ResumeData
+		if err := json.Unmarshal([]byte(content), &data); err != nil {
+			return nil, fmt.Errorf("failed to parse AI JSON: %w. Raw content: %s", err, content)
+		}
+		parts = append(parts, &data)
 	}
 
-	return &data, nil
-}
\ No newline at end of file
+	return MergeResumeData(parts), nil
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// Pay periods, as stored in jobs.pay_period
+const (
+	PayPeriodHour  = "HOUR"
+	PayPeriodDay   = "DAY"
+	PayPeriodWeek  = "WEEK"
+	PayPeriodMonth = "MONTH"
+	PayPeriodYear  = "YEAR"
+)
+
+// PayPeriodsPerYear turns a salary for a period into a yearly one for
+// full-time work. Keep in step with the salary_*_yearly columns added in
+// migration 026.
+var PayPeriodsPerYear = map[string]int64{
+	PayPeriodHour:  2080,
+	PayPeriodDay:   260,
+	PayPeriodWeek:  52,
+	PayPeriodMonth: 12,
+	PayPeriodYear:  1,
+}
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// ExchangeRates maps ISO 4217 codes to how many units of the currency buy
+// one US dollar, as exact decimal strings ready for a NUMERIC column
+type ExchangeRates map[string]string
+
+// ParseExchangeRates reads a rates file. JSON files use the layout most
+// rate providers export, {"base": "EUR", "rates": {"USD": 1.08, ...}},
+// with base defaulting to USD. CSV files have a currency,units_per_usd
+// header. Rates against another base are converted to US dollars, so the
+// file must then include USD.
+func ParseExchangeRates(r io.Reader, format string) (ExchangeRates, error) {
+	base, raw := "USD", map[string]string{}
+	switch format {
+	case "json":
+		var file struct {
+			Base  string                 `json:"base"`
+			Rates map[string]json.Number `json:"rates"`
+		}
+		dec := json.NewDecoder(r)
+		dec.UseNumber()
+		if err := dec.Decode(&file); err != nil {
+			return nil, fmt.Errorf("invalid rates file: %w", err)
+		}
+		if file.Base != "" {
+			base = strings.ToUpper(file.Base)
+		}
+		for currency, rate := range file.Rates {
+			raw[currency] = rate.String()
+		}
+	case "csv":
+		records, err := csv.NewReader(r).ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("invalid rates file: %w", err)
+		}
+		if len(records) == 0 || len(records[0]) != 2 || strings.TrimSpace(records[0][0]) != "currency" {
+			return nil, fmt.Errorf("rates CSV must start with a currency,units_per_usd header")
+		}
+		for _, rec := range records[1:] {
+			raw[rec[0]] = rec[1]
+		}
+	default:
+		return nil, fmt.Errorf("unsupported rates format %q", format)
+	}
+
+	rates := map[string]*big.Rat{base: big.NewRat(1, 1)}
+	for currency, value := range raw {
+		currency = strings.ToUpper(strings.TrimSpace(currency))
+		if !currencyCode.MatchString(currency) {
+			return nil, fmt.Errorf("%q is not an ISO 4217 currency code", currency)
+		}
+		rate, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+		if !ok || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("rate for %s must be a positive number, got %q", currency, value)
+		}
+		rates[currency] = rate
+	}
+	usd, ok := rates["USD"]
+	if !ok {
+		return nil, fmt.Errorf("rates are against %s but do not include USD", base)
+	}
+
+	out := ExchangeRates{}
+	for currency, rate := range rates {
+		out[currency] = new(big.Rat).Quo(rate, usd).FloatString(10)
+	}
+	out["USD"] = "1"
+	return out, nil
+}
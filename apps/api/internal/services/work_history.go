@@ -0,0 +1,154 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WorkPosition is one role in a candidate's work history. Start and End are
+// "YYYY-MM" (or just "YYYY" when the month is unknown); an empty End means
+// the position is current.
+type WorkPosition struct {
+	Company     string `json:"company"`
+	Title       string `json:"title"`
+	Start       string `json:"start"`
+	End         string `json:"end"`
+	Description string `json:"description"`
+}
+
+// EducationEntry is one degree or qualification
+type EducationEntry struct {
+	Institution    string `json:"institution"`
+	Degree         string `json:"degree"`
+	FieldOfStudy   string `json:"field_of_study"`
+	GraduationYear int    `json:"graduation_year,omitempty"`
+}
+
+var monthNames = map[string]time.Month{
+	"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April,
+	"may": time.May, "jun": time.June, "jul": time.July, "aug": time.August,
+	"sep": time.September, "sept": time.September, "oct": time.October, "nov": time.November, "dec": time.December,
+}
+
+// ParseHistoryDate reads the date formats that show up in resumes and model
+// output: "2021-03", "2021-03-15", "2021", "Mar 2021" and "March 2021".
+// Words like "present" are not dates and report false.
+func ParseHistoryDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range []string{"2006-01-02", "2006-01", "2006/01", "01/2006", "2006"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+
+	fields := strings.Fields(strings.ReplaceAll(s, ",", " "))
+	if len(fields) == 2 {
+		name := strings.ToLower(strings.TrimSuffix(fields[0], "."))
+		month, ok := monthNames[name]
+		if !ok && len(name) > 3 {
+			// "June", "March", "Sept." all start with the abbreviation
+			month, ok = monthNames[name[:3]]
+		}
+		year, err := strconv.Atoi(fields[1])
+		if ok && err == nil && year > 1900 {
+			return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// FormatHistoryDate renders a date the way WorkPosition stores it
+func FormatHistoryDate(t time.Time) string {
+	return t.Format("2006-01")
+}
+
+// SummarizeExperience derives the legacy users.experience string from
+// structured positions: "N Years" of total experience with overlapping
+// positions counted once, or "Intern" for less than a year. Positions
+// without a parsable start date are ignored.
+func SummarizeExperience(positions []WorkPosition, now time.Time) string {
+	type span struct{ start, end time.Time }
+
+	var spans []span
+	for _, p := range positions {
+		start, ok := ParseHistoryDate(p.Start)
+		if !ok {
+			continue
+		}
+		end, ok := ParseHistoryDate(p.End)
+		if !ok {
+			end = now
+		}
+		if end.After(start) {
+			spans = append(spans, span{start, end})
+		}
+	}
+	if len(spans) == 0 {
+		if len(positions) > 0 {
+			return "Intern"
+		}
+		return ""
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start.Before(spans[j].start) })
+	var total time.Duration
+	current := spans[0]
+	for _, s := range spans[1:] {
+		if s.start.After(current.end) {
+			total += current.end.Sub(current.start)
+			current = s
+			continue
+		}
+		if s.end.After(current.end) {
+			current.end = s.end
+		}
+	}
+	total += current.end.Sub(current.start)
+
+	years := int(total.Hours() / 24 / 365)
+	if years < 1 {
+		return "Intern"
+	}
+	if years == 1 {
+		return "1 Year"
+	}
+	return fmt.Sprintf("%d Years", years)
+}
+
+// SummarizeEducation derives the legacy users.education string, most recent
+// entry first: "B.Tech in Computer Science, IIT Madras (2022)"
+func SummarizeEducation(entries []EducationEntry) string {
+	sorted := append([]EducationEntry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].GraduationYear > sorted[j].GraduationYear })
+
+	var parts []string
+	for _, e := range sorted {
+		var b strings.Builder
+		b.WriteString(strings.TrimSpace(e.Degree))
+		if field := strings.TrimSpace(e.FieldOfStudy); field != "" {
+			if b.Len() > 0 {
+				b.WriteString(" in ")
+			}
+			b.WriteString(field)
+		}
+		if inst := strings.TrimSpace(e.Institution); inst != "" {
+			if b.Len() > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(inst)
+		}
+		if e.GraduationYear > 0 {
+			fmt.Fprintf(&b, " (%d)", e.GraduationYear)
+		}
+		if b.Len() > 0 {
+			parts = append(parts, b.String())
+		}
+	}
+	return strings.Join(parts, "; ")
+}
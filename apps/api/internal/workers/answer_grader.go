@@ -0,0 +1,171 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/aswinbala005/rizeos/api/internal/services"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	gradePollInterval = 5 * time.Second
+	gradeStaleAfter   = 5 * time.Minute
+	gradeRetryBase    = 10 * time.Second
+	gradeRetryMax     = 10 * time.Minute
+	gradeMaxAttempts  = 3
+	gradeReaperEvery  = time.Minute
+)
+
+// AnswerGrader drains PENDING free-text screening answers, grades
+// them against the question's rubric and keeps applications.gateway_grade
+// up to date.
+type AnswerGrader struct {
+	queries *db.Queries
+	grader  services.LLMAnswerGrader
+	wake    chan struct{}
+}
+
+func NewAnswerGrader(queries *db.Queries, grader services.LLMAnswerGrader) *AnswerGrader {
+	return &AnswerGrader{
+		queries: queries,
+		grader:  grader,
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Notify wakes the worker so freshly submitted answers are graded without
+// waiting for the next poll.
+func (w *AnswerGrader) Notify() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (w *AnswerGrader) Run(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.reap(ctx)
+	}()
+
+	for {
+		answer, err := w.queries.ClaimAnswerForGrading(ctx)
+		if err == nil {
+			w.process(ctx, answer)
+			continue
+		}
+		if !errors.Is(err, pgx.ErrNoRows) && ctx.Err() == nil {
+			log.Printf("answer grader: failed to claim answer: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			<-done
+			return
+		case <-w.wake:
+		case <-time.After(gradePollInterval):
+		}
+	}
+}
+
+func (w *AnswerGrader) process(ctx context.Context, answer db.ApplicationAnswer) {
+	answerID := answer.ID.String()
+
+	grade, err := w.grade(ctx, answer)
+	if err == nil {
+		err = w.queries.CompleteAnswerGrade(ctx, db.CompleteAnswerGradeParams{
+			ID:        answer.ID,
+			Grade:     pgtype.Int4{Int32: grade.Grade, Valid: true},
+			Rationale: pgtype.Text{String: grade.Rationale, Valid: grade.Rationale != ""},
+		})
+		if err != nil {
+			log.Printf("answer grader: failed to store grade for answer %s: %v", answerID, err)
+			return
+		}
+		if err := w.queries.UpdateApplicationGatewayGrade(ctx, answer.ApplicationID); err != nil {
+			log.Printf("answer grader: failed to update application grade: %v", err)
+		}
+		w.rescreen(ctx, answer.ApplicationID)
+		return
+	}
+
+	if answer.Attempts >= gradeMaxAttempts {
+		log.Printf("answer grader: answer %s failed permanently after %d attempts: %v", answerID, answer.Attempts, err)
+		if err := w.queries.FailAnswerGrade(ctx, db.FailAnswerGradeParams{
+			ID:        answer.ID,
+			Rationale: pgtype.Text{String: "Grading failed: " + err.Error(), Valid: true},
+		}); err != nil {
+			log.Printf("answer grader: failed to mark answer %s as failed: %v", answerID, err)
+		}
+		w.rescreen(ctx, answer.ApplicationID)
+		return
+	}
+
+	delay := Backoff(int(answer.Attempts), gradeRetryBase, gradeRetryMax)
+	log.Printf("answer grader: answer %s attempt %d failed, retrying in %s: %v", answerID, answer.Attempts, delay, err)
+	if err := w.queries.RetryAnswerGrade(ctx, db.RetryAnswerGradeParams{
+		ID:    answer.ID,
+		RunAt: pgtype.Timestamptz{Time: time.Now().Add(delay), Valid: true},
+	}); err != nil {
+		log.Printf("answer grader: failed to requeue answer %s: %v", answerID, err)
+	}
+}
+
+// rescreen queues the application for Faye again now its answers changed
+func (w *AnswerGrader) rescreen(ctx context.Context, applicationID pgtype.UUID) {
+	if err := w.queries.EnqueueApplicationScreening(ctx, applicationID); err != nil {
+		log.Printf("answer grader: failed to queue screening: %v", err)
+	}
+}
+
+// grade looks up the rubric and job title for an answer and asks the model.
+// Answers whose question has since been deleted are graded without a rubric.
+func (w *AnswerGrader) grade(ctx context.Context, answer db.ApplicationAnswer) (*services.AnswerGrade, error) {
+	rubric := ""
+	if answer.QuestionID.Valid {
+		question, err := w.queries.GetScreeningQuestion(ctx, answer.QuestionID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		rubric = question.Rubric.String
+	}
+
+	jobTitle := ""
+	application, err := w.queries.GetApplicationByID(ctx, answer.ApplicationID)
+	if err != nil {
+		return nil, err
+	}
+	if job, err := w.queries.GetJobByID(ctx, application.JobID); err == nil {
+		jobTitle = job.Title
+	}
+
+	return w.grader.Grade(ctx, jobTitle, answer.QuestionPrompt, rubric, answer.Answer)
+}
+
+// reap puts answers abandoned by a crashed worker back on the queue
+func (w *AnswerGrader) reap(ctx context.Context) {
+	ticker := time.NewTicker(gradeReaperEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cutoff := pgtype.Timestamptz{Time: time.Now().Add(-gradeStaleAfter), Valid: true}
+			n, err := w.queries.RequeueStaleAnswerGrades(ctx, cutoff)
+			if err != nil {
+				log.Printf("answer grader: failed to requeue stale answers: %v", err)
+			} else if n > 0 {
+				log.Printf("answer grader: requeued %d stale answers", n)
+				w.Notify()
+			}
+		}
+	}
+}
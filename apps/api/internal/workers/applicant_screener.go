@@ -0,0 +1,205 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/aswinbala005/rizeos/api/internal/services"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	screenPollInterval = 5 * time.Second
+	screenStaleAfter   = 5 * time.Minute
+	screenRetryBase    = 10 * time.Second
+	screenRetryMax     = 10 * time.Minute
+	screenMaxAttempts  = 3
+	screenReaperEvery  = time.Minute
+)
+
+// ApplicantScreener is the server side of the Faye agent. It drains
+// application_screenings, summarising and triaging each applicant. Rows are
+// queued when an application arrives and again whenever its answers are
+// graded, so results follow the application as it changes.
+type ApplicantScreener struct {
+	queries  *db.Queries
+	screener services.LLMApplicantScreener
+	wake     chan struct{}
+}
+
+func NewApplicantScreener(queries *db.Queries, screener services.LLMApplicantScreener) *ApplicantScreener {
+	return &ApplicantScreener{
+		queries:  queries,
+		screener: screener,
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Notify wakes the worker so new screenings run without waiting for the
+// next poll.
+func (w *ApplicantScreener) Notify() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (w *ApplicantScreener) Run(ctx context.Context) {
+	// Applications that predate the screener (or were created while it was
+	// down) have no row yet
+	if n, err := w.queries.EnqueueMissingScreenings(ctx); err != nil {
+		log.Printf("applicant screener: failed to queue missing screenings: %v", err)
+	} else if n > 0 {
+		log.Printf("applicant screener: queued %d applications for screening", n)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.reap(ctx)
+	}()
+
+	for {
+		screening, err := w.queries.ClaimApplicationScreening(ctx)
+		if err == nil {
+			w.process(ctx, screening)
+			continue
+		}
+		if !errors.Is(err, pgx.ErrNoRows) && ctx.Err() == nil {
+			log.Printf("applicant screener: failed to claim screening: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			<-done
+			return
+		case <-w.wake:
+		case <-time.After(screenPollInterval):
+		}
+	}
+}
+
+func (w *ApplicantScreener) process(ctx context.Context, screening db.ApplicationScreening) {
+	appID := screening.ApplicationID.String()
+
+	input, err := w.input(ctx, screening.ApplicationID)
+	if err != nil {
+		w.retry(ctx, screening, err)
+		return
+	}
+
+	result, err := w.screener.Screen(ctx, *input)
+	if err != nil {
+		if screening.Attempts < screenMaxAttempts {
+			w.retry(ctx, screening, err)
+			return
+		}
+		// Out of retries: fall back to the heuristic so the applicant is
+		// still triaged, and keep the error for the recruiter to see
+		log.Printf("applicant screener: application %s falling back to heuristics after %d attempts: %v", appID, screening.Attempts, err)
+		result = services.ScreenApplicantHeuristically(*input)
+	}
+
+	lastError := pgtype.Text{}
+	if err != nil {
+		lastError = pgtype.Text{String: err.Error(), Valid: true}
+	}
+	if err := w.queries.CompleteApplicationScreening(ctx, db.CompleteApplicationScreeningParams{
+		ApplicationID: screening.ApplicationID,
+		Bucket:        pgtype.Text{String: result.Bucket, Valid: true},
+		Score:         pgtype.Int4{Int32: result.Score, Valid: true},
+		Summary:       pgtype.Text{String: result.Summary, Valid: true},
+		Strengths:     result.Strengths,
+		Gaps:          result.Gaps,
+		AnswerQuality: pgtype.Text{String: result.AnswerQuality, Valid: result.AnswerQuality != ""},
+		Source:        pgtype.Text{String: result.Source, Valid: true},
+		LastError:     lastError,
+	}); err != nil {
+		log.Printf("applicant screener: failed to store screening for application %s: %v", appID, err)
+	}
+}
+
+func (w *ApplicantScreener) retry(ctx context.Context, screening db.ApplicationScreening, cause error) {
+	delay := Backoff(int(screening.Attempts), screenRetryBase, screenRetryMax)
+	log.Printf("applicant screener: application %s attempt %d failed, retrying in %s: %v", screening.ApplicationID.String(), screening.Attempts, delay, cause)
+	if err := w.queries.RetryApplicationScreening(ctx, db.RetryApplicationScreeningParams{
+		ApplicationID: screening.ApplicationID,
+		RunAt:         pgtype.Timestamptz{Time: time.Now().Add(delay), Valid: true},
+		LastError:     pgtype.Text{String: cause.Error(), Valid: true},
+	}); err != nil {
+		log.Printf("applicant screener: failed to requeue application %s: %v", screening.ApplicationID.String(), err)
+	}
+}
+
+// input gathers the job, the candidate's profile and the screening answers
+func (w *ApplicantScreener) input(ctx context.Context, applicationID pgtype.UUID) (*services.ApplicantInput, error) {
+	row, err := w.queries.GetApplicationScreeningInput(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	answers, err := w.queries.ListApplicationAnswers(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	in := &services.ApplicantInput{
+		JobTitle:              row.JobTitle,
+		JobSummary:            row.JobSummary.String,
+		SkillsRequirements:    row.SkillsRequirements.String,
+		EducationRequirements: row.EducationRequirements.String,
+		ExperienceMin:         row.ExperienceMin.Int32,
+		ExperienceMax:         row.ExperienceMax.Int32,
+		CandidateName:         row.CandidateName.String,
+		CandidateRole:         row.CandidateRole.String,
+		CandidateBio:          row.CandidateBio.String,
+		CandidateSkills:       row.CandidateSkills.String,
+		CandidateExperience:   row.CandidateExperience.String,
+		CandidateEducation:    row.CandidateEducation.String,
+		MatchScore:            row.MatchScore.Int32,
+		RejectionReason:       row.RejectionReason.String,
+	}
+	if row.GatewayGrade.Valid {
+		grade := row.GatewayGrade.Int32
+		in.GatewayGrade = &grade
+	}
+	for _, a := range answers {
+		answer := services.ScreenedAnswer{
+			Question:  a.QuestionPrompt,
+			Answer:    a.Answer,
+			Pending:   a.GradeStatus == services.GradePending || a.GradeStatus == services.GradeRunning,
+			Rationale: a.Rationale.String,
+		}
+		if a.Grade.Valid {
+			grade := a.Grade.Int32
+			answer.Grade = &grade
+		}
+		in.Answers = append(in.Answers, answer)
+	}
+	return in, nil
+}
+
+// reap puts screenings abandoned by a crashed worker back on the queue
+func (w *ApplicantScreener) reap(ctx context.Context) {
+	ticker := time.NewTicker(screenReaperEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cutoff := pgtype.Timestamptz{Time: time.Now().Add(-screenStaleAfter), Valid: true}
+			n, err := w.queries.RequeueStaleScreenings(ctx, cutoff)
+			if err != nil {
+				log.Printf("applicant screener: failed to requeue stale screenings: %v", err)
+			} else if n > 0 {
+				log.Printf("applicant screener: requeued %d stale screenings", n)
+				w.Notify()
+			}
+		}
+	}
+}
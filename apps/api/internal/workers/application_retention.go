@@ -0,0 +1,57 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const retentionInterval = 24 * time.Hour
+
+// ApplicationRetention deletes withdrawn applications once they have been
+// withdrawn for retentionDays. Withdrawing only marks an application, so
+// this is where it finally goes away.
+type ApplicationRetention struct {
+	queries       *db.Queries
+	retentionDays int
+}
+
+func NewApplicationRetention(queries *db.Queries, retentionDays int) *ApplicationRetention {
+	return &ApplicationRetention{queries: queries, retentionDays: retentionDays}
+}
+
+func (w *ApplicationRetention) Run(ctx context.Context) {
+	if w.retentionDays == 0 {
+		log.Println("application retention: APPLICATION_RETENTION_DAYS is 0, keeping withdrawn applications")
+		<-ctx.Done()
+		return
+	}
+
+	ticker := time.NewTicker(retentionInterval)
+	defer ticker.Stop()
+	for {
+		w.purge(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *ApplicationRetention) purge(ctx context.Context) {
+	cutoff := time.Now().AddDate(0, 0, -w.retentionDays)
+	purged, err := w.queries.PurgeWithdrawnApplications(ctx, pgtype.Timestamptz{Time: cutoff, Valid: true})
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("application retention: failed to purge withdrawn applications: %v", err)
+		}
+		return
+	}
+	if purged > 0 {
+		log.Printf("application retention: deleted %d withdrawn applications", purged)
+	}
+}
@@ -0,0 +1,136 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/aswinbala005/rizeos/api/internal/services"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	emailPollInterval = 10 * time.Second
+	emailSendTimeout  = time.Minute
+	emailStaleAfter   = 10 * time.Minute
+	emailRetryBase    = 30 * time.Second
+	emailRetryMax     = time.Hour
+	emailMaxAttempts  = 6
+	emailReaperEvery  = time.Minute
+)
+
+// EmailSender drains the email outbox through the mailer, retrying with
+// backoff when sending fails.
+type EmailSender struct {
+	queries *db.Queries
+	mailer  services.Mailer
+	wake    chan struct{}
+}
+
+func NewEmailSender(queries *db.Queries, mailer services.Mailer) *EmailSender {
+	return &EmailSender{
+		queries: queries,
+		mailer:  mailer,
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Notify wakes the worker so freshly queued emails go out without waiting
+// for the next poll.
+func (w *EmailSender) Notify() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (w *EmailSender) Run(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.reap(ctx)
+	}()
+
+	for {
+		email, err := w.queries.ClaimEmail(ctx)
+		if err == nil {
+			w.process(ctx, email)
+			continue
+		}
+		if !errors.Is(err, pgx.ErrNoRows) && ctx.Err() == nil {
+			log.Printf("email sender: failed to claim email: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			<-done
+			return
+		case <-w.wake:
+		case <-time.After(emailPollInterval):
+		}
+	}
+}
+
+func (w *EmailSender) process(ctx context.Context, email db.EmailOutbox) {
+	emailID := email.ID.String()
+
+	sendCtx, cancel := context.WithTimeout(ctx, emailSendTimeout)
+	err := w.mailer.Send(sendCtx, services.Email{
+		To:             email.ToAddress,
+		Subject:        email.Subject,
+		Body:           email.Body,
+		Calendar:       email.Calendar.String,
+		CalendarMethod: email.CalendarMethod.String,
+	})
+	cancel()
+	if err == nil {
+		if err := w.queries.CompleteEmail(ctx, email.ID); err != nil {
+			log.Printf("email sender: failed to mark email %s as sent: %v", emailID, err)
+		}
+		return
+	}
+
+	lastError := pgtype.Text{String: err.Error(), Valid: true}
+	if email.Attempts >= emailMaxAttempts {
+		log.Printf("email sender: email %s failed permanently after %d attempts: %v", emailID, email.Attempts, err)
+		if err := w.queries.FailEmail(ctx, db.FailEmailParams{ID: email.ID, LastError: lastError}); err != nil {
+			log.Printf("email sender: failed to mark email %s as failed: %v", emailID, err)
+		}
+		return
+	}
+
+	delay := Backoff(int(email.Attempts), emailRetryBase, emailRetryMax)
+	log.Printf("email sender: email %s attempt %d failed, retrying in %s: %v", emailID, email.Attempts, delay, err)
+	if err := w.queries.RetryEmail(ctx, db.RetryEmailParams{
+		ID:        email.ID,
+		RunAt:     pgtype.Timestamptz{Time: time.Now().Add(delay), Valid: true},
+		LastError: lastError,
+	}); err != nil {
+		log.Printf("email sender: failed to requeue email %s: %v", emailID, err)
+	}
+}
+
+// reap puts emails abandoned by a crashed worker back on the queue
+func (w *EmailSender) reap(ctx context.Context) {
+	ticker := time.NewTicker(emailReaperEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cutoff := pgtype.Timestamptz{Time: time.Now().Add(-emailStaleAfter), Valid: true}
+			n, err := w.queries.RequeueStaleEmails(ctx, cutoff)
+			if err != nil {
+				log.Printf("email sender: failed to requeue stale emails: %v", err)
+			} else if n > 0 {
+				log.Printf("email sender: requeued %d stale emails", n)
+				w.Notify()
+			}
+		}
+	}
+}
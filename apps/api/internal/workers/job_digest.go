@@ -0,0 +1,166 @@
+package workers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/aswinbala005/rizeos/api/internal/services"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	digestCheckInterval = time.Hour
+	digestBatchSize     = 100
+	digestMaxJobs       = 5 // jobs listed in one notification
+)
+
+// JobDigest notifies candidates about jobs opened since their last alert:
+// one notification per saved search with alerts on, and one for jobs that
+// fit their preferences, at most every intervalDays. Each alert's clock is
+// moved when it is claimed, so several servers can run the digest at once
+// and a failed alert is skipped rather than repeated.
+type JobDigest struct {
+	queries      *db.Queries
+	intervalDays int32
+}
+
+func NewJobDigest(queries *db.Queries, intervalDays int) *JobDigest {
+	return &JobDigest{queries: queries, intervalDays: int32(intervalDays)}
+}
+
+func (w *JobDigest) Run(ctx context.Context) {
+	if w.intervalDays == 0 {
+		log.Println("job digest: JOB_DIGEST_DAYS is 0, not sending alerts")
+		<-ctx.Done()
+		return
+	}
+
+	ticker := time.NewTicker(digestCheckInterval)
+	defer ticker.Stop()
+	for {
+		w.sendSavedSearchAlerts(ctx)
+		w.sendPreferenceDigests(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *JobDigest) sendSavedSearchAlerts(ctx context.Context) {
+	for ctx.Err() == nil {
+		due, err := w.queries.ClaimSavedSearchAlerts(ctx, db.ClaimSavedSearchAlertsParams{
+			IntervalDays: w.intervalDays,
+			BatchSize:    digestBatchSize,
+		})
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("job digest: failed to claim saved searches: %v", err)
+			}
+			return
+		}
+		for _, search := range due {
+			var arg db.SearchJobsParams
+			if err := json.Unmarshal(search.Filters, &arg); err != nil {
+				log.Printf("job digest: saved search %s has unreadable filters: %v", search.ID.String(), err)
+				continue
+			}
+			if search.SalaryCurrency.Valid && !search.TargetRate.Valid {
+				log.Printf("job digest: saved search %s: no exchange rate for %s", search.ID.String(), search.SalaryCurrency.String)
+				continue
+			}
+			arg.TargetRate = search.TargetRate
+			arg.PostedSince = search.Since
+			arg.CursorCreatedAt, arg.CursorID = pgtype.Timestamptz{}, pgtype.UUID{}
+			arg.PageLimit = digestMaxJobs + 1
+			rows, err := w.queries.SearchJobs(ctx, arg)
+			if err != nil {
+				log.Printf("job digest: failed to run saved search %s: %v", search.ID.String(), err)
+				continue
+			}
+			if len(rows) == 0 {
+				continue
+			}
+			jobs := make([]services.AlertJob, 0, len(rows))
+			for _, row := range rows {
+				jobs = append(jobs, services.AlertJob{ID: row.ID.String(), Title: row.Title, Organization: row.OrganizationName.String, City: row.LocationCity.String})
+			}
+			more := len(jobs) > digestMaxJobs
+			if more {
+				jobs = jobs[:digestMaxJobs]
+			}
+			w.notify(ctx, search.UserID, services.SavedSearchAlert(search.ID.String(), search.Name, jobs, more))
+		}
+		if len(due) < digestBatchSize {
+			return
+		}
+	}
+}
+
+func (w *JobDigest) sendPreferenceDigests(ctx context.Context) {
+	for ctx.Err() == nil {
+		due, err := w.queries.ClaimJobPreferenceDigests(ctx, db.ClaimJobPreferenceDigestsParams{
+			IntervalDays: w.intervalDays,
+			BatchSize:    digestBatchSize,
+		})
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("job digest: failed to claim preference digests: %v", err)
+			}
+			return
+		}
+		for _, candidate := range due {
+			rows, err := w.queries.ListPreferredJobs(ctx, db.ListPreferredJobsParams{
+				CandidateID: candidate.UserID,
+				PostedSince: candidate.Since,
+			})
+			if err != nil {
+				log.Printf("job digest: failed to list jobs for %s: %v", candidate.UserID.String(), err)
+				continue
+			}
+			if len(rows) == 0 {
+				continue
+			}
+			// Jobs meeting more of their soft preferences first, then newest
+			sort.SliceStable(rows, func(i, j int) bool {
+				return preferenceMatches(rows[i]) > preferenceMatches(rows[j])
+			})
+			jobs := make([]services.AlertJob, 0, digestMaxJobs)
+			for _, row := range rows[:min(len(rows), digestMaxJobs)] {
+				jobs = append(jobs, services.AlertJob{ID: row.ID.String(), Title: row.Title, Organization: row.OrganizationName.String, City: row.LocationCity.String})
+			}
+			w.notify(ctx, candidate.UserID, services.PreferenceDigest(jobs, len(rows) > digestMaxJobs))
+		}
+		if len(due) < digestBatchSize {
+			return
+		}
+	}
+}
+
+func preferenceMatches(row db.ListPreferredJobsRow) int {
+	n := 0
+	if row.RoleMatch {
+		n++
+	}
+	if row.LocationMatch {
+		n++
+	}
+	return n
+}
+
+func (w *JobDigest) notify(ctx context.Context, userID pgtype.UUID, n services.Notice) {
+	if _, err := w.queries.CreateNotification(ctx, db.CreateNotificationParams{
+		UserID: userID,
+		Kind:   n.Kind,
+		Title:  n.Title,
+		Body:   n.Body,
+		Data:   n.Data,
+	}); err != nil {
+		log.Printf("job digest: failed to notify %s: %v", userID.String(), err)
+	}
+}
@@ -0,0 +1,88 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/aswinbala005/rizeos/api/internal/db"
+)
+
+// The scheduler sleeps until the next publish_at or expires_at, but never
+// longer than this, so schedules written by another instance are picked up
+const schedulePollInterval = time.Minute
+
+// JobScheduler opens DRAFT jobs once their publish_at passes and closes
+// OPEN jobs once their expires_at passes. Both updates are idempotent, so
+// several servers can run it side by side.
+type JobScheduler struct {
+	queries *db.Queries
+	wake    chan struct{}
+}
+
+func NewJobScheduler(queries *db.Queries) *JobScheduler {
+	return &JobScheduler{
+		queries: queries,
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Notify makes the scheduler re-read the schedule, after a job's
+// publish_at or expires_at has been set.
+func (w *JobScheduler) Notify() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (w *JobScheduler) Run(ctx context.Context) {
+	for {
+		w.tick(ctx)
+
+		timer := time.NewTimer(w.sleep(ctx))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-w.wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+func (w *JobScheduler) tick(ctx context.Context) {
+	if n, err := w.queries.PublishScheduledJobs(ctx); err != nil {
+		if ctx.Err() == nil {
+			log.Printf("job scheduler: failed to publish scheduled jobs: %v", err)
+		}
+	} else if n > 0 {
+		log.Printf("job scheduler: published %d jobs", n)
+	}
+
+	if n, err := w.queries.ExpireJobs(ctx); err != nil {
+		if ctx.Err() == nil {
+			log.Printf("job scheduler: failed to close expired jobs: %v", err)
+		}
+	} else if n > 0 {
+		log.Printf("job scheduler: closed %d expired jobs", n)
+	}
+}
+
+// sleep is how long to wait before the next tick
+func (w *JobScheduler) sleep(ctx context.Context) time.Duration {
+	next, err := w.queries.NextJobScheduleAt(ctx)
+	if err != nil || !next.Valid {
+		return schedulePollInterval
+	}
+	d := time.Until(next.Time)
+	if d < time.Second {
+		// Due now (or the clocks disagree slightly); don't spin
+		return time.Second
+	}
+	if d > schedulePollInterval {
+		return schedulePollInterval
+	}
+	return d
+}
@@ -0,0 +1,104 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/aswinbala005/rizeos/api/internal/services"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const sweepInterval = 15 * time.Minute
+
+// JobSweeper closes jobs nobody is looking after. An open job is closed
+// once it has been open for maxAgeDays or had no edits or applications for
+// inactiveDays, whichever comes first; its recruiter is warned warningDays
+// beforehand and can extend the job to restart both clocks. A job is never
+// closed less than warningDays after its warning, even if the deadline
+// passed while nobody was sweeping. Warnings are
+// recorded once per deadline and closing re-checks the status, so several
+// servers can sweep at once.
+type JobSweeper struct {
+	queries      *db.Queries
+	maxAgeDays   int32
+	inactiveDays int32
+	warningDays  int32
+}
+
+func NewJobSweeper(queries *db.Queries, maxAgeDays, inactiveDays, warningDays int) *JobSweeper {
+	return &JobSweeper{
+		queries:      queries,
+		maxAgeDays:   int32(maxAgeDays),
+		inactiveDays: int32(inactiveDays),
+		warningDays:  int32(warningDays),
+	}
+}
+
+func (w *JobSweeper) Run(ctx context.Context) {
+	if w.maxAgeDays == 0 && w.inactiveDays == 0 {
+		log.Println("job sweeper: JOB_MAX_AGE_DAYS and JOB_INACTIVITY_DAYS are 0, not sweeping")
+		<-ctx.Done()
+		return
+	}
+
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+	for {
+		w.sweep(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *JobSweeper) sweep(ctx context.Context) {
+	if w.warningDays > 0 {
+		warned, err := w.queries.WarnStaleJobs(ctx, db.WarnStaleJobsParams{
+			MaxAgeDays:   w.maxAgeDays,
+			InactiveDays: w.inactiveDays,
+			WarningDays:  w.warningDays,
+		})
+		if err != nil && ctx.Err() == nil {
+			log.Printf("job sweeper: failed to warn about stale jobs: %v", err)
+		}
+		for _, job := range warned {
+			w.notify(ctx, job.RecruiterID, services.StaleJobWarning(job.JobID.String(), job.Title, job.Reason.String, job.ClosesAt.Time))
+		}
+	}
+
+	closed, err := w.queries.CloseStaleJobs(ctx, db.CloseStaleJobsParams{
+		MaxAgeDays:   w.maxAgeDays,
+		InactiveDays: w.inactiveDays,
+		WarningDays:  w.warningDays,
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("job sweeper: failed to close stale jobs: %v", err)
+		}
+		return
+	}
+	if len(closed) > 0 {
+		log.Printf("job sweeper: closed %d stale jobs", len(closed))
+	}
+	for _, job := range closed {
+		w.notify(ctx, job.RecruiterID, services.StaleJobClosed(job.ID.String(), job.Title, job.CloseReason.String, job.Deadline.Time))
+	}
+}
+
+// notify is best effort: the job_events row is the record, the
+// notification only points the recruiter at it
+func (w *JobSweeper) notify(ctx context.Context, userID pgtype.UUID, n services.Notice) {
+	if _, err := w.queries.CreateNotification(ctx, db.CreateNotificationParams{
+		UserID: userID,
+		Kind:   n.Kind,
+		Title:  n.Title,
+		Body:   n.Body,
+		Data:   n.Data,
+	}); err != nil {
+		log.Printf("job sweeper: failed to notify %s: %v", userID.String(), err)
+	}
+}
@@ -0,0 +1,247 @@
+package workers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/aswinbala005/rizeos/api/internal/db"
+	"github.com/aswinbala005/rizeos/api/internal/services"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Resume parse job statuses, as stored in resume_parse_jobs.status
+const (
+	ParseJobQueued    = "QUEUED"
+	ParseJobRunning   = "RUNNING"
+	ParseJobSucceeded = "SUCCEEDED"
+	ParseJobFailed    = "FAILED"
+)
+
+const (
+	parsePollInterval = 2 * time.Second
+	parseStaleAfter   = 5 * time.Minute
+	parseHeartbeat    = time.Minute
+	parseRetryBase    = 5 * time.Second
+	parseRetryMax     = 5 * time.Minute
+	parseReaperEvery  = time.Minute
+)
+
+// ResumeParser drains the resume_parse_jobs queue: it downloads each PDF,
+// runs it through the AI parser and stores the result on the job row.
+type ResumeParser struct {
+	queries     *db.Queries
+	cache       *services.ResumeCache
+	events      *Events
+	concurrency int
+	wake        chan struct{}
+}
+
+func NewResumeParser(queries *db.Queries, cache *services.ResumeCache, concurrency int) *ResumeParser {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &ResumeParser{
+		queries:     queries,
+		cache:       cache,
+		events:      NewEvents(),
+		concurrency: concurrency,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// Events exposes job completion signals, keyed by job ID.
+func (p *ResumeParser) Events() *Events {
+	return p.events
+}
+
+// Notify wakes an idle worker so a freshly enqueued job is picked up
+// without waiting for the next poll.
+func (p *ResumeParser) Notify() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (p *ResumeParser) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < p.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.loop(ctx)
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		p.reap(ctx)
+	}()
+
+	wg.Wait()
+}
+
+func (p *ResumeParser) loop(ctx context.Context) {
+	for {
+		job, err := p.queries.ClaimResumeParseJob(ctx)
+		if err == nil {
+			p.process(ctx, job)
+			continue
+		}
+		if !errors.Is(err, pgx.ErrNoRows) && ctx.Err() == nil {
+			log.Printf("resume parser: failed to claim job: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.wake:
+		case <-time.After(parsePollInterval):
+		}
+	}
+}
+
+func (p *ResumeParser) process(ctx context.Context, job db.ResumeParseJob) {
+	jobID := job.ID.String()
+	defer p.events.Publish(jobID)
+
+	// Chunked AI parses can outlast parseStaleAfter, so keep the lock fresh
+	// while the job runs
+	stop := make(chan struct{})
+	go p.heartbeat(ctx, job.ID, stop)
+	data, err := p.parse(ctx, job)
+	close(stop)
+	if err == nil {
+		result, _ := json.Marshal(data)
+		err = p.queries.CompleteResumeParseJob(ctx, db.CompleteResumeParseJobParams{
+			ID:     job.ID,
+			Result: result,
+		})
+		if err != nil {
+			log.Printf("resume parser: failed to store result for job %s: %v", jobID, err)
+		}
+		return
+	}
+
+	lastError := pgtype.Text{String: err.Error(), Valid: true}
+	if job.Attempts >= job.MaxAttempts || errors.Is(err, services.ErrPDFUnavailable) {
+		log.Printf("resume parser: job %s failed permanently after %d attempts: %v", jobID, job.Attempts, err)
+		if err := p.queries.FailResumeParseJob(ctx, db.FailResumeParseJobParams{
+			ID:        job.ID,
+			LastError: lastError,
+		}); err != nil {
+			log.Printf("resume parser: failed to mark job %s as failed: %v", jobID, err)
+		}
+		return
+	}
+
+	delay := Backoff(int(job.Attempts), parseRetryBase, parseRetryMax)
+	log.Printf("resume parser: job %s attempt %d failed, retrying in %s: %v", jobID, job.Attempts, delay, err)
+	if err := p.queries.RetryResumeParseJob(ctx, db.RetryResumeParseJobParams{
+		ID:        job.ID,
+		LastError: lastError,
+		RunAt:     pgtype.Timestamptz{Time: time.Now().Add(delay), Valid: true},
+	}); err != nil {
+		log.Printf("resume parser: failed to requeue job %s: %v", jobID, err)
+	}
+}
+
+// heartbeat refreshes the job's locked_at until stop is closed
+func (p *ResumeParser) heartbeat(ctx context.Context, jobID pgtype.UUID, stop <-chan struct{}) {
+	ticker := time.NewTicker(parseHeartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := p.queries.TouchResumeParseJob(ctx, jobID); err != nil {
+				log.Printf("resume parser: failed to refresh lock on job %s: %v", jobID.String(), err)
+			}
+		}
+	}
+}
+
+// parse downloads the PDF and parses it with the job's parser, reusing
+// cached text and AI results for files that have been seen before
+func (p *ResumeParser) parse(ctx context.Context, job db.ResumeParseJob) (*services.ResumeData, error) {
+	pdfBytes, err := services.DownloadPDF(job.SourceUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := services.ContentHash(pdfBytes)
+	useAI := job.Parser != services.ResumeParserHeuristic
+	if useAI {
+		if data, ok := p.cache.GetParsed(ctx, hash); ok {
+			return data, nil
+		}
+	}
+
+	text, ok := p.cache.GetText(ctx, hash)
+	if !ok {
+		if text, err = services.ExtractTextFromPDFBytes(pdfBytes); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := services.NewResumeTextParser(job.Parser).Parse(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+
+	// Heuristic results are cheap to recompute and should not shadow a
+	// proper AI result once the provider is back
+	if data.Source == services.ResumeSourceAI {
+		if err := p.cache.Put(ctx, hash, text, data); err != nil {
+			log.Printf("resume parser: failed to cache result: %v", err)
+		}
+	}
+	return data, nil
+}
+
+// reap periodically puts jobs abandoned by a crashed worker back on the
+// queue, failing those that were on their last attempt, and drops expired
+// cache entries
+func (p *ResumeParser) reap(ctx context.Context) {
+	ticker := time.NewTicker(parseReaperEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cutoff := pgtype.Timestamptz{Time: time.Now().Add(-parseStaleAfter), Valid: true}
+			failed, err := p.queries.FailStaleResumeParseJobs(ctx, cutoff)
+			if err != nil {
+				log.Printf("resume parser: failed to fail stale jobs: %v", err)
+			}
+			for _, id := range failed {
+				log.Printf("resume parser: job %s failed permanently after its worker stopped on the last attempt", id.String())
+				p.events.Publish(id.String())
+			}
+
+			n, err := p.queries.RequeueStaleResumeParseJobs(ctx, cutoff)
+			if err != nil {
+				log.Printf("resume parser: failed to requeue stale jobs: %v", err)
+			} else if n > 0 {
+				log.Printf("resume parser: requeued %d stale jobs", n)
+				p.Notify()
+			}
+
+			if _, err := p.cache.Purge(ctx); err != nil {
+				log.Printf("resume parser: failed to purge cache: %v", err)
+			}
+		}
+	}
+}
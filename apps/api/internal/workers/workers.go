@@ -0,0 +1,75 @@
+package workers
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Worker is a long-running background process owned by the server.
+// Run must block until ctx is cancelled.
+type Worker interface {
+	Run(ctx context.Context)
+}
+
+// Backoff returns the delay before retry number `attempt` (1-based):
+// exponential from base, capped at max, with up to 20% jitter so that
+// jobs failing together do not retry together.
+func Backoff(attempt int, base, max time.Duration) time.Duration {
+	d := base
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d + time.Duration(rand.Int63n(int64(d)/5+1))
+}
+
+// Events fans out "something changed" signals keyed by an ID, so that
+// HTTP handlers can wait on background work without polling the database
+// in a tight loop.
+type Events struct {
+	mu   sync.Mutex
+	subs map[string]map[chan struct{}]struct{}
+}
+
+func NewEvents() *Events {
+	return &Events{subs: make(map[string]map[chan struct{}]struct{})}
+}
+
+// Subscribe returns a channel that receives a signal every time id is
+// published, and a function that must be called to unsubscribe.
+func (e *Events) Subscribe(id string) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	e.mu.Lock()
+	if e.subs[id] == nil {
+		e.subs[id] = make(map[chan struct{}]struct{})
+	}
+	e.subs[id][ch] = struct{}{}
+	e.mu.Unlock()
+
+	return ch, func() {
+		e.mu.Lock()
+		delete(e.subs[id], ch)
+		if len(e.subs[id]) == 0 {
+			delete(e.subs, id)
+		}
+		e.mu.Unlock()
+	}
+}
+
+// Publish signals every subscriber of id. It never blocks: a subscriber that
+// has not consumed its previous signal simply keeps the pending one.
+func (e *Events) Publish(id string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for ch := range e.subs[id] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,18 @@
+-- Queue of resume parsing work, drained by the background workers
+CREATE TABLE resume_parse_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source_url TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'QUEUED', -- QUEUED, RUNNING, SUCCEEDED, FAILED
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL DEFAULT 3,
+    result JSONB,
+    last_error TEXT,
+    run_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Workers only ever look for queued jobs that are due
+CREATE INDEX idx_resume_parse_jobs_due ON resume_parse_jobs(run_at) WHERE status = 'QUEUED';
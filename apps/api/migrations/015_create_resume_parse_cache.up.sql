@@ -0,0 +1,13 @@
+-- Parsed resumes keyed by the SHA-256 of the PDF bytes and the prompt/model
+-- version, so re-uploading the same file does not cost another AI call
+CREATE TABLE resume_parse_cache (
+    content_hash TEXT NOT NULL,
+    prompt_version TEXT NOT NULL,
+    extracted_text TEXT NOT NULL,
+    result JSONB NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (content_hash, prompt_version)
+);
+
+CREATE INDEX idx_resume_parse_cache_expires ON resume_parse_cache(expires_at);
@@ -0,0 +1,2 @@
+-- Which parser a job should use: 'auto' (AI with heuristic fallback), 'llm' or 'heuristic'
+ALTER TABLE resume_parse_jobs ADD COLUMN parser TEXT NOT NULL DEFAULT 'auto';
@@ -0,0 +1,31 @@
+-- Structured work history and education, replacing the free-text
+-- users.experience / users.education as the source of truth. The old
+-- columns are kept as derived summaries.
+CREATE TABLE work_positions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    company TEXT NOT NULL,
+    title TEXT NOT NULL,
+    start_date DATE,
+    end_date DATE, -- NULL means this is the current position
+    description TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_work_positions_user ON work_positions(user_id);
+-- Recruiters filter candidates by employer
+CREATE INDEX idx_work_positions_company ON work_positions(LOWER(company));
+
+CREATE TABLE education_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    institution TEXT NOT NULL,
+    degree TEXT,
+    field_of_study TEXT,
+    graduation_year INT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_education_entries_user ON education_entries(user_id);
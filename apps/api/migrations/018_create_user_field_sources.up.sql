@@ -0,0 +1,11 @@
+-- Where each profile field's current value came from: accepted from a
+-- resume parse (AI) or typed by the user (USER). Fields without a row
+-- predate tracking.
+CREATE TABLE user_field_sources (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    field TEXT NOT NULL,
+    source TEXT NOT NULL CHECK (source IN ('AI', 'USER')),
+    parse_job_id UUID REFERENCES resume_parse_jobs(id) ON DELETE SET NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, field)
+);
@@ -0,0 +1,43 @@
+-- Screening questions a recruiter attaches to a job, replacing the single
+-- jobs.gateway_question dropped in 013
+CREATE TABLE job_screening_questions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('FREE_TEXT', 'MULTIPLE_CHOICE', 'YES_NO')),
+    prompt TEXT NOT NULL,
+    options TEXT[] NOT NULL DEFAULT '{}', -- MULTIPLE_CHOICE only
+    required BOOLEAN NOT NULL DEFAULT TRUE,
+    knockout_answer TEXT, -- an answer that rejects the application outright
+    rubric TEXT, -- FREE_TEXT only: what a good answer looks like
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_job_screening_questions_job ON job_screening_questions(job_id, position);
+
+-- A candidate's answers. The question text is copied so answers survive the
+-- recruiter editing or removing the question later.
+CREATE TABLE application_answers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    question_id UUID REFERENCES job_screening_questions(id) ON DELETE SET NULL,
+    question_prompt TEXT NOT NULL,
+    answer TEXT NOT NULL,
+    grade_status TEXT NOT NULL DEFAULT 'NONE', -- NONE, PENDING, RUNNING, GRADED, FAILED
+    grade INT, -- 0-100
+    rationale TEXT,
+    attempts INT NOT NULL DEFAULT 0,
+    run_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_at TIMESTAMPTZ,
+    graded_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (application_id, question_id)
+);
+
+-- The grading worker only looks for pending answers that are due
+CREATE INDEX idx_application_answers_pending ON application_answers(run_at) WHERE grade_status = 'PENDING';
+
+-- Average grade of the graded free-text answers, and why an application
+-- was rejected automatically
+ALTER TABLE applications ADD COLUMN gateway_grade INT;
+ALTER TABLE applications ADD COLUMN rejection_reason TEXT;
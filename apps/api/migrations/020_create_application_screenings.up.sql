@@ -0,0 +1,26 @@
+-- Faye's per-applicant screening: a short summary, strengths, gaps against
+-- the job's skills_requirements, an answer-quality note and a triage bucket.
+-- One row per application, re-queued whenever its inputs change.
+CREATE TABLE application_screenings (
+    application_id UUID PRIMARY KEY REFERENCES applications(id) ON DELETE CASCADE,
+    job_id UUID NOT NULL REFERENCES jobs(id),
+    status TEXT NOT NULL DEFAULT 'PENDING', -- PENDING, RUNNING, DONE
+    bucket TEXT, -- HIGH_SIGNAL, POTENTIAL_FIT, LOW_SIGNAL
+    score INT, -- 0-100
+    summary TEXT,
+    strengths TEXT[] NOT NULL DEFAULT '{}',
+    gaps TEXT[] NOT NULL DEFAULT '{}',
+    answer_quality TEXT,
+    source TEXT, -- ai or heuristic
+    last_error TEXT,
+    attempts INT NOT NULL DEFAULT 0,
+    requested_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    run_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_at TIMESTAMPTZ,
+    screened_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_application_screenings_job ON application_screenings(job_id);
+CREATE INDEX idx_application_screenings_pending ON application_screenings(run_at) WHERE status = 'PENDING';
@@ -0,0 +1,10 @@
+-- Fields the Tracer candidate search filters on. experience_years is read
+-- out of the free-text experience summary ("3 Years", "5+ yrs"); anything
+-- without a number, such as "Intern", counts as 0.
+ALTER TABLE users ADD COLUMN location TEXT;
+ALTER TABLE users ADD COLUMN experience_years INT GENERATED ALWAYS AS (
+    COALESCE(substring(lower(experience) from '(\d+)\s*\+?\s*(?:years?|yrs?)')::int, 0)
+) STORED;
+
+CREATE INDEX idx_users_candidate_location ON users (lower(location) text_pattern_ops) WHERE role = 'CANDIDATE';
+CREATE INDEX idx_users_candidate_experience ON users (experience_years) WHERE role = 'CANDIDATE';
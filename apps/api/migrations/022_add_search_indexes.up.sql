@@ -0,0 +1,25 @@
+-- Persistent search vectors, weighted so a match in the role or title
+-- outranks one buried in a bio or description
+ALTER TABLE users ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', COALESCE(job_role, '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE(skills, '')), 'B') ||
+    setweight(to_tsvector('english', COALESCE(full_name, '')), 'C') ||
+    setweight(to_tsvector('english', COALESCE(bio, '')), 'D')
+) STORED;
+
+ALTER TABLE jobs ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE(skills_requirements, '')), 'B') ||
+    setweight(to_tsvector('english', COALESCE(job_summary, '')), 'C') ||
+    setweight(to_tsvector('english', COALESCE(description, '')), 'D')
+) STORED;
+
+CREATE INDEX idx_users_search_vector ON users USING GIN (search_vector) WHERE role = 'CANDIDATE';
+CREATE INDEX idx_jobs_search_vector ON jobs USING GIN (search_vector) WHERE status = 'OPEN';
+
+-- Trigram indexes (pg_trgm, enabled in 001) serve fuzzy matching with % and
+-- similarity(), and ILIKE '%..%' substring filters
+CREATE INDEX idx_users_full_name_trgm ON users USING GIN (full_name gin_trgm_ops) WHERE role = 'CANDIDATE';
+CREATE INDEX idx_users_skills_trgm ON users USING GIN (skills gin_trgm_ops) WHERE role = 'CANDIDATE';
+CREATE INDEX idx_users_job_role_trgm ON users USING GIN (job_role gin_trgm_ops) WHERE role = 'CANDIDATE';
+CREATE INDEX idx_jobs_title_trgm ON jobs USING GIN (title gin_trgm_ops) WHERE status = 'OPEN';
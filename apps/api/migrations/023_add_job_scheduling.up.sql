@@ -0,0 +1,12 @@
+-- Drafts and scheduled publishing. A DRAFT with a publish_at is opened by the
+-- job scheduler once that time passes; an OPEN job with an expires_at is
+-- closed the same way.
+UPDATE jobs SET status = 'OPEN' WHERE status IS NULL;
+ALTER TABLE jobs ADD CONSTRAINT jobs_status_check CHECK (status IN ('DRAFT', 'OPEN', 'CLOSED'));
+
+ALTER TABLE jobs ADD COLUMN publish_at TIMESTAMPTZ;
+ALTER TABLE jobs ADD COLUMN expires_at TIMESTAMPTZ;
+ALTER TABLE jobs ADD CONSTRAINT jobs_schedule_check CHECK (publish_at IS NULL OR expires_at IS NULL OR expires_at > publish_at);
+
+CREATE INDEX idx_jobs_publish_at ON jobs (publish_at) WHERE status = 'DRAFT' AND publish_at IS NOT NULL;
+CREATE INDEX idx_jobs_expires_at ON jobs (expires_at) WHERE status = 'OPEN' AND expires_at IS NOT NULL;
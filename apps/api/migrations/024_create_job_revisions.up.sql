@@ -0,0 +1,90 @@
+-- Every version of a job, written by a trigger so no code path can edit a
+-- job without leaving a revision behind. snapshot holds the fields listed
+-- in job_revision_fields; changed_by is the recruiter for the first
+-- revision and jobs.updated_by after that (NULL for system changes such as
+-- scheduled publishing).
+ALTER TABLE jobs ADD COLUMN updated_by UUID REFERENCES users(id);
+
+CREATE TABLE job_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    revision INT NOT NULL,
+    snapshot JSONB NOT NULL,
+    changed_by UUID REFERENCES users(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (job_id, revision)
+);
+
+-- The fields a recruiter edits, and so the only ones a revision records.
+-- Columns the system keeps (search vectors, sweeper clocks, geocoding)
+-- stay out without being named; a migration adding an editable column
+-- registers it here.
+CREATE TABLE job_revision_fields (
+    name TEXT PRIMARY KEY
+);
+
+INSERT INTO job_revision_fields (name) VALUES
+    ('title'), ('description'), ('job_summary'), ('education_requirements'), ('skills_requirements'),
+    ('experience_min'), ('experience_max'), ('is_paid'), ('is_unpaid'), ('salary_min'), ('salary_max'),
+    ('currency'), ('job_type'), ('location_type'), ('location_city'), ('recruiter_email'),
+    ('status'), ('publish_at'), ('expires_at');
+
+CREATE FUNCTION job_snapshot(job jobs) RETURNS JSONB AS $$
+    SELECT COALESCE(jsonb_object_agg(f.key, f.value), '{}')
+    FROM jsonb_each(to_jsonb(job)) AS f
+    WHERE f.key IN (SELECT name FROM job_revision_fields);
+$$ LANGUAGE sql STABLE;
+
+-- Existing jobs start at revision 1, as they are now
+INSERT INTO job_revisions (job_id, revision, snapshot, created_at)
+SELECT id, 1, job_snapshot(jobs), updated_at
+FROM jobs;
+
+CREATE FUNCTION record_job_revision() RETURNS trigger AS $$
+DECLARE
+    snap JSONB := job_snapshot(NEW);
+    last_snap JSONB;
+    last_revision INT;
+BEGIN
+    -- Concurrent updates of one job are serialised by its row lock, so the
+    -- next revision number cannot be taken twice
+    SELECT snapshot, revision INTO last_snap, last_revision
+    FROM job_revisions WHERE job_id = NEW.id
+    ORDER BY revision DESC LIMIT 1;
+
+    -- Updates that touch no listed field are not revisions
+    IF last_snap IS NOT DISTINCT FROM snap THEN
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO job_revisions (job_id, revision, snapshot, changed_by)
+    VALUES (
+        NEW.id,
+        COALESCE(last_revision, 0) + 1,
+        snap,
+        CASE WHEN TG_OP = 'INSERT' THEN NEW.recruiter_id ELSE NEW.updated_by END
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER jobs_record_revision
+AFTER INSERT OR UPDATE ON jobs
+FOR EACH ROW EXECUTE FUNCTION record_job_revision();
+
+-- Revisions are history: they can go with their job, but never change
+CREATE FUNCTION reject_job_revision_update() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'job revisions are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER job_revisions_immutable
+BEFORE UPDATE ON job_revisions
+FOR EACH ROW EXECUTE FUNCTION reject_job_revision_update();
+
+-- The version of the job a candidate saw when they applied. NULL for
+-- applications made before revisions were recorded.
+ALTER TABLE applications ADD COLUMN job_revision INT;
+ALTER TABLE applications ADD CONSTRAINT applications_job_revision_fkey
+    FOREIGN KEY (job_id, job_revision) REFERENCES job_revisions (job_id, revision);
@@ -0,0 +1,52 @@
+-- Stale-job sweeping. A job's age is counted from when it was posted (or
+-- last extended); its inactivity from its last edit, application or
+-- extension. close_reason says why a job was closed: MANUAL, EXPIRED
+-- (expires_at passed), MAX_AGE or INACTIVE.
+ALTER TABLE jobs ADD COLUMN close_reason TEXT;
+ALTER TABLE jobs ADD COLUMN extended_at TIMESTAMPTZ;
+
+-- Neither joins job_revision_fields: close_reason goes with status, which
+-- is already in the snapshot, and extending only restarts the sweeper's
+-- clocks
+UPDATE jobs SET close_reason = 'MANUAL' WHERE status = 'CLOSED';
+
+-- The clocks the sweeper reads, for OPEN jobs
+CREATE VIEW job_activity AS
+SELECT j.id AS job_id,
+       j.recruiter_id,
+       j.title,
+       GREATEST(j.created_at, j.publish_at, j.extended_at)::timestamptz AS age_anchor,
+       GREATEST(j.updated_at, j.extended_at, (SELECT MAX(a.created_at) FROM applications a WHERE a.job_id = j.id))::timestamptz AS activity_anchor
+FROM jobs j
+WHERE j.status = 'OPEN';
+
+-- Lifecycle history for the recruiter dashboard: PUBLISHED, CLOSED,
+-- REOPENED, EXTENDED, STALE_WARNING and AUTO_CLOSED (with the close_reason
+-- as reason). actor_id is NULL for the scheduler and the sweeper.
+CREATE TABLE job_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    reason TEXT,
+    deadline TIMESTAMPTZ,
+    actor_id UUID REFERENCES users(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_job_events_job ON job_events(job_id, created_at DESC);
+-- One warning per deadline; a new deadline (after activity) warns again
+CREATE UNIQUE INDEX idx_job_events_warning ON job_events(job_id, deadline) WHERE kind = 'STALE_WARNING';
+
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    title TEXT NOT NULL,
+    body TEXT NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    read_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_notifications_user ON notifications(user_id, created_at DESC);
+CREATE INDEX idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
@@ -0,0 +1,37 @@
+-- Salaries become comparable across jobs: each job says which period its
+-- salary_min/salary_max cover, generated columns hold the yearly amounts,
+-- and exchange_rates converts between currencies at query time. Existing
+-- jobs were entered as yearly amounts.
+ALTER TABLE jobs ADD COLUMN pay_period TEXT NOT NULL DEFAULT 'YEAR'
+    CHECK (pay_period IN ('HOUR', 'DAY', 'WEEK', 'MONTH', 'YEAR'));
+
+-- Full-time equivalents: 40 hours a week, 5 days a week, 52 weeks a year.
+-- Keep in step with services.PayPeriodsPerYear.
+ALTER TABLE jobs
+    ADD COLUMN salary_min_yearly BIGINT GENERATED ALWAYS AS (salary_min::bigint * CASE pay_period
+        WHEN 'HOUR' THEN 2080 WHEN 'DAY' THEN 260 WHEN 'WEEK' THEN 52 WHEN 'MONTH' THEN 12 ELSE 1 END) STORED,
+    ADD COLUMN salary_max_yearly BIGINT GENERATED ALWAYS AS (salary_max::bigint * CASE pay_period
+        WHEN 'HOUR' THEN 2080 WHEN 'DAY' THEN 260 WHEN 'WEEK' THEN 52 WHEN 'MONTH' THEN 12 ELSE 1 END) STORED;
+
+CREATE INDEX idx_jobs_salary_max_yearly ON jobs (salary_max_yearly) WHERE status = 'OPEN';
+
+-- How many units of each currency buy one US dollar. Loaded from a file by
+-- cmd/fxrates; USD itself is always 1.
+CREATE TABLE exchange_rates (
+    currency TEXT PRIMARY KEY CHECK (currency ~ '^[A-Z]{3}$'),
+    units_per_usd NUMERIC(24, 10) NOT NULL CHECK (units_per_usd > 0),
+    source TEXT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO exchange_rates (currency, units_per_usd, source) VALUES ('USD', 1, 'base');
+
+-- pay_period is an edit; the yearly amounts are derived from it and the
+-- salaries, so they stay out. Existing revisions were yearly too: carrying
+-- that into their snapshots keeps the next update of an old job from
+-- reading as a pay_period change.
+INSERT INTO job_revision_fields (name) VALUES ('pay_period');
+
+ALTER TABLE job_revisions DISABLE TRIGGER job_revisions_immutable;
+UPDATE job_revisions SET snapshot = snapshot || '{"pay_period": "YEAR"}' WHERE NOT snapshot ? 'pay_period';
+ALTER TABLE job_revisions ENABLE TRIGGER job_revisions_immutable;
@@ -0,0 +1,124 @@
+-- A gazetteer of places with coordinates, loaded from a file by
+-- cmd/gazetteer. Jobs and users are geocoded by trigger whenever their
+-- location text changes, so "Bangalore" and "Bengaluru" land on the same
+-- place and searches can ask for everything within a radius.
+CREATE TABLE locations (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    country_code TEXT NOT NULL CHECK (country_code ~ '^[A-Z]{2}$'),
+    admin1 TEXT NOT NULL DEFAULT '', -- state or province
+    latitude DOUBLE PRECISION NOT NULL CHECK (latitude BETWEEN -90 AND 90),
+    longitude DOUBLE PRECISION NOT NULL CHECK (longitude BETWEEN -180 AND 180),
+    population BIGINT NOT NULL DEFAULT 0,
+    UNIQUE (country_code, admin1, name)
+);
+
+-- Every spelling a place is known by, including its own name, as
+-- location_key() normalises it. When several places share an alias the
+-- most populous wins.
+CREATE TABLE location_aliases (
+    alias_key TEXT NOT NULL,
+    location_id INT NOT NULL REFERENCES locations(id) ON DELETE CASCADE,
+    PRIMARY KEY (alias_key, location_id)
+);
+CREATE INDEX idx_location_aliases_prefix ON location_aliases (alias_key text_pattern_ops);
+
+-- The city part of free text such as "Bengaluru, Karnataka", lower-cased
+-- with punctuation collapsed to single spaces
+CREATE FUNCTION location_key(place TEXT) RETURNS TEXT AS $$
+    SELECT NULLIF(btrim(regexp_replace(lower(split_part(place, ',', 1)), '[^[:alnum:]]+', ' ', 'g')), '')
+$$ LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE;
+
+CREATE FUNCTION resolve_location(place TEXT) RETURNS INT AS $$
+    SELECT l.id
+    FROM location_aliases a
+    JOIN locations l ON l.id = a.location_id
+    WHERE a.alias_key = location_key(place)
+    ORDER BY l.population DESC, l.id
+    LIMIT 1
+$$ LANGUAGE sql STABLE STRICT PARALLEL SAFE;
+
+-- Great-circle distance in kilometres (haversine)
+CREATE FUNCTION distance_km(lat1 DOUBLE PRECISION, lng1 DOUBLE PRECISION, lat2 DOUBLE PRECISION, lng2 DOUBLE PRECISION)
+RETURNS DOUBLE PRECISION AS $$
+    SELECT 2 * 6371.0088 * asin(LEAST(1, sqrt(
+        power(sin(radians(lat2 - lat1) / 2), 2) +
+        cos(radians(lat1)) * cos(radians(lat2)) * power(sin(radians(lng2 - lng1) / 2), 2))))
+$$ LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE;
+
+-- location_type was free text; keep the three values the app offers.
+-- Anything the patterns below do not place stops the migration, listing
+-- the values, so each one is reviewed and added here rather than guessed.
+CREATE FUNCTION pg_temp.normalise_location_type(t TEXT) RETURNS TEXT AS $$
+    SELECT CASE
+        WHEN lower(t) ~ '(hybrid|flex|mixed|partly|partial)' THEN 'Hybrid'
+        WHEN lower(t) ~ '(remote|wfh|from home|anywhere|distributed)' THEN 'Remote'
+        WHEN lower(t) ~ '(office|site|in person|in-person)' THEN 'In-Office'
+    END
+$$ LANGUAGE sql IMMUTABLE;
+
+DO $$
+DECLARE
+    unknown TEXT;
+BEGIN
+    SELECT string_agg(DISTINCT quote_literal(location_type), ', ') INTO unknown
+    FROM jobs
+    WHERE location_type IS NOT NULL AND pg_temp.normalise_location_type(location_type) IS NULL;
+    IF unknown IS NOT NULL THEN
+        RAISE EXCEPTION 'unrecognised jobs.location_type values: %', unknown;
+    END IF;
+END;
+$$;
+
+-- Tidying the spelling is not an edit by the job's last editor. Past
+-- snapshots get the same spelling, so the next real edit of such a job
+-- does not show the tidy-up among its changes either.
+ALTER TABLE jobs DISABLE TRIGGER jobs_record_revision;
+UPDATE jobs SET location_type = pg_temp.normalise_location_type(location_type)
+WHERE location_type IS NOT NULL AND location_type NOT IN ('Remote', 'Hybrid', 'In-Office');
+ALTER TABLE jobs ENABLE TRIGGER jobs_record_revision;
+
+ALTER TABLE job_revisions DISABLE TRIGGER job_revisions_immutable;
+UPDATE job_revisions
+SET snapshot = jsonb_set(snapshot, '{location_type}', to_jsonb(pg_temp.normalise_location_type(snapshot->>'location_type')))
+WHERE snapshot->>'location_type' NOT IN ('Remote', 'Hybrid', 'In-Office')
+  AND pg_temp.normalise_location_type(snapshot->>'location_type') IS NOT NULL;
+ALTER TABLE job_revisions ENABLE TRIGGER job_revisions_immutable;
+
+ALTER TABLE jobs ADD CONSTRAINT jobs_location_type_check
+    CHECK (location_type IN ('Remote', 'Hybrid', 'In-Office'));
+
+ALTER TABLE jobs ADD COLUMN location_id INT REFERENCES locations(id) ON DELETE SET NULL;
+ALTER TABLE users ADD COLUMN location_id INT REFERENCES locations(id) ON DELETE SET NULL;
+CREATE INDEX idx_jobs_location_id ON jobs (location_id);
+CREATE INDEX idx_users_location_id ON users (location_id) WHERE role = 'CANDIDATE';
+
+-- Remote jobs have no place. A job that resolves gets the place's name in
+-- location_name, so the city facet groups spellings together; location_city
+-- keeps what the recruiter typed, as users' location does. location_id and
+-- location_name follow from location_city and change when the gazetteer is
+-- reloaded, so neither joins job_revision_fields.
+ALTER TABLE jobs ADD COLUMN location_name TEXT;
+
+CREATE FUNCTION geocode_job() RETURNS trigger AS $$
+BEGIN
+    NEW.location_id := CASE WHEN NEW.location_type = 'Remote' THEN NULL ELSE resolve_location(NEW.location_city) END;
+    NEW.location_name := (SELECT name FROM locations WHERE id = NEW.location_id);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER jobs_geocode
+BEFORE INSERT OR UPDATE OF location_city, location_type ON jobs
+FOR EACH ROW EXECUTE FUNCTION geocode_job();
+
+CREATE FUNCTION geocode_user() RETURNS trigger AS $$
+BEGIN
+    NEW.location_id := resolve_location(NEW.location);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER users_geocode
+BEFORE INSERT OR UPDATE OF location ON users
+FOR EACH ROW EXECUTE FUNCTION geocode_user();
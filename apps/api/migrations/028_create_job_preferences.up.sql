@@ -0,0 +1,72 @@
+-- What a candidate is looking for. remote_only, job_types and the minimum
+-- salary are hard filters on their job list; roles and locations only
+-- rank matching jobs higher. With alerts on, the job digest notifies them
+-- about new jobs that fit.
+CREATE TABLE job_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    roles TEXT[] NOT NULL DEFAULT '{}',
+    locations TEXT[] NOT NULL DEFAULT '{}',
+    radius_km INT NOT NULL DEFAULT 50 CHECK (radius_km > 0),
+    remote_only BOOLEAN NOT NULL DEFAULT FALSE,
+    job_types TEXT[] NOT NULL DEFAULT '{}',
+    min_salary INT CHECK (min_salary >= 0),
+    salary_currency TEXT CHECK (salary_currency ~ '^[A-Z]{3}$'),
+    pay_period TEXT NOT NULL DEFAULT 'YEAR'
+        CHECK (pay_period IN ('HOUR', 'DAY', 'WEEK', 'MONTH', 'YEAR')),
+    -- Keep in step with jobs.salary_min_yearly
+    min_salary_yearly BIGINT GENERATED ALWAYS AS (min_salary::bigint * CASE pay_period
+        WHEN 'HOUR' THEN 2080 WHEN 'DAY' THEN 260 WHEN 'WEEK' THEN 52 WHEN 'MONTH' THEN 12 ELSE 1 END) STORED,
+    alerts BOOLEAN NOT NULL DEFAULT FALSE,
+    last_notified_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (min_salary IS NULL OR salary_currency IS NOT NULL)
+);
+
+CREATE INDEX idx_job_preferences_alerts ON job_preferences (last_notified_at) WHERE alerts;
+
+-- A /jobs/search query a candidate wants to come back to. query is the
+-- query string as the client sent it; filters holds it parsed, with the
+-- place it names already looked up, ready to run. Exchange rates move, so
+-- salary_currency is converted at the rate of the day each alert runs.
+CREATE TABLE saved_searches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    query TEXT NOT NULL,
+    filters JSONB NOT NULL,
+    salary_currency TEXT CHECK (salary_currency ~ '^[A-Z]{3}$'),
+    alerts BOOLEAN NOT NULL DEFAULT TRUE,
+    last_notified_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_saved_searches_user ON saved_searches (user_id, created_at DESC);
+CREATE INDEX idx_saved_searches_alerts ON saved_searches (last_notified_at) WHERE alerts;
+
+-- When a job was first opened, whether it was created OPEN, published by
+-- the scheduler or edited out of DRAFT. Alerts and the posted_since filter
+-- go by it, since a draft can open long after it was created.
+ALTER TABLE jobs ADD COLUMN published_at TIMESTAMPTZ;
+
+CREATE FUNCTION stamp_job_published() RETURNS trigger AS $$
+BEGIN
+    IF NEW.status = 'OPEN' AND NEW.published_at IS NULL THEN
+        NEW.published_at := NOW();
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER jobs_stamp_published
+BEFORE INSERT OR UPDATE OF status ON jobs
+FOR EACH ROW EXECUTE FUNCTION stamp_job_published();
+
+-- Jobs opened before now: the scheduled time if they had one, else when
+-- they were created. published_at is bookkeeping and not in
+-- job_revision_fields; the trigger is off anyway so the backfill can never
+-- be credited to a job's last editor.
+ALTER TABLE jobs DISABLE TRIGGER jobs_record_revision;
+UPDATE jobs SET published_at = COALESCE(publish_at, created_at) WHERE status <> 'DRAFT';
+ALTER TABLE jobs ENABLE TRIGGER jobs_record_revision;
@@ -0,0 +1,39 @@
+-- A candidate has one application per job. Existing duplicates (mostly
+-- double-clicked Apply buttons) are deleted, keeping one row per job and
+-- candidate: one still in progress over a rejected one, then the newest.
+-- The deleted rows' answers and screening go with them.
+WITH ranked AS (
+    SELECT id, ROW_NUMBER() OVER (
+        PARTITION BY job_id, candidate_id
+        ORDER BY (status = 'REJECTED'), created_at DESC, id DESC
+    ) AS rank
+    FROM applications
+)
+DELETE FROM applications a
+USING ranked r
+WHERE a.id = r.id AND r.rank > 1;
+
+ALTER TABLE applications ADD CONSTRAINT applications_job_candidate_key UNIQUE (job_id, candidate_id);
+
+-- The unique index leads with job_id, so it serves lookups by job too
+DROP INDEX IF EXISTS idx_applications_job;
+
+-- closed_at is when the application was rejected or withdrawn, and starts
+-- the candidate's re-apply cooldown. Re-applying reopens the same row and
+-- counts it in reapply_count.
+ALTER TABLE applications ADD COLUMN closed_at TIMESTAMPTZ;
+ALTER TABLE applications ADD COLUMN reapplied_at TIMESTAMPTZ;
+ALTER TABLE applications ADD COLUMN reapply_count INT NOT NULL DEFAULT 0;
+
+UPDATE applications SET closed_at = updated_at WHERE status = 'REJECTED';
+
+-- A re-application is fresh activity on the job, though its row is old
+CREATE OR REPLACE VIEW job_activity AS
+SELECT j.id AS job_id,
+       j.recruiter_id,
+       j.title,
+       GREATEST(j.created_at, j.publish_at, j.extended_at)::timestamptz AS age_anchor,
+       GREATEST(j.updated_at, j.extended_at,
+                (SELECT MAX(GREATEST(a.created_at, a.reapplied_at)) FROM applications a WHERE a.job_id = j.id))::timestamptz AS activity_anchor
+FROM jobs j
+WHERE j.status = 'OPEN';
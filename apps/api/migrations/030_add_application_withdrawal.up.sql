@@ -0,0 +1,7 @@
+-- Withdrawing an application keeps it, as WITHDRAWN, so the recruiter's
+-- pipeline and the application's history stay intact. closed_at is set
+-- too, which starts the re-apply cooldown.
+ALTER TABLE applications ADD COLUMN withdrawn_at TIMESTAMPTZ;
+ALTER TABLE applications ADD COLUMN withdrawal_reason TEXT;
+
+CREATE INDEX idx_applications_withdrawn ON applications (withdrawn_at) WHERE status = 'WITHDRAWN';
@@ -0,0 +1,64 @@
+-- What recruiters record while reviewing applicants. None of it is shown
+-- to candidates. Reviewers are the job's recruiter and recruiters from the
+-- same organization.
+
+-- Private notes. mentions are the reviewers the note was addressed to;
+-- they are notified when it is posted.
+CREATE TABLE application_notes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    mentions UUID[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_application_notes_application ON application_notes (application_id, created_at);
+
+-- Free-form tags, stored lowercased
+CREATE TABLE application_tags (
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    tag TEXT NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (application_id, tag)
+);
+
+CREATE INDEX idx_application_tags_tag ON application_tags (tag);
+
+-- The criteria a job's interview scorecards rate, in order
+CREATE TABLE job_scorecard_criteria (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_job_scorecard_criteria_job ON job_scorecard_criteria (job_id, position);
+
+-- One scorecard per reviewer per application, with an overall
+-- recommendation
+CREATE TABLE application_scorecards (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    reviewer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    recommendation TEXT NOT NULL
+        CHECK (recommendation IN ('STRONG_NO', 'NO', 'YES', 'STRONG_YES')),
+    summary TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (application_id, reviewer_id)
+);
+
+-- A scorecard's 1-5 rating per criterion. The criterion's name is copied,
+-- like screening answers copy their question, so ratings outlive the job's
+-- criteria being replaced.
+CREATE TABLE scorecard_ratings (
+    scorecard_id UUID NOT NULL REFERENCES application_scorecards(id) ON DELETE CASCADE,
+    criterion_id UUID REFERENCES job_scorecard_criteria(id) ON DELETE SET NULL,
+    criterion TEXT NOT NULL,
+    rating INT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    PRIMARY KEY (scorecard_id, criterion)
+);
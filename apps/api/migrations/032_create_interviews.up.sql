@@ -0,0 +1,64 @@
+-- Interviews for an application. A recruiter proposes slots, the candidate
+-- picks one and everyone gets a calendar invite. uid and sequence are the
+-- iCalendar UID and SEQUENCE: the UID never changes, so calendars update
+-- the same event, and sequence goes up with every reschedule or
+-- cancellation sent after the first invite.
+CREATE TABLE interviews (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    organizer_id UUID NOT NULL REFERENCES users(id),
+    title TEXT NOT NULL,
+    duration_minutes INT NOT NULL CHECK (duration_minutes BETWEEN 5 AND 480),
+    timezone TEXT NOT NULL,
+    location TEXT,
+    video_url TEXT,
+    status TEXT NOT NULL DEFAULT 'PROPOSED'
+        CHECK (status IN ('PROPOSED', 'SCHEDULED', 'CANCELLED')),
+    starts_at TIMESTAMPTZ,
+    uid TEXT NOT NULL UNIQUE,
+    sequence INT NOT NULL DEFAULT 0,
+    invited_at TIMESTAMPTZ, -- when the first invite went out
+    cancel_reason TEXT,
+    cancelled_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (status <> 'SCHEDULED' OR starts_at IS NOT NULL)
+);
+
+CREATE INDEX idx_interviews_application ON interviews (application_id, created_at);
+
+-- The times a recruiter offered; the candidate picks one
+CREATE TABLE interview_slots (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    interview_id UUID NOT NULL REFERENCES interviews(id) ON DELETE CASCADE,
+    starts_at TIMESTAMPTZ NOT NULL,
+    UNIQUE (interview_id, starts_at)
+);
+
+CREATE TABLE interview_interviewers (
+    interview_id UUID NOT NULL REFERENCES interviews(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (interview_id, user_id)
+);
+
+-- Outgoing email, written in the same transaction as the change it reports
+-- and sent by the email sender worker with retries. calendar holds an
+-- iCalendar attachment, sent with calendar_method (REQUEST or CANCEL).
+CREATE TABLE email_outbox (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    to_address TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    body TEXT NOT NULL,
+    calendar TEXT,
+    calendar_method TEXT,
+    status TEXT NOT NULL DEFAULT 'PENDING'
+        CHECK (status IN ('PENDING', 'SENDING', 'SENT', 'FAILED')),
+    attempts INT NOT NULL DEFAULT 0,
+    run_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_at TIMESTAMPTZ,
+    last_error TEXT,
+    sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_email_outbox_pending ON email_outbox (run_at) WHERE status = 'PENDING';
@@ -10,22 +10,13 @@ import { useRouter } from "next/navigation";
 import { useAccount } from "wagmi";
 import { UploadButton } from "@uploadthing/react";
 import { OurFileRouter } from "@/app/api/uploadthing/core";
+import { parseResume } from "@/lib/parseResume";
 
 async function extractResumeData(fileUrl: string) {
   try {
-    const response = await fetch(`${process.env.NEXT_PUBLIC_API_URL}/parse-resume`, {
-      method: "POST",
-      headers: { "Content-Type": "application/json" },
-      body: JSON.stringify({ url: fileUrl }),
-    });
-    if (!response.ok) {
-        const err = await response.json();
-        console.error("AI Parser Error:", err);
-        return null;
-    }
-    return await response.json();
+    return await parseResume(fileUrl);
   } catch (error) {
-    console.error("Network Error during parsing:", error);
+    console.error("AI Parser Error:", error);
     return null;
   }
 }
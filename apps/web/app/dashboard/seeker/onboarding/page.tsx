@@ -6,21 +6,16 @@ import { UploadCloud, PenTool, CheckCircle, Loader2 } from "lucide-react";
 import { Button } from "@/components/ui/button";
 import { UploadButton } from "@uploadthing/react";
 import { OurFileRouter } from "@/app/api/uploadthing/core";
+import { parseResume } from "@/lib/parseResume";
 import { useRouter } from "next/navigation";
 import { useAccount } from "wagmi";
 
 // Helper to call the Backend AI Parser
 async function extractResumeData(fileUrl: string) {
   try {
-    const response = await fetch(`${process.env.NEXT_PUBLIC_API_URL}/parse-resume`, {
-      method: "POST",
-      headers: { "Content-Type": "application/json" },
-      body: JSON.stringify({ url: fileUrl }),
-    });
-    if (!response.ok) throw new Error("Parsing failed");
-    return await response.json();
+    return await parseResume(fileUrl);
   } catch (error) {
-    console.error(error);
+    console.error("AI Parser Error:", error);
     return null;
   }
 }
@@ -0,0 +1,34 @@
+const API_URL = process.env.NEXT_PUBLIC_API_URL;
+
+const POLL_INTERVAL_MS = 2000;
+const POLL_TIMEOUT_MS = 3 * 60 * 1000;
+
+// Queues a resume for parsing on the backend and polls until the job finishes.
+// Resolves with the parsed resume JSON, or throws if parsing failed.
+export async function parseResume(fileUrl: string) {
+  const response = await fetch(`${API_URL}/parse-resume`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify({ url: fileUrl }),
+  });
+  if (!response.ok) {
+    const err = await response.json().catch(() => ({}));
+    throw new Error(err.error || "Failed to queue resume");
+  }
+
+  const { job_id } = await response.json();
+  const deadline = Date.now() + POLL_TIMEOUT_MS;
+
+  while (Date.now() < deadline) {
+    await new Promise((resolve) => setTimeout(resolve, POLL_INTERVAL_MS));
+
+    const res = await fetch(`${API_URL}/parse-resume/${job_id}`);
+    if (!res.ok) continue;
+
+    const job = await res.json();
+    if (job.status === "SUCCEEDED") return job.result;
+    if (job.status === "FAILED") throw new Error(job.error || "Parsing failed");
+  }
+
+  throw new Error("Timed out waiting for resume parsing");
+}